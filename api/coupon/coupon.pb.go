@@ -15,6 +15,7 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -25,15 +26,322 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// CampaignStatus is the issuance state of a campaign
+type CampaignStatus int32
+
+const (
+	CampaignStatus_CAMPAIGN_STATUS_UNSPECIFIED CampaignStatus = 0
+	CampaignStatus_CAMPAIGN_STATUS_SCHEDULED   CampaignStatus = 1
+	CampaignStatus_CAMPAIGN_STATUS_ACTIVE      CampaignStatus = 2
+	CampaignStatus_CAMPAIGN_STATUS_SOLD_OUT    CampaignStatus = 3
+	CampaignStatus_CAMPAIGN_STATUS_ENDED       CampaignStatus = 4
+	CampaignStatus_CAMPAIGN_STATUS_PAUSED      CampaignStatus = 5
+)
+
+// Enum value maps for CampaignStatus.
+var (
+	CampaignStatus_name = map[int32]string{
+		0: "CAMPAIGN_STATUS_UNSPECIFIED",
+		1: "CAMPAIGN_STATUS_SCHEDULED",
+		2: "CAMPAIGN_STATUS_ACTIVE",
+		3: "CAMPAIGN_STATUS_SOLD_OUT",
+		4: "CAMPAIGN_STATUS_ENDED",
+		5: "CAMPAIGN_STATUS_PAUSED",
+	}
+	CampaignStatus_value = map[string]int32{
+		"CAMPAIGN_STATUS_UNSPECIFIED": 0,
+		"CAMPAIGN_STATUS_SCHEDULED":   1,
+		"CAMPAIGN_STATUS_ACTIVE":      2,
+		"CAMPAIGN_STATUS_SOLD_OUT":    3,
+		"CAMPAIGN_STATUS_ENDED":       4,
+		"CAMPAIGN_STATUS_PAUSED":      5,
+	}
+)
+
+func (x CampaignStatus) Enum() *CampaignStatus {
+	p := new(CampaignStatus)
+	*p = x
+	return p
+}
+
+func (x CampaignStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CampaignStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_coupon_coupon_proto_enumTypes[0].Descriptor()
+}
+
+func (CampaignStatus) Type() protoreflect.EnumType {
+	return &file_api_coupon_coupon_proto_enumTypes[0]
+}
+
+func (x CampaignStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CampaignStatus.Descriptor instead.
+func (CampaignStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{0}
+}
+
+// CodeMode selects how coupon codes are generated for a campaign
+type CodeMode int32
+
+const (
+	// CODE_MODE_UNSPECIFIED generates random codes
+	CodeMode_CODE_MODE_UNSPECIFIED CodeMode = 0
+	// CODE_MODE_RANDOM draws each code at random and retries on collisions
+	CodeMode_CODE_MODE_RANDOM CodeMode = 1
+	// CODE_MODE_PERMUTED maps each issue serial through a keyed permutation,
+	// so codes never collide within the campaign
+	CodeMode_CODE_MODE_PERMUTED CodeMode = 2
+	// CODE_MODE_SIGNED embeds a truncated HMAC over the campaign and issue
+	// serial, so points of sale holding the signing keys can check codes offline
+	CodeMode_CODE_MODE_SIGNED CodeMode = 3
+	// CODE_MODE_IMPORTED hands out codes uploaded with ImportCodes
+	CodeMode_CODE_MODE_IMPORTED CodeMode = 4
+)
+
+// Enum value maps for CodeMode.
+var (
+	CodeMode_name = map[int32]string{
+		0: "CODE_MODE_UNSPECIFIED",
+		1: "CODE_MODE_RANDOM",
+		2: "CODE_MODE_PERMUTED",
+		3: "CODE_MODE_SIGNED",
+		4: "CODE_MODE_IMPORTED",
+	}
+	CodeMode_value = map[string]int32{
+		"CODE_MODE_UNSPECIFIED": 0,
+		"CODE_MODE_RANDOM":      1,
+		"CODE_MODE_PERMUTED":    2,
+		"CODE_MODE_SIGNED":      3,
+		"CODE_MODE_IMPORTED":    4,
+	}
+)
+
+func (x CodeMode) Enum() *CodeMode {
+	p := new(CodeMode)
+	*p = x
+	return p
+}
+
+func (x CodeMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CodeMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_coupon_coupon_proto_enumTypes[1].Descriptor()
+}
+
+func (CodeMode) Type() protoreflect.EnumType {
+	return &file_api_coupon_coupon_proto_enumTypes[1]
+}
+
+func (x CodeMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CodeMode.Descriptor instead.
+func (CodeMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{1}
+}
+
+// CodeAlphabet selects the symbols of templated coupon codes
+type CodeAlphabet int32
+
+const (
+	// CODE_ALPHABET_UNSPECIFIED means Hangul
+	CodeAlphabet_CODE_ALPHABET_UNSPECIFIED CodeAlphabet = 0
+	// CODE_ALPHABET_HANGUL is Korean syllables and digits, starting with a syllable
+	CodeAlphabet_CODE_ALPHABET_HANGUL CodeAlphabet = 1
+	// CODE_ALPHABET_LATIN is upper-case Latin letters and digits without I, O, 0 and 1
+	CodeAlphabet_CODE_ALPHABET_LATIN CodeAlphabet = 2
+	// CODE_ALPHABET_DIGITS is decimal digits only
+	CodeAlphabet_CODE_ALPHABET_DIGITS CodeAlphabet = 3
+)
+
+// Enum value maps for CodeAlphabet.
+var (
+	CodeAlphabet_name = map[int32]string{
+		0: "CODE_ALPHABET_UNSPECIFIED",
+		1: "CODE_ALPHABET_HANGUL",
+		2: "CODE_ALPHABET_LATIN",
+		3: "CODE_ALPHABET_DIGITS",
+	}
+	CodeAlphabet_value = map[string]int32{
+		"CODE_ALPHABET_UNSPECIFIED": 0,
+		"CODE_ALPHABET_HANGUL":      1,
+		"CODE_ALPHABET_LATIN":       2,
+		"CODE_ALPHABET_DIGITS":      3,
+	}
+)
+
+func (x CodeAlphabet) Enum() *CodeAlphabet {
+	p := new(CodeAlphabet)
+	*p = x
+	return p
+}
+
+func (x CodeAlphabet) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CodeAlphabet) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_coupon_coupon_proto_enumTypes[2].Descriptor()
+}
+
+func (CodeAlphabet) Type() protoreflect.EnumType {
+	return &file_api_coupon_coupon_proto_enumTypes[2]
+}
+
+func (x CodeAlphabet) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CodeAlphabet.Descriptor instead.
+func (CodeAlphabet) EnumDescriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{2}
+}
+
+// CouponStatus is the lifecycle state of a coupon
+type CouponStatus int32
+
+const (
+	CouponStatus_COUPON_STATUS_UNSPECIFIED CouponStatus = 0
+	CouponStatus_COUPON_STATUS_ISSUED      CouponStatus = 1
+	CouponStatus_COUPON_STATUS_REDEEMED    CouponStatus = 2
+	CouponStatus_COUPON_STATUS_EXPIRED     CouponStatus = 3
+	CouponStatus_COUPON_STATUS_REVOKED     CouponStatus = 4
+)
+
+// Enum value maps for CouponStatus.
+var (
+	CouponStatus_name = map[int32]string{
+		0: "COUPON_STATUS_UNSPECIFIED",
+		1: "COUPON_STATUS_ISSUED",
+		2: "COUPON_STATUS_REDEEMED",
+		3: "COUPON_STATUS_EXPIRED",
+		4: "COUPON_STATUS_REVOKED",
+	}
+	CouponStatus_value = map[string]int32{
+		"COUPON_STATUS_UNSPECIFIED": 0,
+		"COUPON_STATUS_ISSUED":      1,
+		"COUPON_STATUS_REDEEMED":    2,
+		"COUPON_STATUS_EXPIRED":     3,
+		"COUPON_STATUS_REVOKED":     4,
+	}
+)
+
+func (x CouponStatus) Enum() *CouponStatus {
+	p := new(CouponStatus)
+	*p = x
+	return p
+}
+
+func (x CouponStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CouponStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_coupon_coupon_proto_enumTypes[3].Descriptor()
+}
+
+func (CouponStatus) Type() protoreflect.EnumType {
+	return &file_api_coupon_coupon_proto_enumTypes[3]
+}
+
+func (x CouponStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CouponStatus.Descriptor instead.
+func (CouponStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{3}
+}
+
+// CouponInvalidReason explains why a coupon code cannot be used
+type CouponInvalidReason int32
+
+const (
+	CouponInvalidReason_COUPON_INVALID_REASON_UNSPECIFIED  CouponInvalidReason = 0
+	CouponInvalidReason_COUPON_INVALID_REASON_UNKNOWN_CODE CouponInvalidReason = 1
+	CouponInvalidReason_COUPON_INVALID_REASON_REDEEMED     CouponInvalidReason = 2
+	CouponInvalidReason_COUPON_INVALID_REASON_EXPIRED      CouponInvalidReason = 3
+	CouponInvalidReason_COUPON_INVALID_REASON_REVOKED      CouponInvalidReason = 4
+	// The coupon's campaign was deleted; its coupons are deleted right after
+	CouponInvalidReason_COUPON_INVALID_REASON_CAMPAIGN_DELETED CouponInvalidReason = 5
+	// The code's check character does not match, so it was mistyped
+	CouponInvalidReason_COUPON_INVALID_REASON_MALFORMED_CODE CouponInvalidReason = 6
+)
+
+// Enum value maps for CouponInvalidReason.
+var (
+	CouponInvalidReason_name = map[int32]string{
+		0: "COUPON_INVALID_REASON_UNSPECIFIED",
+		1: "COUPON_INVALID_REASON_UNKNOWN_CODE",
+		2: "COUPON_INVALID_REASON_REDEEMED",
+		3: "COUPON_INVALID_REASON_EXPIRED",
+		4: "COUPON_INVALID_REASON_REVOKED",
+		5: "COUPON_INVALID_REASON_CAMPAIGN_DELETED",
+		6: "COUPON_INVALID_REASON_MALFORMED_CODE",
+	}
+	CouponInvalidReason_value = map[string]int32{
+		"COUPON_INVALID_REASON_UNSPECIFIED":      0,
+		"COUPON_INVALID_REASON_UNKNOWN_CODE":     1,
+		"COUPON_INVALID_REASON_REDEEMED":         2,
+		"COUPON_INVALID_REASON_EXPIRED":          3,
+		"COUPON_INVALID_REASON_REVOKED":          4,
+		"COUPON_INVALID_REASON_CAMPAIGN_DELETED": 5,
+		"COUPON_INVALID_REASON_MALFORMED_CODE":   6,
+	}
+)
+
+func (x CouponInvalidReason) Enum() *CouponInvalidReason {
+	p := new(CouponInvalidReason)
+	*p = x
+	return p
+}
+
+func (x CouponInvalidReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CouponInvalidReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_coupon_coupon_proto_enumTypes[4].Descriptor()
+}
+
+func (CouponInvalidReason) Type() protoreflect.EnumType {
+	return &file_api_coupon_coupon_proto_enumTypes[4]
+}
+
+func (x CouponInvalidReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CouponInvalidReason.Descriptor instead.
+func (CouponInvalidReason) EnumDescriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{4}
+}
+
 // Campaign represents a coupon campaign
 type Campaign struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	TotalCoupons  int32                  `protobuf:"varint,3,opt,name=total_coupons,json=totalCoupons,proto3" json:"total_coupons,omitempty"`
-	IssuedCoupons int32                  `protobuf:"varint,4,opt,name=issued_coupons,json=issuedCoupons,proto3" json:"issued_coupons,omitempty"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TotalCoupons      int32                  `protobuf:"varint,3,opt,name=total_coupons,json=totalCoupons,proto3" json:"total_coupons,omitempty"`
+	IssuedCoupons     int32                  `protobuf:"varint,4,opt,name=issued_coupons,json=issuedCoupons,proto3" json:"issued_coupons,omitempty"`
+	StartTime         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MaxCouponsPerUser int32                  `protobuf:"varint,7,opt,name=max_coupons_per_user,json=maxCouponsPerUser,proto3" json:"max_coupons_per_user,omitempty"`
+	EndTime           *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Status            CampaignStatus         `protobuf:"varint,9,opt,name=status,proto3,enum=coupon.v1.CampaignStatus" json:"status,omitempty"`
+	// version increases every time the campaign settings change
+	Version  int64    `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	CodeMode CodeMode `protobuf:"varint,11,opt,name=code_mode,json=codeMode,proto3,enum=coupon.v1.CodeMode" json:"code_mode,omitempty"`
+	// code_template is unset for campaigns using the default code format
+	CodeTemplate  *CodeTemplate `protobuf:"bytes,12,opt,name=code_template,json=codeTemplate,proto3" json:"code_template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -110,19 +418,139 @@ func (x *Campaign) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Campaign) GetMaxCouponsPerUser() int32 {
+	if x != nil {
+		return x.MaxCouponsPerUser
+	}
+	return 0
+}
+
+func (x *Campaign) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *Campaign) GetStatus() CampaignStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CampaignStatus_CAMPAIGN_STATUS_UNSPECIFIED
+}
+
+func (x *Campaign) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Campaign) GetCodeMode() CodeMode {
+	if x != nil {
+		return x.CodeMode
+	}
+	return CodeMode_CODE_MODE_UNSPECIFIED
+}
+
+func (x *Campaign) GetCodeTemplate() *CodeTemplate {
+	if x != nil {
+		return x.CodeTemplate
+	}
+	return nil
+}
+
+// CodeTemplate describes what a campaign's coupon codes look like, for example
+// prefix "SUMMER-", latin alphabet, groups [4, 4] and separator "-" give
+// codes like SUMMER-K7QX-M2PA. The last symbol is always a check character.
+type CodeTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// prefix is put in front of every code (at most 16 characters)
+	Prefix   string       `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Alphabet CodeAlphabet `protobuf:"varint,2,opt,name=alphabet,proto3,enum=coupon.v1.CodeAlphabet" json:"alphabet,omitempty"`
+	// groups are the sizes of the symbol groups, check character included
+	// (2 to 32 symbols in all); empty means a single group of 11 symbols
+	Groups []int32 `protobuf:"varint,3,rep,packed,name=groups,proto3" json:"groups,omitempty"`
+	// separator is put between groups (at most 3 characters)
+	Separator     string `protobuf:"bytes,4,opt,name=separator,proto3" json:"separator,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CodeTemplate) Reset() {
+	*x = CodeTemplate{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeTemplate) ProtoMessage() {}
+
+func (x *CodeTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeTemplate.ProtoReflect.Descriptor instead.
+func (*CodeTemplate) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CodeTemplate) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *CodeTemplate) GetAlphabet() CodeAlphabet {
+	if x != nil {
+		return x.Alphabet
+	}
+	return CodeAlphabet_CODE_ALPHABET_UNSPECIFIED
+}
+
+func (x *CodeTemplate) GetGroups() []int32 {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *CodeTemplate) GetSeparator() string {
+	if x != nil {
+		return x.Separator
+	}
+	return ""
+}
+
 // Coupon represents an issued coupon
 type Coupon struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	CampaignId    string                 `protobuf:"bytes,2,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
 	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        CouponStatus           `protobuf:"varint,5,opt,name=status,proto3,enum=coupon.v1.CouponStatus" json:"status,omitempty"`
+	RedeemedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=redeemed_at,json=redeemedAt,proto3" json:"redeemed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Coupon) Reset() {
 	*x = Coupon{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[1]
+	mi := &file_api_coupon_coupon_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +562,7 @@ func (x *Coupon) String() string {
 func (*Coupon) ProtoMessage() {}
 
 func (x *Coupon) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[1]
+	mi := &file_api_coupon_coupon_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +575,7 @@ func (x *Coupon) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
 func (*Coupon) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{1}
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Coupon) GetCode() string {
@@ -164,38 +592,1191 @@ func (x *Coupon) GetCampaignId() string {
 	return ""
 }
 
-func (x *Coupon) GetIssuedAt() *timestamppb.Timestamp {
+func (x *Coupon) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *Coupon) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Coupon) GetStatus() CouponStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CouponStatus_COUPON_STATUS_UNSPECIFIED
+}
+
+func (x *Coupon) GetRedeemedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RedeemedAt
+	}
+	return nil
+}
+
+// CreateCampaignRequest is the request for creating a new campaign
+type CreateCampaignRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TotalCoupons int32                  `protobuf:"varint,2,opt,name=total_coupons,json=totalCoupons,proto3" json:"total_coupons,omitempty"`
+	StartTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	// max_coupons_per_user limits how many coupons a single user can receive (0 means unlimited)
+	MaxCouponsPerUser int32 `protobuf:"varint,4,opt,name=max_coupons_per_user,json=maxCouponsPerUser,proto3" json:"max_coupons_per_user,omitempty"`
+	// end_time optionally closes the campaign; no coupons are issued after it
+	EndTime  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	CodeMode CodeMode               `protobuf:"varint,6,opt,name=code_mode,json=codeMode,proto3,enum=coupon.v1.CodeMode" json:"code_mode,omitempty"`
+	// code_template optionally changes the code format; the template must have
+	// enough codes for total_coupons and cannot be combined with signed codes
+	CodeTemplate  *CodeTemplate `protobuf:"bytes,7,opt,name=code_template,json=codeTemplate,proto3" json:"code_template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCampaignRequest) Reset() {
+	*x = CreateCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCampaignRequest) ProtoMessage() {}
+
+func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCampaignRequest.ProtoReflect.Descriptor instead.
+func (*CreateCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateCampaignRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCampaignRequest) GetTotalCoupons() int32 {
+	if x != nil {
+		return x.TotalCoupons
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetMaxCouponsPerUser() int32 {
+	if x != nil {
+		return x.MaxCouponsPerUser
+	}
+	return 0
+}
+
+func (x *CreateCampaignRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *CreateCampaignRequest) GetCodeMode() CodeMode {
+	if x != nil {
+		return x.CodeMode
+	}
+	return CodeMode_CODE_MODE_UNSPECIFIED
+}
+
+func (x *CreateCampaignRequest) GetCodeTemplate() *CodeTemplate {
+	if x != nil {
+		return x.CodeTemplate
+	}
+	return nil
+}
+
+// CreateCampaignResponse is the response for creating a new campaign
+type CreateCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCampaignResponse) Reset() {
+	*x = CreateCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCampaignResponse) ProtoMessage() {}
+
+func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCampaignResponse.ProtoReflect.Descriptor instead.
+func (*CreateCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+// GetCampaignRequest is the request for getting campaign information
+type GetCampaignRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// user_id optionally restricts the returned coupons to those issued to this user
+	UserId string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// omit_coupons skips loading coupons; use ListCoupons to page through them instead
+	OmitCoupons   bool `protobuf:"varint,3,opt,name=omit_coupons,json=omitCoupons,proto3" json:"omit_coupons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignRequest) Reset() {
+	*x = GetCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignRequest) ProtoMessage() {}
+
+func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
+func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCampaignRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *GetCampaignRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetCampaignRequest) GetOmitCoupons() bool {
+	if x != nil {
+		return x.OmitCoupons
+	}
+	return false
+}
+
+// GetCampaignResponse is the response for getting campaign information
+type GetCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	Coupons       []*Coupon              `protobuf:"bytes,2,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCampaignResponse) Reset() {
+	*x = GetCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCampaignResponse) ProtoMessage() {}
+
+func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
+func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *GetCampaignResponse) GetCoupons() []*Coupon {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+// IssueCouponRequest is the request for issuing a coupon
+type IssueCouponRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// user_id identifies the customer receiving the coupon
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCouponRequest) Reset() {
+	*x = IssueCouponRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCouponRequest) ProtoMessage() {}
+
+func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCouponRequest.ProtoReflect.Descriptor instead.
+func (*IssueCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *IssueCouponRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *IssueCouponRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// IssueCouponResponse is the response for issuing a coupon
+type IssueCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Coupon        *Coupon                `protobuf:"bytes,2,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueCouponResponse) Reset() {
+	*x = IssueCouponResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCouponResponse) ProtoMessage() {}
+
+func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCouponResponse.ProtoReflect.Descriptor instead.
+func (*IssueCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IssueCouponResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *IssueCouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+func (x *IssueCouponResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// DeleteCampaignRequest is the request for deleting a campaign
+type DeleteCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CampaignName  string                 `protobuf:"bytes,2,opt,name=campaign_name,json=campaignName,proto3" json:"campaign_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCampaignRequest) Reset() {
+	*x = DeleteCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCampaignRequest) ProtoMessage() {}
+
+func (x *DeleteCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCampaignRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteCampaignRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *DeleteCampaignRequest) GetCampaignName() string {
+	if x != nil {
+		return x.CampaignName
+	}
+	return ""
+}
+
+// DeleteCampaignResponse is the response for deleting a campaign
+type DeleteCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCampaignResponse) Reset() {
+	*x = DeleteCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCampaignResponse) ProtoMessage() {}
+
+func (x *DeleteCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCampaignResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteCampaignResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteCampaignResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// RedeemCouponRequest is the request for redeeming a coupon
+type RedeemCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemCouponRequest) Reset() {
+	*x = RedeemCouponRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemCouponRequest) ProtoMessage() {}
+
+func (x *RedeemCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemCouponRequest.ProtoReflect.Descriptor instead.
+func (*RedeemCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RedeemCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// RedeemCouponResponse is the response for redeeming a coupon
+type RedeemCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupon        *Coupon                `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemCouponResponse) Reset() {
+	*x = RedeemCouponResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemCouponResponse) ProtoMessage() {}
+
+func (x *RedeemCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemCouponResponse.ProtoReflect.Descriptor instead.
+func (*RedeemCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RedeemCouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+// ValidateCouponRequest is the request for validating a coupon code
+type ValidateCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCouponRequest) Reset() {
+	*x = ValidateCouponRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCouponRequest) ProtoMessage() {}
+
+func (x *ValidateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCouponRequest.ProtoReflect.Descriptor instead.
+func (*ValidateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ValidateCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// ValidateCouponResponse is the response for validating a coupon code
+type ValidateCouponResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// reason is set when valid is false
+	Reason        CouponInvalidReason `protobuf:"varint,2,opt,name=reason,proto3,enum=coupon.v1.CouponInvalidReason" json:"reason,omitempty"`
+	Coupon        *Coupon             `protobuf:"bytes,3,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	Campaign      *Campaign           `protobuf:"bytes,4,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCouponResponse) Reset() {
+	*x = ValidateCouponResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCouponResponse) ProtoMessage() {}
+
+func (x *ValidateCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCouponResponse.ProtoReflect.Descriptor instead.
+func (*ValidateCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ValidateCouponResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateCouponResponse) GetReason() CouponInvalidReason {
+	if x != nil {
+		return x.Reason
+	}
+	return CouponInvalidReason_COUPON_INVALID_REASON_UNSPECIFIED
+}
+
+func (x *ValidateCouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+func (x *ValidateCouponResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+// ListCampaignsRequest is the request for listing campaigns
+type ListCampaignsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page_size is the maximum number of campaigns to return (defaults to 50)
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of a previous response
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// status, name_prefix and the created_at range are optional filters
+	Status        CampaignStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=coupon.v1.CampaignStatus" json:"status,omitempty"`
+	NamePrefix    string                 `protobuf:"bytes,4,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCampaignsRequest) Reset() {
+	*x = ListCampaignsRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCampaignsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCampaignsRequest) ProtoMessage() {}
+
+func (x *ListCampaignsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCampaignsRequest.ProtoReflect.Descriptor instead.
+func (*ListCampaignsRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListCampaignsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCampaignsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListCampaignsRequest) GetStatus() CampaignStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CampaignStatus_CAMPAIGN_STATUS_UNSPECIFIED
+}
+
+func (x *ListCampaignsRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ListCampaignsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ListCampaignsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+// ListCampaignsResponse is the response for listing campaigns
+type ListCampaignsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Campaigns []*Campaign            `protobuf:"bytes,1,rep,name=campaigns,proto3" json:"campaigns,omitempty"`
+	// next_page_token is empty when there are no more campaigns
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCampaignsResponse) Reset() {
+	*x = ListCampaignsResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCampaignsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCampaignsResponse) ProtoMessage() {}
+
+func (x *ListCampaignsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCampaignsResponse.ProtoReflect.Descriptor instead.
+func (*ListCampaignsResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListCampaignsResponse) GetCampaigns() []*Campaign {
+	if x != nil {
+		return x.Campaigns
+	}
+	return nil
+}
+
+func (x *ListCampaignsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ListCouponsRequest is the request for listing a campaign's coupons
+type ListCouponsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// page_size is the maximum number of coupons to return (defaults to 50)
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of a previous response
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsRequest) Reset() {
+	*x = ListCouponsRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsRequest) ProtoMessage() {}
+
+func (x *ListCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsRequest.ProtoReflect.Descriptor instead.
+func (*ListCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListCouponsRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+func (x *ListCouponsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListCouponsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// ListCouponsResponse is the response for listing a campaign's coupons
+type ListCouponsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Coupons []*Coupon              `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	// next_page_token is empty when there are no more coupons
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsResponse) Reset() {
+	*x = ListCouponsResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsResponse) ProtoMessage() {}
+
+func (x *ListCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsResponse.ProtoReflect.Descriptor instead.
+func (*ListCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListCouponsResponse) GetCoupons() []*Coupon {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+func (x *ListCouponsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// UpdateCampaignRequest is the request for updating a campaign
+type UpdateCampaignRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// campaign carries the ID, the new values for the fields in update_mask and,
+	// optionally, the version the change is based on (0 skips the version check)
+	Campaign *Campaign `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	// update_mask lists the fields to change: name, start_time and total_coupons
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCampaignRequest) Reset() {
+	*x = UpdateCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCampaignRequest) ProtoMessage() {}
+
+func (x *UpdateCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCampaignRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateCampaignRequest) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+func (x *UpdateCampaignRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// UpdateCampaignResponse is the response for updating a campaign
+type UpdateCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCampaignResponse) Reset() {
+	*x = UpdateCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCampaignResponse) ProtoMessage() {}
+
+func (x *UpdateCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCampaignResponse.ProtoReflect.Descriptor instead.
+func (*UpdateCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateCampaignResponse) GetCampaign() *Campaign {
+	if x != nil {
+		return x.Campaign
+	}
+	return nil
+}
+
+// PauseCampaignRequest is the request for pausing a campaign
+type PauseCampaignRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseCampaignRequest) Reset() {
+	*x = PauseCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseCampaignRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseCampaignRequest) ProtoMessage() {}
+
+func (x *PauseCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseCampaignRequest.ProtoReflect.Descriptor instead.
+func (*PauseCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PauseCampaignRequest) GetCampaignId() string {
+	if x != nil {
+		return x.CampaignId
+	}
+	return ""
+}
+
+// PauseCampaignResponse is the response for pausing a campaign
+type PauseCampaignResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseCampaignResponse) Reset() {
+	*x = PauseCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseCampaignResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseCampaignResponse) ProtoMessage() {}
+
+func (x *PauseCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseCampaignResponse.ProtoReflect.Descriptor instead.
+func (*PauseCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PauseCampaignResponse) GetCampaign() *Campaign {
 	if x != nil {
-		return x.IssuedAt
+		return x.Campaign
 	}
 	return nil
 }
 
-// CreateCampaignRequest is the request for creating a new campaign
-type CreateCampaignRequest struct {
+// ResumeCampaignRequest is the request for resuming a paused campaign
+type ResumeCampaignRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	TotalCoupons  int32                  `protobuf:"varint,2,opt,name=total_coupons,json=totalCoupons,proto3" json:"total_coupons,omitempty"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCampaignRequest) Reset() {
-	*x = CreateCampaignRequest{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[2]
+func (x *ResumeCampaignRequest) Reset() {
+	*x = ResumeCampaignRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCampaignRequest) String() string {
+func (x *ResumeCampaignRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCampaignRequest) ProtoMessage() {}
+func (*ResumeCampaignRequest) ProtoMessage() {}
 
-func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[2]
+func (x *ResumeCampaignRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -206,55 +1787,41 @@ func (x *CreateCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCampaignRequest.ProtoReflect.Descriptor instead.
-func (*CreateCampaignRequest) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ResumeCampaignRequest.ProtoReflect.Descriptor instead.
+func (*ResumeCampaignRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *CreateCampaignRequest) GetName() string {
+func (x *ResumeCampaignRequest) GetCampaignId() string {
 	if x != nil {
-		return x.Name
+		return x.CampaignId
 	}
 	return ""
 }
 
-func (x *CreateCampaignRequest) GetTotalCoupons() int32 {
-	if x != nil {
-		return x.TotalCoupons
-	}
-	return 0
-}
-
-func (x *CreateCampaignRequest) GetStartTime() *timestamppb.Timestamp {
-	if x != nil {
-		return x.StartTime
-	}
-	return nil
-}
-
-// CreateCampaignResponse is the response for creating a new campaign
-type CreateCampaignResponse struct {
+// ResumeCampaignResponse is the response for resuming a paused campaign
+type ResumeCampaignResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCampaignResponse) Reset() {
-	*x = CreateCampaignResponse{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[3]
+func (x *ResumeCampaignResponse) Reset() {
+	*x = ResumeCampaignResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCampaignResponse) String() string {
+func (x *ResumeCampaignResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCampaignResponse) ProtoMessage() {}
+func (*ResumeCampaignResponse) ProtoMessage() {}
 
-func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[3]
+func (x *ResumeCampaignResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -265,41 +1832,45 @@ func (x *CreateCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCampaignResponse.ProtoReflect.Descriptor instead.
-func (*CreateCampaignResponse) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ResumeCampaignResponse.ProtoReflect.Descriptor instead.
+func (*ResumeCampaignResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *CreateCampaignResponse) GetCampaign() *Campaign {
+func (x *ResumeCampaignResponse) GetCampaign() *Campaign {
 	if x != nil {
 		return x.Campaign
 	}
 	return nil
 }
 
-// GetCampaignRequest is the request for getting campaign information
-type GetCampaignRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+// ImportCodesRequest is one batch of codes to import. Each batch is imported
+// as a unit, so batches received before a failure stay imported.
+type ImportCodesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// campaign_id is required in the first message; later messages may repeat it
+	CampaignId string `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	// codes holds at most 10000 codes
+	Codes         []string `protobuf:"bytes,2,rep,name=codes,proto3" json:"codes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCampaignRequest) Reset() {
-	*x = GetCampaignRequest{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[4]
+func (x *ImportCodesRequest) Reset() {
+	*x = ImportCodesRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignRequest) String() string {
+func (x *ImportCodesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignRequest) ProtoMessage() {}
+func (*ImportCodesRequest) ProtoMessage() {}
 
-func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[4]
+func (x *ImportCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -310,42 +1881,56 @@ func (x *GetCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignRequest.ProtoReflect.Descriptor instead.
-func (*GetCampaignRequest) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ImportCodesRequest.ProtoReflect.Descriptor instead.
+func (*ImportCodesRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *GetCampaignRequest) GetCampaignId() string {
+func (x *ImportCodesRequest) GetCampaignId() string {
 	if x != nil {
 		return x.CampaignId
 	}
 	return ""
 }
 
-// GetCampaignResponse is the response for getting campaign information
-type GetCampaignResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Campaign      *Campaign              `protobuf:"bytes,1,opt,name=campaign,proto3" json:"campaign,omitempty"`
-	Coupons       []*Coupon              `protobuf:"bytes,2,rep,name=coupons,proto3" json:"coupons,omitempty"`
+func (x *ImportCodesRequest) GetCodes() []string {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+// ImportCodesResponse is the response for importing codes
+type ImportCodesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// imported is the number of codes added to the pool
+	Imported int32 `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	// duplicates is the number of codes skipped because they were already
+	// pooled, taken by a coupon or repeated in the upload
+	Duplicates int32 `protobuf:"varint,2,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	// duplicate_codes lists the first 100 skipped codes
+	DuplicateCodes []string `protobuf:"bytes,3,rep,name=duplicate_codes,json=duplicateCodes,proto3" json:"duplicate_codes,omitempty"`
+	// available is the number of codes in the pool after the import
+	Available     int32 `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCampaignResponse) Reset() {
-	*x = GetCampaignResponse{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[5]
+func (x *ImportCodesResponse) Reset() {
+	*x = ImportCodesResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCampaignResponse) String() string {
+func (x *ImportCodesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCampaignResponse) ProtoMessage() {}
+func (*ImportCodesResponse) ProtoMessage() {}
 
-func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[5]
+func (x *ImportCodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -356,48 +1941,64 @@ func (x *GetCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCampaignResponse.ProtoReflect.Descriptor instead.
-func (*GetCampaignResponse) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ImportCodesResponse.ProtoReflect.Descriptor instead.
+func (*ImportCodesResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *GetCampaignResponse) GetCampaign() *Campaign {
+func (x *ImportCodesResponse) GetImported() int32 {
 	if x != nil {
-		return x.Campaign
+		return x.Imported
 	}
-	return nil
+	return 0
 }
 
-func (x *GetCampaignResponse) GetCoupons() []*Coupon {
+func (x *ImportCodesResponse) GetDuplicates() int32 {
 	if x != nil {
-		return x.Coupons
+		return x.Duplicates
+	}
+	return 0
+}
+
+func (x *ImportCodesResponse) GetDuplicateCodes() []string {
+	if x != nil {
+		return x.DuplicateCodes
 	}
 	return nil
 }
 
-// IssueCouponRequest is the request for issuing a coupon
-type IssueCouponRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+func (x *ImportCodesResponse) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+// ReconcileCountersRequest is the request for a counter reconciliation pass
+type ReconcileCountersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// repair releases leaked slots, but only for drift that the previous pass
+	// found with the same values
+	Repair        bool `protobuf:"varint,1,opt,name=repair,proto3" json:"repair,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IssueCouponRequest) Reset() {
-	*x = IssueCouponRequest{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[6]
+func (x *ReconcileCountersRequest) Reset() {
+	*x = ReconcileCountersRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IssueCouponRequest) String() string {
+func (x *ReconcileCountersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IssueCouponRequest) ProtoMessage() {}
+func (*ReconcileCountersRequest) ProtoMessage() {}
 
-func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[6]
+func (x *ReconcileCountersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,43 +2009,51 @@ func (x *IssueCouponRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IssueCouponRequest.ProtoReflect.Descriptor instead.
-func (*IssueCouponRequest) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ReconcileCountersRequest.ProtoReflect.Descriptor instead.
+func (*ReconcileCountersRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *IssueCouponRequest) GetCampaignId() string {
+func (x *ReconcileCountersRequest) GetRepair() bool {
 	if x != nil {
-		return x.CampaignId
+		return x.Repair
 	}
-	return ""
+	return false
 }
 
-// IssueCouponResponse is the response for issuing a coupon
-type IssueCouponResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Coupon        *Coupon                `protobuf:"bytes,2,opt,name=coupon,proto3" json:"coupon,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+// CounterDrift describes a campaign whose counters disagree with its stored coupons
+type CounterDrift struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CampaignId   string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
+	CampaignName string                 `protobuf:"bytes,2,opt,name=campaign_name,json=campaignName,proto3" json:"campaign_name,omitempty"`
+	// issued_coupons is the campaign's issued counter
+	IssuedCoupons int32 `protobuf:"varint,3,opt,name=issued_coupons,json=issuedCoupons,proto3" json:"issued_coupons,omitempty"`
+	// stored_coupons is the number of coupons actually stored for the campaign
+	StoredCoupons int32 `protobuf:"varint,4,opt,name=stored_coupons,json=storedCoupons,proto3" json:"stored_coupons,omitempty"`
+	// user_drift maps user IDs to their counter minus their stored coupons;
+	// users whose counts agree are left out
+	UserDrift map[string]int32 `protobuf:"bytes,5,rep,name=user_drift,json=userDrift,proto3" json:"user_drift,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// repaired is set when the leaked slots were released during this pass
+	Repaired      bool `protobuf:"varint,6,opt,name=repaired,proto3" json:"repaired,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IssueCouponResponse) Reset() {
-	*x = IssueCouponResponse{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[7]
+func (x *CounterDrift) Reset() {
+	*x = CounterDrift{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IssueCouponResponse) String() string {
+func (x *CounterDrift) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IssueCouponResponse) ProtoMessage() {}
+func (*CounterDrift) ProtoMessage() {}
 
-func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[7]
+func (x *CounterDrift) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -455,56 +2064,80 @@ func (x *IssueCouponResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IssueCouponResponse.ProtoReflect.Descriptor instead.
-func (*IssueCouponResponse) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CounterDrift.ProtoReflect.Descriptor instead.
+func (*CounterDrift) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *IssueCouponResponse) GetSuccess() bool {
+func (x *CounterDrift) GetCampaignId() string {
 	if x != nil {
-		return x.Success
+		return x.CampaignId
 	}
-	return false
+	return ""
 }
 
-func (x *IssueCouponResponse) GetCoupon() *Coupon {
+func (x *CounterDrift) GetCampaignName() string {
 	if x != nil {
-		return x.Coupon
+		return x.CampaignName
+	}
+	return ""
+}
+
+func (x *CounterDrift) GetIssuedCoupons() int32 {
+	if x != nil {
+		return x.IssuedCoupons
+	}
+	return 0
+}
+
+func (x *CounterDrift) GetStoredCoupons() int32 {
+	if x != nil {
+		return x.StoredCoupons
+	}
+	return 0
+}
+
+func (x *CounterDrift) GetUserDrift() map[string]int32 {
+	if x != nil {
+		return x.UserDrift
 	}
 	return nil
 }
 
-func (x *IssueCouponResponse) GetError() string {
+func (x *CounterDrift) GetRepaired() bool {
 	if x != nil {
-		return x.Error
+		return x.Repaired
 	}
-	return ""
+	return false
 }
 
-// DeleteCampaignRequest is the request for deleting a campaign
-type DeleteCampaignRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CampaignId    string                 `protobuf:"bytes,1,opt,name=campaign_id,json=campaignId,proto3" json:"campaign_id,omitempty"`
-	CampaignName  string                 `protobuf:"bytes,2,opt,name=campaign_name,json=campaignName,proto3" json:"campaign_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ReconcileCountersResponse is the response for a counter reconciliation pass
+type ReconcileCountersResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	CheckedAt        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	CampaignsChecked int32                  `protobuf:"varint,2,opt,name=campaigns_checked,json=campaignsChecked,proto3" json:"campaigns_checked,omitempty"`
+	// campaigns_skipped counts campaigns that changed while being checked
+	CampaignsSkipped int32           `protobuf:"varint,3,opt,name=campaigns_skipped,json=campaignsSkipped,proto3" json:"campaigns_skipped,omitempty"`
+	Drifts           []*CounterDrift `protobuf:"bytes,4,rep,name=drifts,proto3" json:"drifts,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *DeleteCampaignRequest) Reset() {
-	*x = DeleteCampaignRequest{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[8]
+func (x *ReconcileCountersResponse) Reset() {
+	*x = ReconcileCountersResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteCampaignRequest) String() string {
+func (x *ReconcileCountersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteCampaignRequest) ProtoMessage() {}
+func (*ReconcileCountersResponse) ProtoMessage() {}
 
-func (x *DeleteCampaignRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[8]
+func (x *ReconcileCountersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -515,49 +2148,62 @@ func (x *DeleteCampaignRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteCampaignRequest.ProtoReflect.Descriptor instead.
-func (*DeleteCampaignRequest) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ReconcileCountersResponse.ProtoReflect.Descriptor instead.
+func (*ReconcileCountersResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *DeleteCampaignRequest) GetCampaignId() string {
+func (x *ReconcileCountersResponse) GetCheckedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CampaignId
+		return x.CheckedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *DeleteCampaignRequest) GetCampaignName() string {
+func (x *ReconcileCountersResponse) GetCampaignsChecked() int32 {
 	if x != nil {
-		return x.CampaignName
+		return x.CampaignsChecked
 	}
-	return ""
+	return 0
 }
 
-// DeleteCampaignResponse is the response for deleting a campaign
-type DeleteCampaignResponse struct {
+func (x *ReconcileCountersResponse) GetCampaignsSkipped() int32 {
+	if x != nil {
+		return x.CampaignsSkipped
+	}
+	return 0
+}
+
+func (x *ReconcileCountersResponse) GetDrifts() []*CounterDrift {
+	if x != nil {
+		return x.Drifts
+	}
+	return nil
+}
+
+// RevokeCouponRequest is the request for revoking a coupon
+type RevokeCouponRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteCampaignResponse) Reset() {
-	*x = DeleteCampaignResponse{}
-	mi := &file_api_coupon_coupon_proto_msgTypes[9]
+func (x *RevokeCouponRequest) Reset() {
+	*x = RevokeCouponRequest{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteCampaignResponse) String() string {
+func (x *RevokeCouponRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteCampaignResponse) ProtoMessage() {}
+func (*RevokeCouponRequest) ProtoMessage() {}
 
-func (x *DeleteCampaignResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_coupon_coupon_proto_msgTypes[9]
+func (x *RevokeCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,30 +2214,68 @@ func (x *DeleteCampaignResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteCampaignResponse.ProtoReflect.Descriptor instead.
-func (*DeleteCampaignResponse) Descriptor() ([]byte, []int) {
-	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use RevokeCouponRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *DeleteCampaignResponse) GetSuccess() bool {
+func (x *RevokeCouponRequest) GetCode() string {
 	if x != nil {
-		return x.Success
+		return x.Code
 	}
-	return false
+	return ""
 }
 
-func (x *DeleteCampaignResponse) GetMessage() string {
+// RevokeCouponResponse is the response for revoking a coupon
+type RevokeCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupon        *Coupon                `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCouponResponse) Reset() {
+	*x = RevokeCouponResponse{}
+	mi := &file_api_coupon_coupon_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCouponResponse) ProtoMessage() {}
+
+func (x *RevokeCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_coupon_coupon_proto_msgTypes[31]
 	if x != nil {
-		return x.Message
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCouponResponse.ProtoReflect.Descriptor instead.
+func (*RevokeCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_coupon_coupon_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RevokeCouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
 }
 
 var File_api_coupon_coupon_proto protoreflect.FileDescriptor
 
 const file_api_coupon_coupon_proto_rawDesc = "" +
 	"\n" +
-	"\x17api/coupon/coupon.proto\x12\tcoupon.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf0\x01\n" +
+	"\x17api/coupon/coupon.proto\x12\tcoupon.v1\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x04\n" +
 	"\bCampaign\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12#\n" +
@@ -600,28 +2284,51 @@ const file_api_coupon_coupon_proto_rawDesc = "" +
 	"\n" +
 	"start_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x129\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"v\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12/\n" +
+	"\x14max_coupons_per_user\x18\a \x01(\x05R\x11maxCouponsPerUser\x125\n" +
+	"\bend_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x121\n" +
+	"\x06status\x18\t \x01(\x0e2\x19.coupon.v1.CampaignStatusR\x06status\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\x03R\aversion\x120\n" +
+	"\tcode_mode\x18\v \x01(\x0e2\x13.coupon.v1.CodeModeR\bcodeMode\x12<\n" +
+	"\rcode_template\x18\f \x01(\v2\x17.coupon.v1.CodeTemplateR\fcodeTemplate\"\x91\x01\n" +
+	"\fCodeTemplate\x12\x16\n" +
+	"\x06prefix\x18\x01 \x01(\tR\x06prefix\x123\n" +
+	"\balphabet\x18\x02 \x01(\x0e2\x17.coupon.v1.CodeAlphabetR\balphabet\x12\x16\n" +
+	"\x06groups\x18\x03 \x03(\x05R\x06groups\x12\x1c\n" +
+	"\tseparator\x18\x04 \x01(\tR\tseparator\"\xfd\x01\n" +
 	"\x06Coupon\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
 	"\vcampaign_id\x18\x02 \x01(\tR\n" +
 	"campaignId\x127\n" +
-	"\tissued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\"\x8b\x01\n" +
+	"\tissued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12/\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x17.coupon.v1.CouponStatusR\x06status\x12;\n" +
+	"\vredeemed_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"redeemedAt\"\xe3\x02\n" +
 	"\x15CreateCampaignRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12#\n" +
 	"\rtotal_coupons\x18\x02 \x01(\x05R\ftotalCoupons\x129\n" +
 	"\n" +
-	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\"I\n" +
+	"start_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x12/\n" +
+	"\x14max_coupons_per_user\x18\x04 \x01(\x05R\x11maxCouponsPerUser\x125\n" +
+	"\bend_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x120\n" +
+	"\tcode_mode\x18\x06 \x01(\x0e2\x13.coupon.v1.CodeModeR\bcodeMode\x12<\n" +
+	"\rcode_template\x18\a \x01(\v2\x17.coupon.v1.CodeTemplateR\fcodeTemplate\"I\n" +
 	"\x16CreateCampaignResponse\x12/\n" +
-	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"5\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"q\n" +
 	"\x12GetCampaignRequest\x12\x1f\n" +
 	"\vcampaign_id\x18\x01 \x01(\tR\n" +
-	"campaignId\"s\n" +
+	"campaignId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\fomit_coupons\x18\x03 \x01(\bR\vomitCoupons\"s\n" +
 	"\x13GetCampaignResponse\x12/\n" +
 	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12+\n" +
-	"\acoupons\x18\x02 \x03(\v2\x11.coupon.v1.CouponR\acoupons\"5\n" +
+	"\acoupons\x18\x02 \x03(\v2\x11.coupon.v1.CouponR\acoupons\"N\n" +
 	"\x12IssueCouponRequest\x12\x1f\n" +
 	"\vcampaign_id\x18\x01 \x01(\tR\n" +
-	"campaignId\"p\n" +
+	"campaignId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"p\n" +
 	"\x13IssueCouponResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12)\n" +
 	"\x06coupon\x18\x02 \x01(\v2\x11.coupon.v1.CouponR\x06coupon\x12\x14\n" +
@@ -632,12 +2339,138 @@ const file_api_coupon_coupon_proto_rawDesc = "" +
 	"\rcampaign_name\x18\x02 \x01(\tR\fcampaignName\"L\n" +
 	"\x16DeleteCampaignResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xe1\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\")\n" +
+	"\x13RedeemCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"A\n" +
+	"\x14RedeemCouponResponse\x12)\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x11.coupon.v1.CouponR\x06coupon\"+\n" +
+	"\x15ValidateCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\xc2\x01\n" +
+	"\x16ValidateCouponResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x126\n" +
+	"\x06reason\x18\x02 \x01(\x0e2\x1e.coupon.v1.CouponInvalidReasonR\x06reason\x12)\n" +
+	"\x06coupon\x18\x03 \x01(\v2\x11.coupon.v1.CouponR\x06coupon\x12/\n" +
+	"\bcampaign\x18\x04 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"\xaa\x02\n" +
+	"\x14ListCampaignsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x121\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x19.coupon.v1.CampaignStatusR\x06status\x12\x1f\n" +
+	"\vname_prefix\x18\x04 \x01(\tR\n" +
+	"namePrefix\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\"r\n" +
+	"\x15ListCampaignsResponse\x121\n" +
+	"\tcampaigns\x18\x01 \x03(\v2\x13.coupon.v1.CampaignR\tcampaigns\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"q\n" +
+	"\x12ListCouponsRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"j\n" +
+	"\x13ListCouponsResponse\x12+\n" +
+	"\acoupons\x18\x01 \x03(\v2\x11.coupon.v1.CouponR\acoupons\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x85\x01\n" +
+	"\x15UpdateCampaignRequest\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"I\n" +
+	"\x16UpdateCampaignResponse\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"7\n" +
+	"\x14PauseCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\"H\n" +
+	"\x15PauseCampaignResponse\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"8\n" +
+	"\x15ResumeCampaignRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\"I\n" +
+	"\x16ResumeCampaignResponse\x12/\n" +
+	"\bcampaign\x18\x01 \x01(\v2\x13.coupon.v1.CampaignR\bcampaign\"K\n" +
+	"\x12ImportCodesRequest\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12\x14\n" +
+	"\x05codes\x18\x02 \x03(\tR\x05codes\"\x98\x01\n" +
+	"\x13ImportCodesResponse\x12\x1a\n" +
+	"\bimported\x18\x01 \x01(\x05R\bimported\x12\x1e\n" +
+	"\n" +
+	"duplicates\x18\x02 \x01(\x05R\n" +
+	"duplicates\x12'\n" +
+	"\x0fduplicate_codes\x18\x03 \x03(\tR\x0eduplicateCodes\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\x05R\tavailable\"2\n" +
+	"\x18ReconcileCountersRequest\x12\x16\n" +
+	"\x06repair\x18\x01 \x01(\bR\x06repair\"\xc3\x02\n" +
+	"\fCounterDrift\x12\x1f\n" +
+	"\vcampaign_id\x18\x01 \x01(\tR\n" +
+	"campaignId\x12#\n" +
+	"\rcampaign_name\x18\x02 \x01(\tR\fcampaignName\x12%\n" +
+	"\x0eissued_coupons\x18\x03 \x01(\x05R\rissuedCoupons\x12%\n" +
+	"\x0estored_coupons\x18\x04 \x01(\x05R\rstoredCoupons\x12E\n" +
+	"\n" +
+	"user_drift\x18\x05 \x03(\v2&.coupon.v1.CounterDrift.UserDriftEntryR\tuserDrift\x12\x1a\n" +
+	"\brepaired\x18\x06 \x01(\bR\brepaired\x1a<\n" +
+	"\x0eUserDriftEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xe1\x01\n" +
+	"\x19ReconcileCountersResponse\x129\n" +
+	"\n" +
+	"checked_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\x12+\n" +
+	"\x11campaigns_checked\x18\x02 \x01(\x05R\x10campaignsChecked\x12+\n" +
+	"\x11campaigns_skipped\x18\x03 \x01(\x05R\x10campaignsSkipped\x12/\n" +
+	"\x06drifts\x18\x04 \x03(\v2\x17.coupon.v1.CounterDriftR\x06drifts\")\n" +
+	"\x13RevokeCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"A\n" +
+	"\x14RevokeCouponResponse\x12)\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x11.coupon.v1.CouponR\x06coupon*\xc1\x01\n" +
+	"\x0eCampaignStatus\x12\x1f\n" +
+	"\x1bCAMPAIGN_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19CAMPAIGN_STATUS_SCHEDULED\x10\x01\x12\x1a\n" +
+	"\x16CAMPAIGN_STATUS_ACTIVE\x10\x02\x12\x1c\n" +
+	"\x18CAMPAIGN_STATUS_SOLD_OUT\x10\x03\x12\x19\n" +
+	"\x15CAMPAIGN_STATUS_ENDED\x10\x04\x12\x1a\n" +
+	"\x16CAMPAIGN_STATUS_PAUSED\x10\x05*\x81\x01\n" +
+	"\bCodeMode\x12\x19\n" +
+	"\x15CODE_MODE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10CODE_MODE_RANDOM\x10\x01\x12\x16\n" +
+	"\x12CODE_MODE_PERMUTED\x10\x02\x12\x14\n" +
+	"\x10CODE_MODE_SIGNED\x10\x03\x12\x16\n" +
+	"\x12CODE_MODE_IMPORTED\x10\x04*z\n" +
+	"\fCodeAlphabet\x12\x1d\n" +
+	"\x19CODE_ALPHABET_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14CODE_ALPHABET_HANGUL\x10\x01\x12\x17\n" +
+	"\x13CODE_ALPHABET_LATIN\x10\x02\x12\x18\n" +
+	"\x14CODE_ALPHABET_DIGITS\x10\x03*\x99\x01\n" +
+	"\fCouponStatus\x12\x1d\n" +
+	"\x19COUPON_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14COUPON_STATUS_ISSUED\x10\x01\x12\x1a\n" +
+	"\x16COUPON_STATUS_REDEEMED\x10\x02\x12\x19\n" +
+	"\x15COUPON_STATUS_EXPIRED\x10\x03\x12\x19\n" +
+	"\x15COUPON_STATUS_REVOKED\x10\x04*\xa4\x02\n" +
+	"\x13CouponInvalidReason\x12%\n" +
+	"!COUPON_INVALID_REASON_UNSPECIFIED\x10\x00\x12&\n" +
+	"\"COUPON_INVALID_REASON_UNKNOWN_CODE\x10\x01\x12\"\n" +
+	"\x1eCOUPON_INVALID_REASON_REDEEMED\x10\x02\x12!\n" +
+	"\x1dCOUPON_INVALID_REASON_EXPIRED\x10\x03\x12!\n" +
+	"\x1dCOUPON_INVALID_REASON_REVOKED\x10\x04\x12*\n" +
+	"&COUPON_INVALID_REASON_CAMPAIGN_DELETED\x10\x05\x12(\n" +
+	"$COUPON_INVALID_REASON_MALFORMED_CODE\x10\x062\x8d\b\n" +
 	"\rCouponService\x12W\n" +
 	"\x0eCreateCampaign\x12 .coupon.v1.CreateCampaignRequest\x1a!.coupon.v1.CreateCampaignResponse\"\x00\x12N\n" +
 	"\vGetCampaign\x12\x1d.coupon.v1.GetCampaignRequest\x1a\x1e.coupon.v1.GetCampaignResponse\"\x00\x12N\n" +
 	"\vIssueCoupon\x12\x1d.coupon.v1.IssueCouponRequest\x1a\x1e.coupon.v1.IssueCouponResponse\"\x00\x12W\n" +
-	"\x0eDeleteCampaign\x12 .coupon.v1.DeleteCampaignRequest\x1a!.coupon.v1.DeleteCampaignResponse\"\x00B@Z>github.com/rpranjan11/coupon-issuance-system/api/coupon;couponb\x06proto3"
+	"\x0eDeleteCampaign\x12 .coupon.v1.DeleteCampaignRequest\x1a!.coupon.v1.DeleteCampaignResponse\"\x00\x12Q\n" +
+	"\fRedeemCoupon\x12\x1e.coupon.v1.RedeemCouponRequest\x1a\x1f.coupon.v1.RedeemCouponResponse\"\x00\x12W\n" +
+	"\x0eValidateCoupon\x12 .coupon.v1.ValidateCouponRequest\x1a!.coupon.v1.ValidateCouponResponse\"\x00\x12T\n" +
+	"\rListCampaigns\x12\x1f.coupon.v1.ListCampaignsRequest\x1a .coupon.v1.ListCampaignsResponse\"\x00\x12N\n" +
+	"\vListCoupons\x12\x1d.coupon.v1.ListCouponsRequest\x1a\x1e.coupon.v1.ListCouponsResponse\"\x00\x12W\n" +
+	"\x0eUpdateCampaign\x12 .coupon.v1.UpdateCampaignRequest\x1a!.coupon.v1.UpdateCampaignResponse\"\x00\x12T\n" +
+	"\rPauseCampaign\x12\x1f.coupon.v1.PauseCampaignRequest\x1a .coupon.v1.PauseCampaignResponse\"\x00\x12W\n" +
+	"\x0eResumeCampaign\x12 .coupon.v1.ResumeCampaignRequest\x1a!.coupon.v1.ResumeCampaignResponse\"\x00\x12P\n" +
+	"\vImportCodes\x12\x1d.coupon.v1.ImportCodesRequest\x1a\x1e.coupon.v1.ImportCodesResponse\"\x00(\x012\xc3\x01\n" +
+	"\fAdminService\x12`\n" +
+	"\x11ReconcileCounters\x12#.coupon.v1.ReconcileCountersRequest\x1a$.coupon.v1.ReconcileCountersResponse\"\x00\x12Q\n" +
+	"\fRevokeCoupon\x12\x1e.coupon.v1.RevokeCouponRequest\x1a\x1f.coupon.v1.RevokeCouponResponse\"\x00B@Z>github.com/rpranjan11/coupon-issuance-system/api/coupon;couponb\x06proto3"
 
 var (
 	file_api_coupon_coupon_proto_rawDescOnce sync.Once
@@ -651,42 +2484,120 @@ func file_api_coupon_coupon_proto_rawDescGZIP() []byte {
 	return file_api_coupon_coupon_proto_rawDescData
 }
 
-var file_api_coupon_coupon_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_coupon_coupon_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_api_coupon_coupon_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_api_coupon_coupon_proto_goTypes = []any{
-	(*Campaign)(nil),               // 0: coupon.v1.Campaign
-	(*Coupon)(nil),                 // 1: coupon.v1.Coupon
-	(*CreateCampaignRequest)(nil),  // 2: coupon.v1.CreateCampaignRequest
-	(*CreateCampaignResponse)(nil), // 3: coupon.v1.CreateCampaignResponse
-	(*GetCampaignRequest)(nil),     // 4: coupon.v1.GetCampaignRequest
-	(*GetCampaignResponse)(nil),    // 5: coupon.v1.GetCampaignResponse
-	(*IssueCouponRequest)(nil),     // 6: coupon.v1.IssueCouponRequest
-	(*IssueCouponResponse)(nil),    // 7: coupon.v1.IssueCouponResponse
-	(*DeleteCampaignRequest)(nil),  // 8: coupon.v1.DeleteCampaignRequest
-	(*DeleteCampaignResponse)(nil), // 9: coupon.v1.DeleteCampaignResponse
-	(*timestamppb.Timestamp)(nil),  // 10: google.protobuf.Timestamp
+	(CampaignStatus)(0),               // 0: coupon.v1.CampaignStatus
+	(CodeMode)(0),                     // 1: coupon.v1.CodeMode
+	(CodeAlphabet)(0),                 // 2: coupon.v1.CodeAlphabet
+	(CouponStatus)(0),                 // 3: coupon.v1.CouponStatus
+	(CouponInvalidReason)(0),          // 4: coupon.v1.CouponInvalidReason
+	(*Campaign)(nil),                  // 5: coupon.v1.Campaign
+	(*CodeTemplate)(nil),              // 6: coupon.v1.CodeTemplate
+	(*Coupon)(nil),                    // 7: coupon.v1.Coupon
+	(*CreateCampaignRequest)(nil),     // 8: coupon.v1.CreateCampaignRequest
+	(*CreateCampaignResponse)(nil),    // 9: coupon.v1.CreateCampaignResponse
+	(*GetCampaignRequest)(nil),        // 10: coupon.v1.GetCampaignRequest
+	(*GetCampaignResponse)(nil),       // 11: coupon.v1.GetCampaignResponse
+	(*IssueCouponRequest)(nil),        // 12: coupon.v1.IssueCouponRequest
+	(*IssueCouponResponse)(nil),       // 13: coupon.v1.IssueCouponResponse
+	(*DeleteCampaignRequest)(nil),     // 14: coupon.v1.DeleteCampaignRequest
+	(*DeleteCampaignResponse)(nil),    // 15: coupon.v1.DeleteCampaignResponse
+	(*RedeemCouponRequest)(nil),       // 16: coupon.v1.RedeemCouponRequest
+	(*RedeemCouponResponse)(nil),      // 17: coupon.v1.RedeemCouponResponse
+	(*ValidateCouponRequest)(nil),     // 18: coupon.v1.ValidateCouponRequest
+	(*ValidateCouponResponse)(nil),    // 19: coupon.v1.ValidateCouponResponse
+	(*ListCampaignsRequest)(nil),      // 20: coupon.v1.ListCampaignsRequest
+	(*ListCampaignsResponse)(nil),     // 21: coupon.v1.ListCampaignsResponse
+	(*ListCouponsRequest)(nil),        // 22: coupon.v1.ListCouponsRequest
+	(*ListCouponsResponse)(nil),       // 23: coupon.v1.ListCouponsResponse
+	(*UpdateCampaignRequest)(nil),     // 24: coupon.v1.UpdateCampaignRequest
+	(*UpdateCampaignResponse)(nil),    // 25: coupon.v1.UpdateCampaignResponse
+	(*PauseCampaignRequest)(nil),      // 26: coupon.v1.PauseCampaignRequest
+	(*PauseCampaignResponse)(nil),     // 27: coupon.v1.PauseCampaignResponse
+	(*ResumeCampaignRequest)(nil),     // 28: coupon.v1.ResumeCampaignRequest
+	(*ResumeCampaignResponse)(nil),    // 29: coupon.v1.ResumeCampaignResponse
+	(*ImportCodesRequest)(nil),        // 30: coupon.v1.ImportCodesRequest
+	(*ImportCodesResponse)(nil),       // 31: coupon.v1.ImportCodesResponse
+	(*ReconcileCountersRequest)(nil),  // 32: coupon.v1.ReconcileCountersRequest
+	(*CounterDrift)(nil),              // 33: coupon.v1.CounterDrift
+	(*ReconcileCountersResponse)(nil), // 34: coupon.v1.ReconcileCountersResponse
+	(*RevokeCouponRequest)(nil),       // 35: coupon.v1.RevokeCouponRequest
+	(*RevokeCouponResponse)(nil),      // 36: coupon.v1.RevokeCouponResponse
+	nil,                               // 37: coupon.v1.CounterDrift.UserDriftEntry
+	(*timestamppb.Timestamp)(nil),     // 38: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),     // 39: google.protobuf.FieldMask
 }
 var file_api_coupon_coupon_proto_depIdxs = []int32{
-	10, // 0: coupon.v1.Campaign.start_time:type_name -> google.protobuf.Timestamp
-	10, // 1: coupon.v1.Campaign.created_at:type_name -> google.protobuf.Timestamp
-	10, // 2: coupon.v1.Coupon.issued_at:type_name -> google.protobuf.Timestamp
-	10, // 3: coupon.v1.CreateCampaignRequest.start_time:type_name -> google.protobuf.Timestamp
-	0,  // 4: coupon.v1.CreateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
-	0,  // 5: coupon.v1.GetCampaignResponse.campaign:type_name -> coupon.v1.Campaign
-	1,  // 6: coupon.v1.GetCampaignResponse.coupons:type_name -> coupon.v1.Coupon
-	1,  // 7: coupon.v1.IssueCouponResponse.coupon:type_name -> coupon.v1.Coupon
-	2,  // 8: coupon.v1.CouponService.CreateCampaign:input_type -> coupon.v1.CreateCampaignRequest
-	4,  // 9: coupon.v1.CouponService.GetCampaign:input_type -> coupon.v1.GetCampaignRequest
-	6,  // 10: coupon.v1.CouponService.IssueCoupon:input_type -> coupon.v1.IssueCouponRequest
-	8,  // 11: coupon.v1.CouponService.DeleteCampaign:input_type -> coupon.v1.DeleteCampaignRequest
-	3,  // 12: coupon.v1.CouponService.CreateCampaign:output_type -> coupon.v1.CreateCampaignResponse
-	5,  // 13: coupon.v1.CouponService.GetCampaign:output_type -> coupon.v1.GetCampaignResponse
-	7,  // 14: coupon.v1.CouponService.IssueCoupon:output_type -> coupon.v1.IssueCouponResponse
-	9,  // 15: coupon.v1.CouponService.DeleteCampaign:output_type -> coupon.v1.DeleteCampaignResponse
-	12, // [12:16] is the sub-list for method output_type
-	8,  // [8:12] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	38, // 0: coupon.v1.Campaign.start_time:type_name -> google.protobuf.Timestamp
+	38, // 1: coupon.v1.Campaign.created_at:type_name -> google.protobuf.Timestamp
+	38, // 2: coupon.v1.Campaign.end_time:type_name -> google.protobuf.Timestamp
+	0,  // 3: coupon.v1.Campaign.status:type_name -> coupon.v1.CampaignStatus
+	1,  // 4: coupon.v1.Campaign.code_mode:type_name -> coupon.v1.CodeMode
+	6,  // 5: coupon.v1.Campaign.code_template:type_name -> coupon.v1.CodeTemplate
+	2,  // 6: coupon.v1.CodeTemplate.alphabet:type_name -> coupon.v1.CodeAlphabet
+	38, // 7: coupon.v1.Coupon.issued_at:type_name -> google.protobuf.Timestamp
+	3,  // 8: coupon.v1.Coupon.status:type_name -> coupon.v1.CouponStatus
+	38, // 9: coupon.v1.Coupon.redeemed_at:type_name -> google.protobuf.Timestamp
+	38, // 10: coupon.v1.CreateCampaignRequest.start_time:type_name -> google.protobuf.Timestamp
+	38, // 11: coupon.v1.CreateCampaignRequest.end_time:type_name -> google.protobuf.Timestamp
+	1,  // 12: coupon.v1.CreateCampaignRequest.code_mode:type_name -> coupon.v1.CodeMode
+	6,  // 13: coupon.v1.CreateCampaignRequest.code_template:type_name -> coupon.v1.CodeTemplate
+	5,  // 14: coupon.v1.CreateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	5,  // 15: coupon.v1.GetCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	7,  // 16: coupon.v1.GetCampaignResponse.coupons:type_name -> coupon.v1.Coupon
+	7,  // 17: coupon.v1.IssueCouponResponse.coupon:type_name -> coupon.v1.Coupon
+	7,  // 18: coupon.v1.RedeemCouponResponse.coupon:type_name -> coupon.v1.Coupon
+	4,  // 19: coupon.v1.ValidateCouponResponse.reason:type_name -> coupon.v1.CouponInvalidReason
+	7,  // 20: coupon.v1.ValidateCouponResponse.coupon:type_name -> coupon.v1.Coupon
+	5,  // 21: coupon.v1.ValidateCouponResponse.campaign:type_name -> coupon.v1.Campaign
+	0,  // 22: coupon.v1.ListCampaignsRequest.status:type_name -> coupon.v1.CampaignStatus
+	38, // 23: coupon.v1.ListCampaignsRequest.created_after:type_name -> google.protobuf.Timestamp
+	38, // 24: coupon.v1.ListCampaignsRequest.created_before:type_name -> google.protobuf.Timestamp
+	5,  // 25: coupon.v1.ListCampaignsResponse.campaigns:type_name -> coupon.v1.Campaign
+	7,  // 26: coupon.v1.ListCouponsResponse.coupons:type_name -> coupon.v1.Coupon
+	5,  // 27: coupon.v1.UpdateCampaignRequest.campaign:type_name -> coupon.v1.Campaign
+	39, // 28: coupon.v1.UpdateCampaignRequest.update_mask:type_name -> google.protobuf.FieldMask
+	5,  // 29: coupon.v1.UpdateCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	5,  // 30: coupon.v1.PauseCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	5,  // 31: coupon.v1.ResumeCampaignResponse.campaign:type_name -> coupon.v1.Campaign
+	37, // 32: coupon.v1.CounterDrift.user_drift:type_name -> coupon.v1.CounterDrift.UserDriftEntry
+	38, // 33: coupon.v1.ReconcileCountersResponse.checked_at:type_name -> google.protobuf.Timestamp
+	33, // 34: coupon.v1.ReconcileCountersResponse.drifts:type_name -> coupon.v1.CounterDrift
+	7,  // 35: coupon.v1.RevokeCouponResponse.coupon:type_name -> coupon.v1.Coupon
+	8,  // 36: coupon.v1.CouponService.CreateCampaign:input_type -> coupon.v1.CreateCampaignRequest
+	10, // 37: coupon.v1.CouponService.GetCampaign:input_type -> coupon.v1.GetCampaignRequest
+	12, // 38: coupon.v1.CouponService.IssueCoupon:input_type -> coupon.v1.IssueCouponRequest
+	14, // 39: coupon.v1.CouponService.DeleteCampaign:input_type -> coupon.v1.DeleteCampaignRequest
+	16, // 40: coupon.v1.CouponService.RedeemCoupon:input_type -> coupon.v1.RedeemCouponRequest
+	18, // 41: coupon.v1.CouponService.ValidateCoupon:input_type -> coupon.v1.ValidateCouponRequest
+	20, // 42: coupon.v1.CouponService.ListCampaigns:input_type -> coupon.v1.ListCampaignsRequest
+	22, // 43: coupon.v1.CouponService.ListCoupons:input_type -> coupon.v1.ListCouponsRequest
+	24, // 44: coupon.v1.CouponService.UpdateCampaign:input_type -> coupon.v1.UpdateCampaignRequest
+	26, // 45: coupon.v1.CouponService.PauseCampaign:input_type -> coupon.v1.PauseCampaignRequest
+	28, // 46: coupon.v1.CouponService.ResumeCampaign:input_type -> coupon.v1.ResumeCampaignRequest
+	30, // 47: coupon.v1.CouponService.ImportCodes:input_type -> coupon.v1.ImportCodesRequest
+	32, // 48: coupon.v1.AdminService.ReconcileCounters:input_type -> coupon.v1.ReconcileCountersRequest
+	35, // 49: coupon.v1.AdminService.RevokeCoupon:input_type -> coupon.v1.RevokeCouponRequest
+	9,  // 50: coupon.v1.CouponService.CreateCampaign:output_type -> coupon.v1.CreateCampaignResponse
+	11, // 51: coupon.v1.CouponService.GetCampaign:output_type -> coupon.v1.GetCampaignResponse
+	13, // 52: coupon.v1.CouponService.IssueCoupon:output_type -> coupon.v1.IssueCouponResponse
+	15, // 53: coupon.v1.CouponService.DeleteCampaign:output_type -> coupon.v1.DeleteCampaignResponse
+	17, // 54: coupon.v1.CouponService.RedeemCoupon:output_type -> coupon.v1.RedeemCouponResponse
+	19, // 55: coupon.v1.CouponService.ValidateCoupon:output_type -> coupon.v1.ValidateCouponResponse
+	21, // 56: coupon.v1.CouponService.ListCampaigns:output_type -> coupon.v1.ListCampaignsResponse
+	23, // 57: coupon.v1.CouponService.ListCoupons:output_type -> coupon.v1.ListCouponsResponse
+	25, // 58: coupon.v1.CouponService.UpdateCampaign:output_type -> coupon.v1.UpdateCampaignResponse
+	27, // 59: coupon.v1.CouponService.PauseCampaign:output_type -> coupon.v1.PauseCampaignResponse
+	29, // 60: coupon.v1.CouponService.ResumeCampaign:output_type -> coupon.v1.ResumeCampaignResponse
+	31, // 61: coupon.v1.CouponService.ImportCodes:output_type -> coupon.v1.ImportCodesResponse
+	34, // 62: coupon.v1.AdminService.ReconcileCounters:output_type -> coupon.v1.ReconcileCountersResponse
+	36, // 63: coupon.v1.AdminService.RevokeCoupon:output_type -> coupon.v1.RevokeCouponResponse
+	50, // [50:64] is the sub-list for method output_type
+	36, // [36:50] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_api_coupon_coupon_proto_init() }
@@ -699,13 +2610,14 @@ func file_api_coupon_coupon_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_coupon_coupon_proto_rawDesc), len(file_api_coupon_coupon_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      5,
+			NumMessages:   33,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_api_coupon_coupon_proto_goTypes,
 		DependencyIndexes: file_api_coupon_coupon_proto_depIdxs,
+		EnumInfos:         file_api_coupon_coupon_proto_enumTypes,
 		MessageInfos:      file_api_coupon_coupon_proto_msgTypes,
 	}.Build()
 	File_api_coupon_coupon_proto = out.File
@@ -26,6 +26,8 @@ const _ = connect_go.IsAtLeastVersion0_1_0
 const (
 	// CouponServiceName is the fully-qualified name of the CouponService service.
 	CouponServiceName = "coupon.v1.CouponService"
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "coupon.v1.AdminService"
 )
 
 // These constants are the fully-qualified names of the RPCs defined in this package. They're
@@ -48,6 +50,36 @@ const (
 	// CouponServiceDeleteCampaignProcedure is the fully-qualified name of the CouponService's
 	// DeleteCampaign RPC.
 	CouponServiceDeleteCampaignProcedure = "/coupon.v1.CouponService/DeleteCampaign"
+	// CouponServiceRedeemCouponProcedure is the fully-qualified name of the CouponService's
+	// RedeemCoupon RPC.
+	CouponServiceRedeemCouponProcedure = "/coupon.v1.CouponService/RedeemCoupon"
+	// CouponServiceValidateCouponProcedure is the fully-qualified name of the CouponService's
+	// ValidateCoupon RPC.
+	CouponServiceValidateCouponProcedure = "/coupon.v1.CouponService/ValidateCoupon"
+	// CouponServiceListCampaignsProcedure is the fully-qualified name of the CouponService's
+	// ListCampaigns RPC.
+	CouponServiceListCampaignsProcedure = "/coupon.v1.CouponService/ListCampaigns"
+	// CouponServiceListCouponsProcedure is the fully-qualified name of the CouponService's ListCoupons
+	// RPC.
+	CouponServiceListCouponsProcedure = "/coupon.v1.CouponService/ListCoupons"
+	// CouponServiceUpdateCampaignProcedure is the fully-qualified name of the CouponService's
+	// UpdateCampaign RPC.
+	CouponServiceUpdateCampaignProcedure = "/coupon.v1.CouponService/UpdateCampaign"
+	// CouponServicePauseCampaignProcedure is the fully-qualified name of the CouponService's
+	// PauseCampaign RPC.
+	CouponServicePauseCampaignProcedure = "/coupon.v1.CouponService/PauseCampaign"
+	// CouponServiceResumeCampaignProcedure is the fully-qualified name of the CouponService's
+	// ResumeCampaign RPC.
+	CouponServiceResumeCampaignProcedure = "/coupon.v1.CouponService/ResumeCampaign"
+	// CouponServiceImportCodesProcedure is the fully-qualified name of the CouponService's ImportCodes
+	// RPC.
+	CouponServiceImportCodesProcedure = "/coupon.v1.CouponService/ImportCodes"
+	// AdminServiceReconcileCountersProcedure is the fully-qualified name of the AdminService's
+	// ReconcileCounters RPC.
+	AdminServiceReconcileCountersProcedure = "/coupon.v1.AdminService/ReconcileCounters"
+	// AdminServiceRevokeCouponProcedure is the fully-qualified name of the AdminService's RevokeCoupon
+	// RPC.
+	AdminServiceRevokeCouponProcedure = "/coupon.v1.AdminService/RevokeCoupon"
 )
 
 // CouponServiceClient is a client for the coupon.v1.CouponService service.
@@ -60,6 +92,23 @@ type CouponServiceClient interface {
 	IssueCoupon(context.Context, *connect_go.Request[coupon.IssueCouponRequest]) (*connect_go.Response[coupon.IssueCouponResponse], error)
 	// DeleteCampaign deletes a campaign by ID or name
 	DeleteCampaign(context.Context, *connect_go.Request[coupon.DeleteCampaignRequest]) (*connect_go.Response[coupon.DeleteCampaignResponse], error)
+	// RedeemCoupon marks an issued coupon as used; a coupon can be redeemed only once
+	RedeemCoupon(context.Context, *connect_go.Request[coupon.RedeemCouponRequest]) (*connect_go.Response[coupon.RedeemCouponResponse], error)
+	// ValidateCoupon checks whether a coupon code is usable without redeeming it
+	ValidateCoupon(context.Context, *connect_go.Request[coupon.ValidateCouponRequest]) (*connect_go.Response[coupon.ValidateCouponResponse], error)
+	// ListCampaigns lists campaigns page by page, optionally filtered
+	ListCampaigns(context.Context, *connect_go.Request[coupon.ListCampaignsRequest]) (*connect_go.Response[coupon.ListCampaignsResponse], error)
+	// ListCoupons lists the coupons issued for a campaign page by page, in issue order
+	ListCoupons(context.Context, *connect_go.Request[coupon.ListCouponsRequest]) (*connect_go.Response[coupon.ListCouponsResponse], error)
+	// UpdateCampaign changes the name, start time or total coupons of a campaign
+	UpdateCampaign(context.Context, *connect_go.Request[coupon.UpdateCampaignRequest]) (*connect_go.Response[coupon.UpdateCampaignResponse], error)
+	// PauseCampaign stops issuance for a campaign without deleting it
+	PauseCampaign(context.Context, *connect_go.Request[coupon.PauseCampaignRequest]) (*connect_go.Response[coupon.PauseCampaignResponse], error)
+	// ResumeCampaign restarts issuance for a paused campaign
+	ResumeCampaign(context.Context, *connect_go.Request[coupon.ResumeCampaignRequest]) (*connect_go.Response[coupon.ResumeCampaignResponse], error)
+	// ImportCodes uploads partner-supplied codes into the code pool of a campaign
+	// created with CODE_MODE_IMPORTED; IssueCoupon hands them out in upload order
+	ImportCodes(context.Context) *connect_go.ClientStreamForClient[coupon.ImportCodesRequest, coupon.ImportCodesResponse]
 }
 
 // NewCouponServiceClient constructs a client for the coupon.v1.CouponService service. By default,
@@ -92,6 +141,46 @@ func NewCouponServiceClient(httpClient connect_go.HTTPClient, baseURL string, op
 			baseURL+CouponServiceDeleteCampaignProcedure,
 			opts...,
 		),
+		redeemCoupon: connect_go.NewClient[coupon.RedeemCouponRequest, coupon.RedeemCouponResponse](
+			httpClient,
+			baseURL+CouponServiceRedeemCouponProcedure,
+			opts...,
+		),
+		validateCoupon: connect_go.NewClient[coupon.ValidateCouponRequest, coupon.ValidateCouponResponse](
+			httpClient,
+			baseURL+CouponServiceValidateCouponProcedure,
+			opts...,
+		),
+		listCampaigns: connect_go.NewClient[coupon.ListCampaignsRequest, coupon.ListCampaignsResponse](
+			httpClient,
+			baseURL+CouponServiceListCampaignsProcedure,
+			opts...,
+		),
+		listCoupons: connect_go.NewClient[coupon.ListCouponsRequest, coupon.ListCouponsResponse](
+			httpClient,
+			baseURL+CouponServiceListCouponsProcedure,
+			opts...,
+		),
+		updateCampaign: connect_go.NewClient[coupon.UpdateCampaignRequest, coupon.UpdateCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceUpdateCampaignProcedure,
+			opts...,
+		),
+		pauseCampaign: connect_go.NewClient[coupon.PauseCampaignRequest, coupon.PauseCampaignResponse](
+			httpClient,
+			baseURL+CouponServicePauseCampaignProcedure,
+			opts...,
+		),
+		resumeCampaign: connect_go.NewClient[coupon.ResumeCampaignRequest, coupon.ResumeCampaignResponse](
+			httpClient,
+			baseURL+CouponServiceResumeCampaignProcedure,
+			opts...,
+		),
+		importCodes: connect_go.NewClient[coupon.ImportCodesRequest, coupon.ImportCodesResponse](
+			httpClient,
+			baseURL+CouponServiceImportCodesProcedure,
+			opts...,
+		),
 	}
 }
 
@@ -101,6 +190,14 @@ type couponServiceClient struct {
 	getCampaign    *connect_go.Client[coupon.GetCampaignRequest, coupon.GetCampaignResponse]
 	issueCoupon    *connect_go.Client[coupon.IssueCouponRequest, coupon.IssueCouponResponse]
 	deleteCampaign *connect_go.Client[coupon.DeleteCampaignRequest, coupon.DeleteCampaignResponse]
+	redeemCoupon   *connect_go.Client[coupon.RedeemCouponRequest, coupon.RedeemCouponResponse]
+	validateCoupon *connect_go.Client[coupon.ValidateCouponRequest, coupon.ValidateCouponResponse]
+	listCampaigns  *connect_go.Client[coupon.ListCampaignsRequest, coupon.ListCampaignsResponse]
+	listCoupons    *connect_go.Client[coupon.ListCouponsRequest, coupon.ListCouponsResponse]
+	updateCampaign *connect_go.Client[coupon.UpdateCampaignRequest, coupon.UpdateCampaignResponse]
+	pauseCampaign  *connect_go.Client[coupon.PauseCampaignRequest, coupon.PauseCampaignResponse]
+	resumeCampaign *connect_go.Client[coupon.ResumeCampaignRequest, coupon.ResumeCampaignResponse]
+	importCodes    *connect_go.Client[coupon.ImportCodesRequest, coupon.ImportCodesResponse]
 }
 
 // CreateCampaign calls coupon.v1.CouponService.CreateCampaign.
@@ -123,6 +220,46 @@ func (c *couponServiceClient) DeleteCampaign(ctx context.Context, req *connect_g
 	return c.deleteCampaign.CallUnary(ctx, req)
 }
 
+// RedeemCoupon calls coupon.v1.CouponService.RedeemCoupon.
+func (c *couponServiceClient) RedeemCoupon(ctx context.Context, req *connect_go.Request[coupon.RedeemCouponRequest]) (*connect_go.Response[coupon.RedeemCouponResponse], error) {
+	return c.redeemCoupon.CallUnary(ctx, req)
+}
+
+// ValidateCoupon calls coupon.v1.CouponService.ValidateCoupon.
+func (c *couponServiceClient) ValidateCoupon(ctx context.Context, req *connect_go.Request[coupon.ValidateCouponRequest]) (*connect_go.Response[coupon.ValidateCouponResponse], error) {
+	return c.validateCoupon.CallUnary(ctx, req)
+}
+
+// ListCampaigns calls coupon.v1.CouponService.ListCampaigns.
+func (c *couponServiceClient) ListCampaigns(ctx context.Context, req *connect_go.Request[coupon.ListCampaignsRequest]) (*connect_go.Response[coupon.ListCampaignsResponse], error) {
+	return c.listCampaigns.CallUnary(ctx, req)
+}
+
+// ListCoupons calls coupon.v1.CouponService.ListCoupons.
+func (c *couponServiceClient) ListCoupons(ctx context.Context, req *connect_go.Request[coupon.ListCouponsRequest]) (*connect_go.Response[coupon.ListCouponsResponse], error) {
+	return c.listCoupons.CallUnary(ctx, req)
+}
+
+// UpdateCampaign calls coupon.v1.CouponService.UpdateCampaign.
+func (c *couponServiceClient) UpdateCampaign(ctx context.Context, req *connect_go.Request[coupon.UpdateCampaignRequest]) (*connect_go.Response[coupon.UpdateCampaignResponse], error) {
+	return c.updateCampaign.CallUnary(ctx, req)
+}
+
+// PauseCampaign calls coupon.v1.CouponService.PauseCampaign.
+func (c *couponServiceClient) PauseCampaign(ctx context.Context, req *connect_go.Request[coupon.PauseCampaignRequest]) (*connect_go.Response[coupon.PauseCampaignResponse], error) {
+	return c.pauseCampaign.CallUnary(ctx, req)
+}
+
+// ResumeCampaign calls coupon.v1.CouponService.ResumeCampaign.
+func (c *couponServiceClient) ResumeCampaign(ctx context.Context, req *connect_go.Request[coupon.ResumeCampaignRequest]) (*connect_go.Response[coupon.ResumeCampaignResponse], error) {
+	return c.resumeCampaign.CallUnary(ctx, req)
+}
+
+// ImportCodes calls coupon.v1.CouponService.ImportCodes.
+func (c *couponServiceClient) ImportCodes(ctx context.Context) *connect_go.ClientStreamForClient[coupon.ImportCodesRequest, coupon.ImportCodesResponse] {
+	return c.importCodes.CallClientStream(ctx)
+}
+
 // CouponServiceHandler is an implementation of the coupon.v1.CouponService service.
 type CouponServiceHandler interface {
 	// CreateCampaign creates a new coupon campaign
@@ -133,6 +270,23 @@ type CouponServiceHandler interface {
 	IssueCoupon(context.Context, *connect_go.Request[coupon.IssueCouponRequest]) (*connect_go.Response[coupon.IssueCouponResponse], error)
 	// DeleteCampaign deletes a campaign by ID or name
 	DeleteCampaign(context.Context, *connect_go.Request[coupon.DeleteCampaignRequest]) (*connect_go.Response[coupon.DeleteCampaignResponse], error)
+	// RedeemCoupon marks an issued coupon as used; a coupon can be redeemed only once
+	RedeemCoupon(context.Context, *connect_go.Request[coupon.RedeemCouponRequest]) (*connect_go.Response[coupon.RedeemCouponResponse], error)
+	// ValidateCoupon checks whether a coupon code is usable without redeeming it
+	ValidateCoupon(context.Context, *connect_go.Request[coupon.ValidateCouponRequest]) (*connect_go.Response[coupon.ValidateCouponResponse], error)
+	// ListCampaigns lists campaigns page by page, optionally filtered
+	ListCampaigns(context.Context, *connect_go.Request[coupon.ListCampaignsRequest]) (*connect_go.Response[coupon.ListCampaignsResponse], error)
+	// ListCoupons lists the coupons issued for a campaign page by page, in issue order
+	ListCoupons(context.Context, *connect_go.Request[coupon.ListCouponsRequest]) (*connect_go.Response[coupon.ListCouponsResponse], error)
+	// UpdateCampaign changes the name, start time or total coupons of a campaign
+	UpdateCampaign(context.Context, *connect_go.Request[coupon.UpdateCampaignRequest]) (*connect_go.Response[coupon.UpdateCampaignResponse], error)
+	// PauseCampaign stops issuance for a campaign without deleting it
+	PauseCampaign(context.Context, *connect_go.Request[coupon.PauseCampaignRequest]) (*connect_go.Response[coupon.PauseCampaignResponse], error)
+	// ResumeCampaign restarts issuance for a paused campaign
+	ResumeCampaign(context.Context, *connect_go.Request[coupon.ResumeCampaignRequest]) (*connect_go.Response[coupon.ResumeCampaignResponse], error)
+	// ImportCodes uploads partner-supplied codes into the code pool of a campaign
+	// created with CODE_MODE_IMPORTED; IssueCoupon hands them out in upload order
+	ImportCodes(context.Context, *connect_go.ClientStream[coupon.ImportCodesRequest]) (*connect_go.Response[coupon.ImportCodesResponse], error)
 }
 
 // NewCouponServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -161,6 +315,46 @@ func NewCouponServiceHandler(svc CouponServiceHandler, opts ...connect_go.Handle
 		svc.DeleteCampaign,
 		opts...,
 	)
+	couponServiceRedeemCouponHandler := connect_go.NewUnaryHandler(
+		CouponServiceRedeemCouponProcedure,
+		svc.RedeemCoupon,
+		opts...,
+	)
+	couponServiceValidateCouponHandler := connect_go.NewUnaryHandler(
+		CouponServiceValidateCouponProcedure,
+		svc.ValidateCoupon,
+		opts...,
+	)
+	couponServiceListCampaignsHandler := connect_go.NewUnaryHandler(
+		CouponServiceListCampaignsProcedure,
+		svc.ListCampaigns,
+		opts...,
+	)
+	couponServiceListCouponsHandler := connect_go.NewUnaryHandler(
+		CouponServiceListCouponsProcedure,
+		svc.ListCoupons,
+		opts...,
+	)
+	couponServiceUpdateCampaignHandler := connect_go.NewUnaryHandler(
+		CouponServiceUpdateCampaignProcedure,
+		svc.UpdateCampaign,
+		opts...,
+	)
+	couponServicePauseCampaignHandler := connect_go.NewUnaryHandler(
+		CouponServicePauseCampaignProcedure,
+		svc.PauseCampaign,
+		opts...,
+	)
+	couponServiceResumeCampaignHandler := connect_go.NewUnaryHandler(
+		CouponServiceResumeCampaignProcedure,
+		svc.ResumeCampaign,
+		opts...,
+	)
+	couponServiceImportCodesHandler := connect_go.NewClientStreamHandler(
+		CouponServiceImportCodesProcedure,
+		svc.ImportCodes,
+		opts...,
+	)
 	return "/coupon.v1.CouponService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case CouponServiceCreateCampaignProcedure:
@@ -171,6 +365,22 @@ func NewCouponServiceHandler(svc CouponServiceHandler, opts ...connect_go.Handle
 			couponServiceIssueCouponHandler.ServeHTTP(w, r)
 		case CouponServiceDeleteCampaignProcedure:
 			couponServiceDeleteCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceRedeemCouponProcedure:
+			couponServiceRedeemCouponHandler.ServeHTTP(w, r)
+		case CouponServiceValidateCouponProcedure:
+			couponServiceValidateCouponHandler.ServeHTTP(w, r)
+		case CouponServiceListCampaignsProcedure:
+			couponServiceListCampaignsHandler.ServeHTTP(w, r)
+		case CouponServiceListCouponsProcedure:
+			couponServiceListCouponsHandler.ServeHTTP(w, r)
+		case CouponServiceUpdateCampaignProcedure:
+			couponServiceUpdateCampaignHandler.ServeHTTP(w, r)
+		case CouponServicePauseCampaignProcedure:
+			couponServicePauseCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceResumeCampaignProcedure:
+			couponServiceResumeCampaignHandler.ServeHTTP(w, r)
+		case CouponServiceImportCodesProcedure:
+			couponServiceImportCodesHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -195,3 +405,131 @@ func (UnimplementedCouponServiceHandler) IssueCoupon(context.Context, *connect_g
 func (UnimplementedCouponServiceHandler) DeleteCampaign(context.Context, *connect_go.Request[coupon.DeleteCampaignRequest]) (*connect_go.Response[coupon.DeleteCampaignResponse], error) {
 	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.DeleteCampaign is not implemented"))
 }
+
+func (UnimplementedCouponServiceHandler) RedeemCoupon(context.Context, *connect_go.Request[coupon.RedeemCouponRequest]) (*connect_go.Response[coupon.RedeemCouponResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.RedeemCoupon is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ValidateCoupon(context.Context, *connect_go.Request[coupon.ValidateCouponRequest]) (*connect_go.Response[coupon.ValidateCouponResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.ValidateCoupon is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ListCampaigns(context.Context, *connect_go.Request[coupon.ListCampaignsRequest]) (*connect_go.Response[coupon.ListCampaignsResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.ListCampaigns is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ListCoupons(context.Context, *connect_go.Request[coupon.ListCouponsRequest]) (*connect_go.Response[coupon.ListCouponsResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.ListCoupons is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) UpdateCampaign(context.Context, *connect_go.Request[coupon.UpdateCampaignRequest]) (*connect_go.Response[coupon.UpdateCampaignResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.UpdateCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) PauseCampaign(context.Context, *connect_go.Request[coupon.PauseCampaignRequest]) (*connect_go.Response[coupon.PauseCampaignResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.PauseCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ResumeCampaign(context.Context, *connect_go.Request[coupon.ResumeCampaignRequest]) (*connect_go.Response[coupon.ResumeCampaignResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.ResumeCampaign is not implemented"))
+}
+
+func (UnimplementedCouponServiceHandler) ImportCodes(context.Context, *connect_go.ClientStream[coupon.ImportCodesRequest]) (*connect_go.Response[coupon.ImportCodesResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.CouponService.ImportCodes is not implemented"))
+}
+
+// AdminServiceClient is a client for the coupon.v1.AdminService service.
+type AdminServiceClient interface {
+	// ReconcileCounters compares each campaign's issued counters with its stored
+	// coupons and, when asked, releases slots that never became coupons
+	ReconcileCounters(context.Context, *connect_go.Request[coupon.ReconcileCountersRequest]) (*connect_go.Response[coupon.ReconcileCountersResponse], error)
+	// RevokeCoupon withdraws an issued coupon so it can no longer be redeemed
+	RevokeCoupon(context.Context, *connect_go.Request[coupon.RevokeCouponRequest]) (*connect_go.Response[coupon.RevokeCouponResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the coupon.v1.AdminService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect_go.HTTPClient, baseURL string, opts ...connect_go.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return &adminServiceClient{
+		reconcileCounters: connect_go.NewClient[coupon.ReconcileCountersRequest, coupon.ReconcileCountersResponse](
+			httpClient,
+			baseURL+AdminServiceReconcileCountersProcedure,
+			opts...,
+		),
+		revokeCoupon: connect_go.NewClient[coupon.RevokeCouponRequest, coupon.RevokeCouponResponse](
+			httpClient,
+			baseURL+AdminServiceRevokeCouponProcedure,
+			opts...,
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	reconcileCounters *connect_go.Client[coupon.ReconcileCountersRequest, coupon.ReconcileCountersResponse]
+	revokeCoupon      *connect_go.Client[coupon.RevokeCouponRequest, coupon.RevokeCouponResponse]
+}
+
+// ReconcileCounters calls coupon.v1.AdminService.ReconcileCounters.
+func (c *adminServiceClient) ReconcileCounters(ctx context.Context, req *connect_go.Request[coupon.ReconcileCountersRequest]) (*connect_go.Response[coupon.ReconcileCountersResponse], error) {
+	return c.reconcileCounters.CallUnary(ctx, req)
+}
+
+// RevokeCoupon calls coupon.v1.AdminService.RevokeCoupon.
+func (c *adminServiceClient) RevokeCoupon(ctx context.Context, req *connect_go.Request[coupon.RevokeCouponRequest]) (*connect_go.Response[coupon.RevokeCouponResponse], error) {
+	return c.revokeCoupon.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the coupon.v1.AdminService service.
+type AdminServiceHandler interface {
+	// ReconcileCounters compares each campaign's issued counters with its stored
+	// coupons and, when asked, releases slots that never became coupons
+	ReconcileCounters(context.Context, *connect_go.Request[coupon.ReconcileCountersRequest]) (*connect_go.Response[coupon.ReconcileCountersResponse], error)
+	// RevokeCoupon withdraws an issued coupon so it can no longer be redeemed
+	RevokeCoupon(context.Context, *connect_go.Request[coupon.RevokeCouponRequest]) (*connect_go.Response[coupon.RevokeCouponResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect_go.HandlerOption) (string, http.Handler) {
+	adminServiceReconcileCountersHandler := connect_go.NewUnaryHandler(
+		AdminServiceReconcileCountersProcedure,
+		svc.ReconcileCounters,
+		opts...,
+	)
+	adminServiceRevokeCouponHandler := connect_go.NewUnaryHandler(
+		AdminServiceRevokeCouponProcedure,
+		svc.RevokeCoupon,
+		opts...,
+	)
+	return "/coupon.v1.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceReconcileCountersProcedure:
+			adminServiceReconcileCountersHandler.ServeHTTP(w, r)
+		case AdminServiceRevokeCouponProcedure:
+			adminServiceRevokeCouponHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) ReconcileCounters(context.Context, *connect_go.Request[coupon.ReconcileCountersRequest]) (*connect_go.Response[coupon.ReconcileCountersResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.AdminService.ReconcileCounters is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) RevokeCoupon(context.Context, *connect_go.Request[coupon.RevokeCouponRequest]) (*connect_go.Response[coupon.RevokeCouponResponse], error) {
+	return nil, connect_go.NewError(connect_go.CodeUnimplemented, errors.New("coupon.v1.AdminService.RevokeCoupon is not implemented"))
+}
@@ -2,28 +2,51 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	coupon "github.com/rpranjan11/coupon-issuance-system/api/coupon"
 	"github.com/rpranjan11/coupon-issuance-system/api/coupon/couponconnect"
+	"github.com/rpranjan11/coupon-issuance-system/pkg/couponverify"
 )
 
 func main() {
 	serverAddr := flag.String("server", "http://localhost:8080", "server address")
-	command := flag.String("command", "issue", "command to run: create, get, issue, or delete")
-	campaignID := flag.String("campaign", "", "campaign ID for get, issue, and delete commands")
-	campaignName := flag.String("name", "Test Campaign", "campaign name for create and delete commands")
-	totalCoupons := flag.Int("total", 10, "total coupons for create command")
-	startIn := flag.Duration("start-in", 0, "start time in duration from now for create command")
+	adminAddr := flag.String("admin-server", "http://localhost:8081", "admin API address for the reconcile command")
+	command := flag.String("command", "issue", "command to run: create, get, list, coupons, update, pause, resume, issue, delete, redeem, validate, verify, import, or reconcile")
+	campaignID := flag.String("campaign", "", "campaign ID for get, update, pause, resume, issue, delete, verify, and import commands")
+	campaignName := flag.String("name", "Test Campaign", "campaign name for create, update, and delete commands")
+	totalCoupons := flag.Int("total", 10, "total coupons for create and update commands")
+	startIn := flag.Duration("start-in", 0, "start time in duration from now for create and update commands")
+	version := flag.Int64("version", 0, "campaign version the update command is based on (0 skips the check)")
+	endIn := flag.Duration("end-in", 0, "end time in duration from now for create command (0 means no end time)")
+	codeMode := flag.String("code-mode", "random", "coupon code mode for create command: random, permuted, signed or imported")
+	codePrefix := flag.String("code-prefix", "", "code template prefix for create command")
+	codeAlphabet := flag.String("code-alphabet", "", "code template alphabet for create command: hangul, latin or digits")
+	codeGroups := flag.String("code-groups", "", "code template group sizes for create command, check character included (e.g. 4,4,3)")
+	codeSeparator := flag.String("code-separator", "", "code template separator between groups for create command")
+	maxPerUser := flag.Int("max-per-user", 0, "maximum coupons per user for create command (0 means unlimited)")
+	code := flag.String("code", "", "coupon code for redeem, validate, and verify commands")
+	namePrefix := flag.String("name-prefix", "", "campaign name prefix filter for list command")
+	pageSize := flag.Int("page-size", 0, "page size for list and coupons commands (0 uses the server default)")
+	pageToken := flag.String("page-token", "", "page token for list and coupons commands")
+	omitCoupons := flag.Bool("omit-coupons", false, "skip issued coupons in get command output")
+	repair := flag.Bool("repair", false, "release leaked issuance slots in reconcile command")
+	keysPath := flag.String("keys", "", "code signing key file for verify command")
+	codesPath := flag.String("codes-file", "", "file with one code per line for import command")
+	userID := flag.String("user", "", "user ID for issue command, or to filter coupons for get command")
 	flag.Parse()
 
 	// Create HTTP client
@@ -31,18 +54,60 @@ func main() {
 		http.DefaultClient,
 		*serverAddr,
 	)
+	adminClient := couponconnect.NewAdminServiceClient(
+		http.DefaultClient,
+		*adminAddr,
+	)
 
 	// Execute the requested command
 	switch *command {
 	case "create":
-		// Calculate start time
+		// Calculate start and end time
 		startTime := time.Now().Add(*startIn)
+		var endTime *timestamppb.Timestamp
+		if *endIn > 0 {
+			endTime = timestamppb.New(time.Now().Add(*endIn))
+		}
+
+		mode, ok := coupon.CodeMode_value["CODE_MODE_"+strings.ToUpper(*codeMode)]
+		if !ok {
+			log.Fatalf("Unknown code mode: %s", *codeMode)
+		}
+
+		// Build the code template if any of its flags is set
+		var codeTemplate *coupon.CodeTemplate
+		if *codePrefix != "" || *codeAlphabet != "" || *codeGroups != "" || *codeSeparator != "" {
+			codeTemplate = &coupon.CodeTemplate{
+				Prefix:    *codePrefix,
+				Separator: *codeSeparator,
+			}
+			if *codeAlphabet != "" {
+				alphabet, ok := coupon.CodeAlphabet_value["CODE_ALPHABET_"+strings.ToUpper(*codeAlphabet)]
+				if !ok {
+					log.Fatalf("Unknown code alphabet: %s", *codeAlphabet)
+				}
+				codeTemplate.Alphabet = coupon.CodeAlphabet(alphabet)
+			}
+			if *codeGroups != "" {
+				for _, group := range strings.Split(*codeGroups, ",") {
+					size, err := strconv.Atoi(strings.TrimSpace(group))
+					if err != nil {
+						log.Fatalf("Invalid code group size: %s", group)
+					}
+					codeTemplate.Groups = append(codeTemplate.Groups, int32(size))
+				}
+			}
+		}
 
 		// Create request
 		req := connect.NewRequest(&coupon.CreateCampaignRequest{
-			Name:         *campaignName,
-			TotalCoupons: int32(*totalCoupons),
-			StartTime:    timestamppb.New(startTime),
+			Name:              *campaignName,
+			TotalCoupons:      int32(*totalCoupons),
+			StartTime:         timestamppb.New(startTime),
+			EndTime:           endTime,
+			MaxCouponsPerUser: int32(*maxPerUser),
+			CodeMode:          coupon.CodeMode(mode),
+			CodeTemplate:      codeTemplate,
 		})
 
 		// Call API
@@ -56,7 +121,18 @@ func main() {
 		fmt.Printf("ID: %s\n", resp.Msg.Campaign.Id)
 		fmt.Printf("Name: %s\n", resp.Msg.Campaign.Name)
 		fmt.Printf("Total Coupons: %d\n", resp.Msg.Campaign.TotalCoupons)
+		if resp.Msg.Campaign.MaxCouponsPerUser > 0 {
+			fmt.Printf("Max Coupons Per User: %d\n", resp.Msg.Campaign.MaxCouponsPerUser)
+		}
 		fmt.Printf("Start Time: %s\n", resp.Msg.Campaign.StartTime.AsTime().Format(time.RFC3339))
+		if resp.Msg.Campaign.EndTime != nil {
+			fmt.Printf("End Time: %s\n", resp.Msg.Campaign.EndTime.AsTime().Format(time.RFC3339))
+		}
+		fmt.Printf("Code Mode: %s\n", resp.Msg.Campaign.CodeMode)
+		if template := resp.Msg.Campaign.CodeTemplate; template != nil {
+			fmt.Printf("Code Template: prefix %q, %s, groups %v, separator %q\n",
+				template.Prefix, template.Alphabet, template.Groups, template.Separator)
+		}
 
 	case "get":
 		// Validate campaign ID
@@ -66,7 +142,9 @@ func main() {
 
 		// Create request
 		req := connect.NewRequest(&coupon.GetCampaignRequest{
-			CampaignId: *campaignID,
+			CampaignId:  *campaignID,
+			UserId:      *userID,
+			OmitCoupons: *omitCoupons,
 		})
 
 		// Call API
@@ -81,15 +159,152 @@ func main() {
 		fmt.Printf("Name: %s\n", resp.Msg.Campaign.Name)
 		fmt.Printf("Total Coupons: %d\n", resp.Msg.Campaign.TotalCoupons)
 		fmt.Printf("Issued Coupons: %d\n", resp.Msg.Campaign.IssuedCoupons)
+		fmt.Printf("Version: %d\n", resp.Msg.Campaign.Version)
 		fmt.Printf("Start Time: %s\n", resp.Msg.Campaign.StartTime.AsTime().Format(time.RFC3339))
+		if resp.Msg.Campaign.EndTime != nil {
+			fmt.Printf("End Time: %s\n", resp.Msg.Campaign.EndTime.AsTime().Format(time.RFC3339))
+		}
 
 		// Print coupons
+		if *omitCoupons {
+			break
+		}
 		fmt.Printf("\nIssued Coupons (%d):\n", len(resp.Msg.Coupons))
 		for i, c := range resp.Msg.Coupons {
-			fmt.Printf("%d. Code: %s, Issued At: %s\n",
+			fmt.Printf("%d. Code: %s, Issued At: %s",
 				i+1, c.Code, c.IssuedAt.AsTime().Format(time.RFC3339))
+			if c.UserId != "" {
+				fmt.Printf(", User: %s", c.UserId)
+			}
+			fmt.Println()
+		}
+
+	case "list":
+		// Create request
+		req := connect.NewRequest(&coupon.ListCampaignsRequest{
+			PageSize:   int32(*pageSize),
+			PageToken:  *pageToken,
+			NamePrefix: *namePrefix,
+		})
+
+		// Call API
+		resp, err := client.ListCampaigns(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error listing campaigns: %v", err)
+		}
+
+		// Print campaigns
+		fmt.Printf("Campaigns (%d):\n", len(resp.Msg.Campaigns))
+		for i, c := range resp.Msg.Campaigns {
+			fmt.Printf("%d. ID: %s, Name: %s, Status: %s, Issued: %d/%d\n",
+				i+1, c.Id, c.Name, c.Status, c.IssuedCoupons, c.TotalCoupons)
+		}
+		if resp.Msg.NextPageToken != "" {
+			fmt.Printf("\nNext page token: %s\n", resp.Msg.NextPageToken)
+		}
+
+	case "coupons":
+		// Validate campaign ID
+		if *campaignID == "" {
+			log.Fatal("Campaign ID is required for coupons command")
+		}
+
+		// Create request
+		req := connect.NewRequest(&coupon.ListCouponsRequest{
+			CampaignId: *campaignID,
+			PageSize:   int32(*pageSize),
+			PageToken:  *pageToken,
+		})
+
+		// Call API
+		resp, err := client.ListCoupons(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error listing coupons: %v", err)
+		}
+
+		// Print coupons
+		fmt.Printf("Coupons (%d):\n", len(resp.Msg.Coupons))
+		for i, c := range resp.Msg.Coupons {
+			fmt.Printf("%d. Code: %s, Status: %s, Issued At: %s\n",
+				i+1, c.Code, c.Status, c.IssuedAt.AsTime().Format(time.RFC3339))
+		}
+		if resp.Msg.NextPageToken != "" {
+			fmt.Printf("\nNext page token: %s\n", resp.Msg.NextPageToken)
+		}
+
+	case "update":
+		// Validate campaign ID
+		if *campaignID == "" {
+			log.Fatal("Campaign ID is required for update command")
+		}
+
+		// Only the flags given on the command line are updated
+		campaign := &coupon.Campaign{Id: *campaignID, Version: *version}
+		mask := &fieldmaskpb.FieldMask{}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "name":
+				campaign.Name = *campaignName
+				mask.Paths = append(mask.Paths, "name")
+			case "total":
+				campaign.TotalCoupons = int32(*totalCoupons)
+				mask.Paths = append(mask.Paths, "total_coupons")
+			case "start-in":
+				campaign.StartTime = timestamppb.New(time.Now().Add(*startIn))
+				mask.Paths = append(mask.Paths, "start_time")
+			}
+		})
+		if len(mask.Paths) == 0 {
+			log.Fatal("At least one of -name, -total, or -start-in is required for update command")
+		}
+
+		// Create request
+		req := connect.NewRequest(&coupon.UpdateCampaignRequest{
+			Campaign:   campaign,
+			UpdateMask: mask,
+		})
+
+		// Call API
+		resp, err := client.UpdateCampaign(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error updating campaign: %v", err)
+		}
+
+		// Print response
+		fmt.Printf("Campaign updated successfully!\n")
+		fmt.Printf("ID: %s\n", resp.Msg.Campaign.Id)
+		fmt.Printf("Name: %s\n", resp.Msg.Campaign.Name)
+		fmt.Printf("Total Coupons: %d\n", resp.Msg.Campaign.TotalCoupons)
+		fmt.Printf("Start Time: %s\n", resp.Msg.Campaign.StartTime.AsTime().Format(time.RFC3339))
+		fmt.Printf("Version: %d\n", resp.Msg.Campaign.Version)
+
+	case "pause", "resume":
+		// Validate campaign ID
+		if *campaignID == "" {
+			log.Fatalf("Campaign ID is required for %s command", *command)
+		}
+
+		// Call API
+		var campaign *coupon.Campaign
+		if *command == "pause" {
+			resp, err := client.PauseCampaign(context.Background(),
+				connect.NewRequest(&coupon.PauseCampaignRequest{CampaignId: *campaignID}))
+			if err != nil {
+				log.Fatalf("Error pausing campaign: %v", err)
+			}
+			campaign = resp.Msg.Campaign
+		} else {
+			resp, err := client.ResumeCampaign(context.Background(),
+				connect.NewRequest(&coupon.ResumeCampaignRequest{CampaignId: *campaignID}))
+			if err != nil {
+				log.Fatalf("Error resuming campaign: %v", err)
+			}
+			campaign = resp.Msg.Campaign
 		}
 
+		// Print result
+		fmt.Printf("Campaign %s: %s\n", campaign.Id, campaign.Status)
+
 	case "issue":
 		// Validate campaign ID
 		if *campaignID == "" {
@@ -99,6 +314,7 @@ func main() {
 		// Create request
 		req := connect.NewRequest(&coupon.IssueCouponRequest{
 			CampaignId: *campaignID,
+			UserId:     *userID,
 		})
 
 		// Call API
@@ -137,9 +353,157 @@ func main() {
 			fmt.Printf("Failed: %s\n", resp.Msg.Message)
 		}
 
+	case "redeem":
+		// Validate coupon code
+		if *code == "" {
+			log.Fatal("Coupon code is required for redeem command")
+		}
+
+		// Create request
+		req := connect.NewRequest(&coupon.RedeemCouponRequest{
+			Code: *code,
+		})
+
+		// Call API
+		resp, err := client.RedeemCoupon(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error redeeming coupon: %v", err)
+		}
+
+		// Print result
+		fmt.Printf("Coupon redeemed successfully!\n")
+		fmt.Printf("Code: %s\n", resp.Msg.Coupon.Code)
+		fmt.Printf("Campaign ID: %s\n", resp.Msg.Coupon.CampaignId)
+		fmt.Printf("Redeemed At: %s\n", resp.Msg.Coupon.RedeemedAt.AsTime().Format(time.RFC3339))
+
+	case "validate":
+		// Validate coupon code
+		if *code == "" {
+			log.Fatal("Coupon code is required for validate command")
+		}
+
+		// Create request
+		req := connect.NewRequest(&coupon.ValidateCouponRequest{
+			Code: *code,
+		})
+
+		// Call API
+		resp, err := client.ValidateCoupon(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error validating coupon: %v", err)
+		}
+
+		// Print result
+		if resp.Msg.Valid {
+			fmt.Printf("Coupon is valid!\n")
+		} else {
+			fmt.Printf("Coupon is not valid: %s\n", resp.Msg.Reason)
+		}
+		if resp.Msg.Coupon != nil {
+			fmt.Printf("Campaign ID: %s\n", resp.Msg.Coupon.CampaignId)
+			fmt.Printf("Status: %s\n", resp.Msg.Coupon.Status)
+		}
+
+	case "verify":
+		// Check a signed code offline, the way a point of sale would
+		if *code == "" || *campaignID == "" || *keysPath == "" {
+			log.Fatal("Coupon code, campaign ID and key file are required for verify command")
+		}
+
+		verifier, err := couponverify.Load(*keysPath)
+		if err != nil {
+			log.Fatalf("Error loading keys: %v", err)
+		}
+
+		signed, err := verifier.Verify(*campaignID, *code)
+		if err != nil {
+			fmt.Printf("Coupon is not genuine: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Print result
+		fmt.Printf("Coupon is genuine!\n")
+		fmt.Printf("Key ID: %d\n", signed.KeyID)
+		fmt.Printf("Serial: %d\n", signed.Serial)
+
+	case "import":
+		// Validate campaign ID and codes file
+		if *campaignID == "" || *codesPath == "" {
+			log.Fatal("Campaign ID and codes file are required for import command")
+		}
+
+		f, err := os.Open(*codesPath)
+		if err != nil {
+			log.Fatalf("Error opening codes file: %v", err)
+		}
+		defer f.Close()
+
+		// Stream the codes in batches
+		const batchSize = 1000
+		stream := client.ImportCodes(context.Background())
+		batch := &coupon.ImportCodesRequest{CampaignId: *campaignID}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				batch.Codes = append(batch.Codes, line)
+			}
+			if len(batch.Codes) < batchSize {
+				continue
+			}
+			if err := stream.Send(batch); err != nil {
+				// The server ended the stream; CloseAndReceive reports why
+				batch = nil
+				break
+			}
+			batch = &coupon.ImportCodesRequest{}
+		}
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("Error reading codes file: %v", err)
+		}
+		if batch != nil && (len(batch.Codes) > 0 || batch.CampaignId != "") {
+			_ = stream.Send(batch)
+		}
+
+		resp, err := stream.CloseAndReceive()
+		if err != nil {
+			log.Fatalf("Error importing codes: %v", err)
+		}
+
+		// Print result
+		fmt.Printf("Imported %d codes, skipped %d duplicates\n", resp.Msg.Imported, resp.Msg.Duplicates)
+		for _, duplicate := range resp.Msg.DuplicateCodes {
+			fmt.Printf("   Duplicate: %s\n", duplicate)
+		}
+		fmt.Printf("Codes available: %d\n", resp.Msg.Available)
+
+	case "reconcile":
+		// Create request
+		req := connect.NewRequest(&coupon.ReconcileCountersRequest{
+			Repair: *repair,
+		})
+
+		// Call API
+		resp, err := adminClient.ReconcileCounters(context.Background(), req)
+		if err != nil {
+			log.Fatalf("Error reconciling counters: %v", err)
+		}
+
+		// Print result
+		fmt.Printf("Checked %d campaigns (%d skipped while changing)\n", resp.Msg.CampaignsChecked, resp.Msg.CampaignsSkipped)
+		if len(resp.Msg.Drifts) == 0 {
+			fmt.Println("All issued counters match the stored coupons")
+		}
+		for i, drift := range resp.Msg.Drifts {
+			fmt.Printf("%d. Campaign %s (%s): counter %d, stored %d, repaired: %t\n",
+				i+1, drift.CampaignId, drift.CampaignName, drift.IssuedCoupons, drift.StoredCoupons, drift.Repaired)
+			for userID, diff := range drift.UserDrift {
+				fmt.Printf("   User %s: %+d\n", userID, diff)
+			}
+		}
+
 	default:
 		fmt.Printf("Unknown command: %s\n", *command)
-		fmt.Println("Available commands: create, get, issue, delete")
+		fmt.Println("Available commands: create, get, list, coupons, update, pause, resume, issue, delete, redeem, validate, verify, import, reconcile")
 		os.Exit(1)
 	}
 }
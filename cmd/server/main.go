@@ -3,12 +3,16 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"flag"
 	"fmt"
 
 	// Remove unused log import
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -18,9 +22,14 @@ import (
 	"golang.org/x/net/http2/h2c"
 
 	"github.com/rpranjan11/coupon-issuance-system/api/coupon/couponconnect"
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/file"
 	"github.com/rpranjan11/coupon-issuance-system/internal/repository/memory"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/sqlite"
 	"github.com/rpranjan11/coupon-issuance-system/internal/service"
 	"github.com/rpranjan11/coupon-issuance-system/internal/service/rpc"
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
 )
 
 const (
@@ -31,42 +40,162 @@ func main() {
 	// Set up logger
 	log := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-	// Create repositories
-	campaignRepo := memory.NewCampaignRepository()
-	couponRepo := memory.NewCouponRepository()
+	// Exit only once run has returned, so its deferred cleanup, such as
+	// closing the store, has happened
+	if err := run(log); err != nil {
+		log.Fatal().Err(err).Msg("server failed")
+	}
+}
+
+// run starts the server and blocks until it shuts down
+func run(log zerolog.Logger) error {
+	storage := flag.String("storage", "memory", "repository backend: memory, file or sqlite")
+	dataDir := flag.String("data-dir", "data", "data directory for the file and sqlite backends")
+	syncWrites := flag.Bool("sync-writes", true, "fsync the write-ahead log after every change (file backend)")
+	reconcileInterval := flag.Duration("reconcile-interval", time.Minute, "how often to compare issued counters with stored coupons (0 disables)")
+	reconcileRepair := flag.Bool("reconcile-repair", false, "release issuance slots that leaked without a stored coupon")
+	codeKeysPath := flag.String("code-keys", "", "key file for signing coupon codes; signed code mode is disabled without it")
+	pregenSize := flag.Int("pregen-size", 1000, "random codes to generate ahead of issuance per campaign (0 disables)")
+	pregenLead := flag.Duration("pregen-lead", time.Minute, "how long before a campaign starts to fill its pool of ready codes")
+	pregenInterval := flag.Duration("pregen-interval", 5*time.Second, "how often to look for campaigns that need a pool of ready codes (0 disables pre-generation)")
+	adminAddr := flag.String("admin-addr", "127.0.0.1:8081", "listen address of the admin API, which can repair counters and revoke coupons; keep it off public networks")
+	compactInterval := flag.Duration("compact-interval", 5*time.Minute, "how often to compact the write-ahead log into a snapshot (file backend, 0 disables)")
+	flag.Parse()
+
+	if *reconcileRepair && *storage == "memory" {
+		return errors.New("-reconcile-repair needs the file or sqlite backend")
+	}
+
+	// Background jobs stop when the server shuts down
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Create repositories. The service and the repositories check start and
+	// end times against the same clock.
+	clk := clock.Real{}
+	var (
+		campaignRepo repository.CampaignRepository
+		couponRepo   repository.CouponRepository
+		issuanceRepo repository.IssuanceRepository
+		codePoolRepo repository.CodePoolRepository
+	)
+	switch *storage {
+	case "memory":
+		campaigns := memory.NewCampaignRepository(clk)
+		coupons := memory.NewCouponRepository()
+		codePool := memory.NewCodePoolRepository(campaigns, coupons)
+		campaignRepo = campaigns
+		couponRepo = coupons
+		issuanceRepo = memory.NewIssuanceRepository(campaigns, coupons, codePool)
+		codePoolRepo = codePool
+	case "file":
+		store, err := file.Open(*dataDir, file.Options{SyncWrites: *syncWrites, Clock: clk})
+		if err != nil {
+			return fmt.Errorf("open file store in %s: %w", *dataDir, err)
+		}
+		defer store.Close()
+
+		if *compactInterval > 0 {
+			go store.RunCompaction(bgCtx, *compactInterval, func(err error) {
+				log.Error().Err(err).Msg("write-ahead log compaction failed")
+			})
+		}
+
+		campaignRepo = store.CampaignRepository()
+		couponRepo = store.CouponRepository()
+		issuanceRepo = store.IssuanceRepository()
+		codePoolRepo = store.CodePoolRepository()
+	case "sqlite":
+		if err := os.MkdirAll(*dataDir, 0o755); err != nil {
+			return fmt.Errorf("create data directory: %w", err)
+		}
+		dbPath := filepath.Join(*dataDir, "coupons.db")
+		db, err := sqlite.Open(dbPath, sqlite.Options{Clock: clk})
+		if err != nil {
+			return fmt.Errorf("open sqlite database %s: %w", dbPath, err)
+		}
+		defer db.Close()
+
+		campaignRepo = db.CampaignRepository()
+		couponRepo = db.CouponRepository()
+		issuanceRepo = db.IssuanceRepository()
+		codePoolRepo = db.CodePoolRepository()
+	default:
+		return fmt.Errorf("unknown storage backend %q", *storage)
+	}
+	log.Info().Str("storage", *storage).Msg("repositories ready")
+
+	// Configure the service and load code signing keys
+	serviceOptions := service.Options{
+		PregenSize: *pregenSize,
+		PregenLead: *pregenLead,
+		Clock:      clk,
+	}
+	if *codeKeysPath != "" {
+		keyring, err := coupongen.LoadKeyring(*codeKeysPath)
+		if err != nil {
+			return fmt.Errorf("load code signing keys from %s: %w", *codeKeysPath, err)
+		}
+		serviceOptions.CodeKeys = keyring
+		log.Info().Int("current_key_id", keyring.CurrentKeyID()).Ints("key_ids", keyring.KeyIDs()).Msg("code signing keys loaded")
+	}
 
 	// Create service
-	campaignService := service.NewCampaignService(campaignRepo, couponRepo)
+	campaignService := service.NewCampaignService(campaignRepo, couponRepo, issuanceRepo, codePoolRepo, serviceOptions)
+
+	// Generate random codes ahead of issuance, before campaigns start
+	go campaignService.RunPregeneration(bgCtx, *pregenInterval, func(err error) {
+		log.Error().Err(err).Msg("code pre-generation failed")
+	})
+	expvar.Publish("code_pregen", expvar.Func(func() any {
+		return campaignService.PregenStats()
+	}))
 
-	// Create RPC server
+	// Periodically check issued counters against the stored coupons
+	// Only the file and sqlite backends count a slot and store its coupon in
+	// one commit, which repairing drift relies on
+	reconciler := service.NewReconciler(campaignRepo, couponRepo, service.ReconcilerOptions{
+		AtomicIssuance: *storage != "memory",
+		Clock:          clk,
+	})
+	if *reconcileInterval > 0 {
+		go reconciler.Run(bgCtx, *reconcileInterval, *reconcileRepair, func(report *service.ReconcileReport, err error) {
+			if err != nil {
+				log.Error().Err(err).Msg("counter reconciliation failed")
+				return
+			}
+			for _, drift := range report.Drifts {
+				log.Warn().
+					Str("campaign_id", drift.CampaignID).
+					Int("issued_coupons", drift.IssuedCoupons).
+					Int("stored_coupons", drift.StoredCoupons).
+					Int("leaked", drift.Leaked()).
+					Interface("user_drift", drift.UserDrift).
+					Bool("repaired", drift.Repaired).
+					Msg("issued counter drift detected")
+			}
+		})
+	}
+
+	// Create RPC servers
 	couponServer := rpc.NewCouponServiceServer(campaignService)
+	adminServer := rpc.NewAdminServiceServer(campaignService, reconciler)
 
 	// Set up Connect path
 	// Change this line to use the correct function from couponconnect
 	path, handler := couponconnect.NewCouponServiceHandler(couponServer)
+	adminPath, adminHandler := couponconnect.NewAdminServiceHandler(adminServer)
 
-	// Set up middleware for logging and error handling
-	var loggingHandler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		startTime := time.Now()
-		log.Info().
-			Str("method", r.Method).
-			Str("path", r.URL.Path).
-			Str("remote_addr", r.RemoteAddr).
-			Msg("request started")
+	// Set up routes. The admin API gets a listener of its own, so it is not
+	// reachable wherever the public API is.
+	mux := http.NewServeMux()
+	mux.Handle(path, logRequests(log, handler))
 
-		handler.ServeHTTP(w, r)
+	adminMux := http.NewServeMux()
+	adminMux.Handle(adminPath, logRequests(log, adminHandler))
 
-		log.Info().
-			Str("method", r.Method).
-			Str("path", r.URL.Path).
-			Str("remote_addr", r.RemoteAddr).
-			Dur("elapsed", time.Since(startTime)).
-			Msg("request completed")
-	})
-
-	// Set up routes
-	mux := http.NewServeMux()
-	mux.Handle(path, loggingHandler)
+	// Expose metrics, including code pool depth and refill lag
+	mux.Handle("/debug/vars", expvar.Handler())
 
 	// Add health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -80,27 +209,72 @@ func main() {
 		Handler: h2c.NewHandler(mux, &http2.Server{}),
 	}
 
-	// Start server in a goroutine
+	adminHTTPServer := &http.Server{
+		Addr:    *adminAddr,
+		Handler: h2c.NewHandler(adminMux, &http2.Server{}),
+	}
+
+	// Start servers in goroutines; a server that cannot serve shuts down both
+	serveErr := make(chan error, 2)
 	go func() {
 		log.Info().Int("port", port).Msg("starting server")
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal().Err(err).Msg("server failed")
+			serveErr <- fmt.Errorf("serve: %w", err)
+		}
+	}()
+	go func() {
+		log.Info().Str("addr", *adminAddr).Msg("starting admin server")
+		if err := adminHTTPServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- fmt.Errorf("serve admin API: %w", err)
 		}
 	}()
 
 	// Set up graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	var failure error
+	select {
+	case <-quit:
+	case failure = <-serveErr:
+	}
 
 	log.Info().Msg("shutting down server")
+	stopBackground()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal().Err(err).Msg("server forced to shutdown")
+	if err := adminHTTPServer.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("admin server forced to shutdown")
+	}
+	if err := server.Shutdown(ctx); err != nil && failure == nil {
+		failure = fmt.Errorf("server forced to shutdown: %w", err)
+	}
+	if failure != nil {
+		return failure
 	}
 
 	log.Info().Msg("server exited gracefully")
+	return nil
+}
+
+// logRequests logs the start and end of every request handled by next
+func logRequests(log zerolog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		log.Info().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Msg("request started")
+
+		next.ServeHTTP(w, r)
+
+		log.Info().
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Dur("elapsed", time.Since(startTime)).
+			Msg("request completed")
+	})
 }
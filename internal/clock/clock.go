@@ -0,0 +1,30 @@
+// internal/clock/clock.go
+
+// Package clock reads the current time for time-based logic, so that logic
+// can be driven by a fake clock in tests instead of sleeping.
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system clock
+type Real struct{}
+
+// Now returns the current system time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// OrReal returns c, or the system clock if c is nil
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real{}
+	}
+	return c
+}
@@ -0,0 +1,50 @@
+// internal/clock/fakeclock/fakeclock.go
+
+// Package fakeclock provides a clock.Clock that only moves when told to
+package fakeclock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+)
+
+// Clock is a clock.Clock that stands still until it is set or advanced.
+// It is safe for concurrent use.
+type Clock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+var _ clock.Clock = (*Clock)(nil)
+
+// New creates a fake clock showing now
+func New(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the time the clock shows
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Set moves the clock to now, which may be in the past
+func (c *Clock) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the clock forward by d and returns the new time
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+	return c.now
+}
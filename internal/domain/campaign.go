@@ -2,29 +2,130 @@
 package domain
 
 import (
+	"slices"
 	"time"
 )
 
+// CampaignStatus is the issuance state of a campaign at a point in time
+type CampaignStatus string
+
+const (
+	CampaignStatusScheduled CampaignStatus = "scheduled"
+	CampaignStatusActive    CampaignStatus = "active"
+	CampaignStatusSoldOut   CampaignStatus = "sold_out"
+	CampaignStatusEnded     CampaignStatus = "ended"
+	CampaignStatusPaused    CampaignStatus = "paused"
+)
+
+// CodeMode selects how coupon codes are generated for a campaign
+type CodeMode string
+
+const (
+	// CodeModeRandom draws random codes; the empty mode means the same
+	CodeModeRandom CodeMode = "random"
+	// CodeModePermuted maps each code serial through a keyed permutation
+	CodeModePermuted CodeMode = "permuted"
+	// CodeModeSigned signs each code serial with the server's code signing key
+	CodeModeSigned CodeMode = "signed"
+	// CodeModeImported hands out codes imported into the campaign's code pool
+	CodeModeImported CodeMode = "imported"
+)
+
 type Campaign struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	TotalCoupons  int       `json:"total_coupons"`
-	IssuedCoupons int       `json:"issued_coupons"`
-	StartTime     time.Time `json:"start_time"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                string        `json:"id"`
+	Name              string        `json:"name"`
+	TotalCoupons      int           `json:"total_coupons"`
+	IssuedCoupons     int           `json:"issued_coupons"`
+	MaxCouponsPerUser int           `json:"max_coupons_per_user"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time,omitempty"` // zero value means the campaign never ends
+	CreatedAt         time.Time     `json:"created_at"`
+	Version           int64         `json:"version"` // incremented on every settings change
+	Paused            bool          `json:"paused"`  // issuance is stopped while paused
+	CodeMode          CodeMode      `json:"code_mode,omitempty"`
+	CodeKey           []byte        `json:"code_key,omitempty"`      // secret key of the permuted code mode
+	CodeSerial        int64         `json:"code_serial,omitempty"`   // code serials handed out so far; never decreases
+	CodeTemplate      *CodeTemplate `json:"code_template,omitempty"` // nil means the default code format
+}
+
+// CodeTemplate describes what a campaign's codes look like
+type CodeTemplate struct {
+	Prefix    string `json:"prefix,omitempty"`
+	Alphabet  string `json:"alphabet"`            // hangul, latin or digits
+	Groups    []int  `json:"groups"`              // group sizes, check character included
+	Separator string `json:"separator,omitempty"` // put between groups
+}
+
+// Clone returns a deep copy of the campaign that shares no memory with it
+func (c *Campaign) Clone() *Campaign {
+	clone := *c
+	clone.CodeKey = slices.Clone(c.CodeKey)
+	if c.CodeTemplate != nil {
+		template := *c.CodeTemplate
+		template.Groups = slices.Clone(c.CodeTemplate.Groups)
+		clone.CodeTemplate = &template
+	}
+	return &clone
+}
+
+// UsesPermutedCodes checks if the campaign's codes come from a keyed permutation
+func (c *Campaign) UsesPermutedCodes() bool {
+	return c.CodeMode == CodeModePermuted
 }
 
-// CanIssue checks if a coupon can be issued for this campaign
-func (c *Campaign) CanIssue() bool {
-	return c.IssuedCoupons < c.TotalCoupons && time.Now().After(c.StartTime)
+// UsesImportedCodes checks if the campaign's codes come from its code pool
+func (c *Campaign) UsesImportedCodes() bool {
+	return c.CodeMode == CodeModeImported
 }
 
-// HasStarted checks if the campaign has started
-func (c *Campaign) HasStarted() bool {
-	return time.Now().After(c.StartTime)
+// UsesSignedCodes checks if the campaign's codes are signed for offline verification
+func (c *Campaign) UsesSignedCodes() bool {
+	return c.CodeMode == CodeModeSigned
+}
+
+// CanIssue checks if a coupon can be issued for this campaign at now
+func (c *Campaign) CanIssue(now time.Time) bool {
+	return c.IssuedCoupons < c.TotalCoupons && c.HasStarted(now) && !c.HasEnded(now)
+}
+
+// HasStarted checks if the campaign has started at now. A campaign starts
+// at its start time, not after it.
+func (c *Campaign) HasStarted(now time.Time) bool {
+	return !now.Before(c.StartTime)
+}
+
+// HasEndTime checks if the campaign has a closing time
+func (c *Campaign) HasEndTime() bool {
+	return !c.EndTime.IsZero()
+}
+
+// HasEnded checks if the campaign has passed its closing time at now
+func (c *Campaign) HasEnded(now time.Time) bool {
+	return c.HasEndTime() && !now.Before(c.EndTime)
 }
 
 // RemainingCoupons returns the number of remaining coupons
 func (c *Campaign) RemainingCoupons() int {
 	return c.TotalCoupons - c.IssuedCoupons
 }
+
+// HasUserLimit checks if the campaign restricts how many coupons a single user can receive
+func (c *Campaign) HasUserLimit() bool {
+	return c.MaxCouponsPerUser > 0
+}
+
+// Status returns the issuance state of the campaign at now
+func (c *Campaign) Status(now time.Time) CampaignStatus {
+	switch {
+	case c.Paused:
+		return CampaignStatusPaused
+	case !c.HasStarted(now):
+		return CampaignStatusScheduled
+	case c.IssuedCoupons >= c.TotalCoupons:
+		return CampaignStatusSoldOut
+	case c.HasEnded(now):
+		return CampaignStatusEnded
+	default:
+		return CampaignStatusActive
+	}
+}
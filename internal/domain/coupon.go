@@ -5,8 +5,27 @@ import (
 	"time"
 )
 
+// CouponStatus is the lifecycle state of a coupon
+type CouponStatus string
+
+const (
+	CouponStatusIssued   CouponStatus = "issued"
+	CouponStatusRedeemed CouponStatus = "redeemed"
+	CouponStatusExpired  CouponStatus = "expired"
+	CouponStatusRevoked  CouponStatus = "revoked"
+)
+
 type Coupon struct {
-	Code       string    `json:"code"`
-	CampaignID string    `json:"campaign_id"`
-	IssuedAt   time.Time `json:"issued_at"`
+	Code       string       `json:"code"`
+	CampaignID string       `json:"campaign_id"`
+	UserID     string       `json:"user_id,omitempty"`
+	Status     CouponStatus `json:"status"`
+	IssuedAt   time.Time    `json:"issued_at"`
+	RedeemedAt time.Time    `json:"redeemed_at,omitempty"`
+}
+
+// Clone returns a copy of the coupon
+func (c *Coupon) Clone() *Coupon {
+	clone := *c
+	return &clone
 }
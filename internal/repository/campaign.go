@@ -3,11 +3,19 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
 )
 
-// CampaignRepository defines the interface for campaign persistence
+// CampaignRepository defines the interface for campaign persistence.
+//
+// Campaigns passed in are copied before they are stored, and campaigns
+// returned are copies owned by the caller: the repository never changes them
+// afterwards, and changing them does not change the stored campaign. Returned
+// campaigns can therefore be read without locking, but they are snapshots;
+// counters such as IssuedCoupons are only current as of the read.
 type CampaignRepository interface {
 	// Create saves a new campaign
 	Create(ctx context.Context, campaign *domain.Campaign) error
@@ -15,12 +23,36 @@ type CampaignRepository interface {
 	// Get retrieves a campaign by ID
 	Get(ctx context.Context, id string) (*domain.Campaign, error)
 
-	// Update updates an existing campaign
+	// Update replaces the settings of an existing campaign if campaign.Version
+	// matches the stored version, and stores the campaign with the next version.
+	// The issued counter, the paused flag and the code serial are owned by
+	// AtomicIncrementIssued, SetPaused and NextCodeSerial and are never overwritten; TotalCoupons may not drop below
+	// the issued counter.
+	// On success campaign.Version and campaign.IssuedCoupons reflect the stored campaign.
+	// Returns ErrVersionConflict if the campaign changed since it was read.
 	Update(ctx context.Context, campaign *domain.Campaign) error
 
-	// AtomicIncrementIssued atomically increments the issued_coupons counter
+	// AtomicIncrementIssued atomically increments the issued_coupons counter.
+	// When userID is not empty, the per-user counter is incremented in the same
+	// step and the campaign's MaxCouponsPerUser is enforced.
 	// Returns true if increment was successful, false if total was reached
-	AtomicIncrementIssued(ctx context.Context, campaignID string) (bool, error)
+	AtomicIncrementIssued(ctx context.Context, campaignID, userID string) (bool, error)
+
+	// IssuedByUser returns how many coupons each user has been issued for a campaign
+	IssuedByUser(ctx context.Context, campaignID string) (map[string]int, error)
+
+	// ReleaseIssued gives back issuance slots that were counted but never
+	// turned into stored coupons: the issued counter drops by total and each
+	// user's counter by byUser[userID]. Counters never drop below zero.
+	ReleaseIssued(ctx context.Context, campaignID string, total int, byUser map[string]int) error
+
+	// NextCodeSerial hands out the campaign's next code serial, starting at 0.
+	// Serials are never handed out twice, even when issuance fails afterwards.
+	NextCodeSerial(ctx context.Context, campaignID string) (int64, error)
+
+	// SetPaused pauses or resumes issuance for a campaign and returns the updated campaign.
+	// While paused, AtomicIncrementIssued fails with ErrCampaignPaused.
+	SetPaused(ctx context.Context, id string, paused bool) (*domain.Campaign, error)
 
 	// FindByName finds a campaign by its name
 	FindByName(ctx context.Context, name string) (*domain.Campaign, error)
@@ -30,4 +62,35 @@ type CampaignRepository interface {
 
 	// DeleteByName deletes a campaign by name
 	DeleteByName(ctx context.Context, name string) (bool, error)
+
+	// List returns one page of campaigns matching the filter, ordered by
+	// creation time and then ID, along with the token of the next page.
+	// The next token is empty when there are no more campaigns.
+	List(ctx context.Context, filter CampaignFilter, page Page) ([]*domain.Campaign, string, error)
+}
+
+// CampaignFilter narrows down the campaigns returned by List.
+// Zero-valued fields do not filter.
+type CampaignFilter struct {
+	Status        domain.CampaignStatus
+	NamePrefix    string
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+}
+
+// Matches checks if a campaign satisfies the filter, judging its status at now
+func (f CampaignFilter) Matches(campaign *domain.Campaign, now time.Time) bool {
+	if f.Status != "" && campaign.Status(now) != f.Status {
+		return false
+	}
+	if f.NamePrefix != "" && !strings.HasPrefix(campaign.Name, f.NamePrefix) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && campaign.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !campaign.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
 }
@@ -0,0 +1,20 @@
+// internal/repository/codepool.go
+package repository
+
+import (
+	"context"
+)
+
+// CodePoolRepository stores coupon codes imported for a campaign until
+// IssuanceRepository.IssuePooledCoupon hands them out
+type CodePoolRepository interface {
+	// AddCodes adds codes to the end of a campaign's pool as a single unit.
+	// Codes that are already in a pool, taken by a coupon or repeated within
+	// codes are not added; they are returned as duplicates instead.
+	// Returns ErrCampaignNotFound if the campaign does not exist.
+	AddCodes(ctx context.Context, campaignID string, codes []string) ([]string, error)
+	// CountCodes returns the number of codes left in a campaign's pool
+	CountCodes(ctx context.Context, campaignID string) (int, error)
+	// DeleteByCampaignID removes a campaign's pool
+	DeleteByCampaignID(ctx context.Context, campaignID string) error
+}
@@ -3,18 +3,52 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
 )
 
-// CouponRepository defines the interface for coupon persistence
+// CouponRepository defines the interface for coupon persistence.
+// Coupons follow the same ownership rules as in CampaignRepository: they are
+// copied on the way in and the caller owns every coupon returned.
 type CouponRepository interface {
 	// Create saves a new coupon
+	// Returns ErrDuplicateCouponCode if the code is already in use
 	Create(ctx context.Context, coupon *domain.Coupon) error
 
-	// GetByCampaign retrieves all coupons for a campaign
-	GetByCampaign(ctx context.Context, campaignID string) ([]*domain.Coupon, error)
+	// GetByCode retrieves a coupon by its code
+	GetByCode(ctx context.Context, code string) (*domain.Coupon, error)
+
+	// Redeem atomically transitions an issued coupon to redeemed.
+	// Only one caller can redeem a given coupon; every other attempt fails
+	// with ErrCouponAlreadyRedeemed (or ErrCouponExpired/ErrCouponRevoked).
+	Redeem(ctx context.Context, code string, redeemedAt time.Time) (*domain.Coupon, error)
+
+	// Revoke atomically transitions an issued coupon to revoked, failing like
+	// Redeem for a coupon that is no longer issued
+	Revoke(ctx context.Context, code string) (*domain.Coupon, error)
+
+	// GetByCampaign retrieves one page of a campaign's coupons ordered by
+	// issue time and then code, along with the token of the next page.
+	// An empty Page returns every coupon.
+	GetByCampaign(ctx context.Context, campaignID string, page Page) ([]*domain.Coupon, string, error)
+
+	// CountByCampaign counts a campaign's stored coupons in total and per user.
+	// Coupons issued without a user only count towards the total.
+	CountByCampaign(ctx context.Context, campaignID string) (int, map[string]int, error)
 
 	// DeleteByCampaignID deletes all coupons for a specific campaign
 	DeleteByCampaignID(ctx context.Context, campaignID string) error
 }
+
+// CountByUser tallies the users of n coupons, where at returns the i-th coupon.
+// Coupons without a user are skipped.
+func CountByUser(n int, at func(i int) *domain.Coupon) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		if userID := at(i).UserID; userID != "" {
+			counts[userID]++
+		}
+	}
+	return counts
+}
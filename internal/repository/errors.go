@@ -0,0 +1,25 @@
+// internal/repository/errors.go
+package repository
+
+import "errors"
+
+// Errors returned by repository implementations
+var (
+	ErrCampaignNotFound   = errors.New("campaign not found")
+	ErrLimitReached       = errors.New("coupon limit reached")
+	ErrUserLimitReached   = errors.New("user coupon limit reached")
+	ErrCampaignNotStarted = errors.New("campaign has not started yet")
+	ErrCampaignEnded      = errors.New("campaign has ended")
+	ErrCampaignPaused     = errors.New("campaign is paused")
+	ErrInvalidPageToken   = errors.New("invalid page token")
+	ErrVersionConflict    = errors.New("campaign was modified concurrently")
+	ErrTotalBelowIssued   = errors.New("total coupons cannot be less than issued coupons")
+
+	ErrCouponNotFound        = errors.New("coupon not found")
+	ErrDuplicateCouponCode   = errors.New("coupon code already exists")
+	ErrCouponAlreadyRedeemed = errors.New("coupon has already been redeemed")
+	ErrCouponExpired         = errors.New("coupon has expired")
+	ErrCouponRevoked         = errors.New("coupon has been revoked")
+
+	ErrCodePoolEmpty = errors.New("code pool is empty")
+)
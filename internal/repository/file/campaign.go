@@ -0,0 +1,247 @@
+// internal/repository/file/campaign.go
+package file
+
+import (
+	"context"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CampaignRepository is a file-backed implementation of repository.CampaignRepository
+type CampaignRepository struct {
+	store *Store
+}
+
+var _ repository.CampaignRepository = (*CampaignRepository)(nil)
+
+// Create saves a new campaign
+func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	return r.store.commit(&record{Op: opPutCampaign, Campaign: campaign})
+}
+
+// Get retrieves a campaign by ID
+func (r *CampaignRepository) Get(ctx context.Context, id string) (*domain.Campaign, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	campaign, exists := r.store.state.campaigns[id]
+	if !exists {
+		return nil, repository.ErrCampaignNotFound
+	}
+
+	return campaign.Clone(), nil
+}
+
+// Update replaces the settings of an existing campaign if its version matches
+func (r *CampaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	stored, exists := r.store.state.campaigns[campaign.ID]
+	if !exists {
+		return repository.ErrCampaignNotFound
+	}
+
+	if stored.Version != campaign.Version {
+		return repository.ErrVersionConflict
+	}
+
+	if campaign.TotalCoupons < stored.IssuedCoupons {
+		return repository.ErrTotalBelowIssued
+	}
+
+	updated := *campaign
+	updated.IssuedCoupons = stored.IssuedCoupons
+	updated.Paused = stored.Paused
+	updated.CodeSerial = stored.CodeSerial
+	updated.Version = stored.Version + 1
+	if err := r.store.commit(&record{Op: opPutCampaign, Campaign: &updated}); err != nil {
+		return err
+	}
+
+	campaign.IssuedCoupons = updated.IssuedCoupons
+	campaign.Paused = updated.Paused
+	campaign.CodeSerial = updated.CodeSerial
+	campaign.Version = updated.Version
+	return nil
+}
+
+// AtomicIncrementIssued atomically increments the issued_coupons counter
+// and, when userID is set, the number of coupons issued to that user
+func (r *CampaignRepository) AtomicIncrementIssued(ctx context.Context, campaignID, userID string) (bool, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if err := r.store.checkIssue(campaignID, userID); err != nil {
+		return false, err
+	}
+
+	if err := r.store.commit(&record{Op: opIssue, CampaignID: campaignID, UserID: userID}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// checkIssue checks that the campaign can issue a coupon to userID.
+// The caller must hold s.mutex.
+func (s *Store) checkIssue(campaignID, userID string) error {
+	campaign, exists := s.state.campaigns[campaignID]
+	if !exists {
+		return repository.ErrCampaignNotFound
+	}
+
+	if campaign.Paused {
+		return repository.ErrCampaignPaused
+	}
+
+	if campaign.IssuedCoupons >= campaign.TotalCoupons {
+		return repository.ErrLimitReached
+	}
+
+	now := s.opts.Clock.Now()
+	if !campaign.HasStarted(now) {
+		return repository.ErrCampaignNotStarted
+	}
+
+	if campaign.HasEnded(now) {
+		return repository.ErrCampaignEnded
+	}
+
+	if userID != "" && campaign.HasUserLimit() &&
+		s.state.userIssued[campaignID][userID] >= campaign.MaxCouponsPerUser {
+		return repository.ErrUserLimitReached
+	}
+
+	return nil
+}
+
+// IssuedByUser returns how many coupons each user has been issued for a campaign
+func (r *CampaignRepository) IssuedByUser(ctx context.Context, campaignID string) (map[string]int, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	if _, exists := r.store.state.campaigns[campaignID]; !exists {
+		return nil, repository.ErrCampaignNotFound
+	}
+
+	issued := make(map[string]int, len(r.store.state.userIssued[campaignID]))
+	for userID, count := range r.store.state.userIssued[campaignID] {
+		issued[userID] = count
+	}
+	return issued, nil
+}
+
+// ReleaseIssued gives back issuance slots that never became stored coupons
+func (r *CampaignRepository) ReleaseIssued(ctx context.Context, campaignID string, total int, byUser map[string]int) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.campaigns[campaignID]; !exists {
+		return repository.ErrCampaignNotFound
+	}
+
+	return r.store.commit(&record{Op: opRelease, CampaignID: campaignID, Count: total, UserCounts: byUser})
+}
+
+// NextCodeSerial hands out the campaign's next code serial
+func (r *CampaignRepository) NextCodeSerial(ctx context.Context, campaignID string) (int64, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	campaign, exists := r.store.state.campaigns[campaignID]
+	if !exists {
+		return 0, repository.ErrCampaignNotFound
+	}
+
+	serial := campaign.CodeSerial
+	if err := r.store.commit(&record{Op: opNextSerial, CampaignID: campaignID}); err != nil {
+		return 0, err
+	}
+	return serial, nil
+}
+
+// SetPaused pauses or resumes issuance for a campaign
+func (r *CampaignRepository) SetPaused(ctx context.Context, id string, paused bool) (*domain.Campaign, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	stored, exists := r.store.state.campaigns[id]
+	if !exists {
+		return nil, repository.ErrCampaignNotFound
+	}
+
+	updated := *stored
+	updated.Paused = paused
+	if err := r.store.commit(&record{Op: opPutCampaign, Campaign: &updated}); err != nil {
+		return nil, err
+	}
+
+	return r.store.state.campaigns[id].Clone(), nil
+}
+
+// FindByName finds a campaign by its name
+func (r *CampaignRepository) FindByName(ctx context.Context, name string) (*domain.Campaign, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, campaign := range r.store.state.campaigns {
+		if campaign.Name == name {
+			return campaign.Clone(), nil
+		}
+	}
+
+	return nil, repository.ErrCampaignNotFound
+}
+
+// DeleteByID deletes a campaign by ID
+func (r *CampaignRepository) DeleteByID(ctx context.Context, id string) (bool, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.campaigns[id]; !exists {
+		return false, nil
+	}
+
+	if err := r.store.commit(&record{Op: opDeleteCampaign, CampaignID: id}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteByName deletes a campaign by name
+func (r *CampaignRepository) DeleteByName(ctx context.Context, name string) (bool, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for id, campaign := range r.store.state.campaigns {
+		if campaign.Name == name {
+			if err := r.store.commit(&record{Op: opDeleteCampaign, CampaignID: id}); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// List returns one page of campaigns matching the filter, ordered by creation time and ID
+func (r *CampaignRepository) List(ctx context.Context, filter repository.CampaignFilter, page repository.Page) ([]*domain.Campaign, string, error) {
+	r.store.mutex.RLock()
+	campaigns := make([]*domain.Campaign, 0, len(r.store.state.campaigns))
+	for _, campaign := range r.store.state.campaigns {
+		campaigns = append(campaigns, campaign)
+	}
+	r.store.mutex.RUnlock()
+
+	// Stored campaigns are never modified, so they can be sorted outside the lock
+	matched, nextPageToken, err := repository.ListCampaigns(campaigns, filter, page, r.store.opts.Clock.Now())
+	if err != nil {
+		return nil, "", err
+	}
+	return repository.CloneCampaigns(matched), nextPageToken, nil
+}
@@ -0,0 +1,69 @@
+// internal/repository/file/codepool.go
+package file
+
+import (
+	"context"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CodePoolRepository is a file-backed implementation of repository.CodePoolRepository
+type CodePoolRepository struct {
+	store *Store
+}
+
+var _ repository.CodePoolRepository = (*CodePoolRepository)(nil)
+
+// AddCodes adds codes to a campaign's pool with a single log write, returning the duplicates
+func (r *CodePoolRepository) AddCodes(ctx context.Context, campaignID string, codes []string) ([]string, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.campaigns[campaignID]; !exists {
+		return nil, repository.ErrCampaignNotFound
+	}
+
+	var (
+		added      []string
+		duplicates []string
+		seen       = make(map[string]struct{}, len(codes))
+	)
+	for _, code := range codes {
+		_, repeated := seen[code]
+		_, pooled := r.store.state.poolOwners[code]
+		_, taken := r.store.state.coupons[code]
+		if repeated || pooled || taken {
+			duplicates = append(duplicates, code)
+			continue
+		}
+		seen[code] = struct{}{}
+		added = append(added, code)
+	}
+
+	if len(added) > 0 {
+		if err := r.store.commit(&record{Op: opAddCodes, CampaignID: campaignID, Codes: added}); err != nil {
+			return nil, err
+		}
+	}
+	return duplicates, nil
+}
+
+// CountCodes returns the number of codes left in a campaign's pool
+func (r *CodePoolRepository) CountCodes(ctx context.Context, campaignID string) (int, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	return len(r.store.state.pools[campaignID]), nil
+}
+
+// DeleteByCampaignID removes a campaign's pool
+func (r *CodePoolRepository) DeleteByCampaignID(ctx context.Context, campaignID string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.pools[campaignID]; !exists {
+		return nil
+	}
+
+	return r.store.commit(&record{Op: opDeleteCodes, CampaignID: campaignID})
+}
@@ -0,0 +1,45 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/repotest"
+)
+
+// openConformanceStore opens an empty store that is closed after the test
+func openConformanceStore(t *testing.T, clk clock.Clock) *Store {
+	t.Helper()
+
+	store, err := Open(t.TempDir(), Options{Clock: clk})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestCampaignRepositoryConformance(t *testing.T) {
+	repotest.TestCampaignRepository(t, func(t *testing.T, clk clock.Clock) repository.CampaignRepository {
+		return openConformanceStore(t, clk).CampaignRepository()
+	})
+}
+
+func TestCouponRepositoryConformance(t *testing.T) {
+	repotest.TestCouponRepository(t, func(t *testing.T) repository.CouponRepository {
+		return openConformanceStore(t, nil).CouponRepository()
+	})
+}
+
+func TestCodePoolRepositoryConformance(t *testing.T) {
+	repotest.TestCodePoolRepository(t, func(t *testing.T) repotest.CodePoolRepositories {
+		store := openConformanceStore(t, nil)
+		return repotest.CodePoolRepositories{
+			Campaigns: store.CampaignRepository(),
+			Coupons:   store.CouponRepository(),
+			CodePool:  store.CodePoolRepository(),
+			Issuance:  store.IssuanceRepository(),
+		}
+	})
+}
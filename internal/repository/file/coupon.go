@@ -0,0 +1,137 @@
+// internal/repository/file/coupon.go
+package file
+
+import (
+	"context"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CouponRepository is a file-backed implementation of repository.CouponRepository
+type CouponRepository struct {
+	store *Store
+}
+
+var _ repository.CouponRepository = (*CouponRepository)(nil)
+
+// Create saves a new coupon
+func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.coupons[coupon.Code]; exists {
+		return repository.ErrDuplicateCouponCode
+	}
+
+	return r.store.commit(&record{Op: opPutCoupon, Coupon: coupon})
+}
+
+// GetByCampaign retrieves one page of a campaign's coupons in issue order
+func (r *CouponRepository) GetByCampaign(ctx context.Context, campaignID string, page repository.Page) ([]*domain.Coupon, string, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	codes := r.store.state.codes[campaignID]
+	coupons, nextPageToken, err := repository.PageCoupons(len(codes), func(i int) *domain.Coupon {
+		return r.store.state.coupons[codes[i]]
+	}, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return repository.CloneCoupons(coupons), nextPageToken, nil
+}
+
+// GetByCode retrieves a coupon by its code
+func (r *CouponRepository) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	coupon, exists := r.store.state.coupons[code]
+	if !exists {
+		return nil, repository.ErrCouponNotFound
+	}
+
+	return coupon.Clone(), nil
+}
+
+// Redeem atomically transitions an issued coupon to redeemed
+func (r *CouponRepository) Redeem(ctx context.Context, code string, redeemedAt time.Time) (*domain.Coupon, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	coupon, exists := r.store.state.coupons[code]
+	if !exists {
+		return nil, repository.ErrCouponNotFound
+	}
+
+	switch coupon.Status {
+	case domain.CouponStatusRedeemed:
+		return nil, repository.ErrCouponAlreadyRedeemed
+	case domain.CouponStatusExpired:
+		return nil, repository.ErrCouponExpired
+	case domain.CouponStatusRevoked:
+		return nil, repository.ErrCouponRevoked
+	}
+
+	redeemed := *coupon
+	redeemed.Status = domain.CouponStatusRedeemed
+	redeemed.RedeemedAt = redeemedAt
+	if err := r.store.commit(&record{Op: opPutCoupon, Coupon: &redeemed}); err != nil {
+		return nil, err
+	}
+
+	return r.store.state.coupons[code].Clone(), nil
+}
+
+// Revoke atomically transitions an issued coupon to revoked
+func (r *CouponRepository) Revoke(ctx context.Context, code string) (*domain.Coupon, error) {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	coupon, exists := r.store.state.coupons[code]
+	if !exists {
+		return nil, repository.ErrCouponNotFound
+	}
+
+	switch coupon.Status {
+	case domain.CouponStatusRedeemed:
+		return nil, repository.ErrCouponAlreadyRedeemed
+	case domain.CouponStatusExpired:
+		return nil, repository.ErrCouponExpired
+	case domain.CouponStatusRevoked:
+		return nil, repository.ErrCouponRevoked
+	}
+
+	revoked := *coupon
+	revoked.Status = domain.CouponStatusRevoked
+	if err := r.store.commit(&record{Op: opPutCoupon, Coupon: &revoked}); err != nil {
+		return nil, err
+	}
+
+	return r.store.state.coupons[code].Clone(), nil
+}
+
+// CountByCampaign counts a campaign's stored coupons in total and per user
+func (r *CouponRepository) CountByCampaign(ctx context.Context, campaignID string) (int, map[string]int, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	codes := r.store.state.codes[campaignID]
+	return len(codes), repository.CountByUser(len(codes), func(i int) *domain.Coupon {
+		return r.store.state.coupons[codes[i]]
+	}), nil
+}
+
+// DeleteByCampaignID deletes all coupons for a specific campaign
+func (r *CouponRepository) DeleteByCampaignID(ctx context.Context, campaignID string) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, exists := r.store.state.codes[campaignID]; !exists {
+		return nil
+	}
+
+	return r.store.commit(&record{Op: opDeleteCoupons, CampaignID: campaignID})
+}
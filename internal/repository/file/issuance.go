@@ -0,0 +1,70 @@
+// internal/repository/file/issuance.go
+package file
+
+import (
+	"context"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// IssuanceRepository is a file-backed implementation of repository.IssuanceRepository
+type IssuanceRepository struct {
+	store *Store
+}
+
+var _ repository.IssuanceRepository = (*IssuanceRepository)(nil)
+
+// IssueCoupon counts and saves a coupon with a single log write, so after a
+// failure or a crash either both changes are replayed or neither is
+func (r *IssuanceRepository) IssueCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if err := r.store.checkIssue(coupon.CampaignID, coupon.UserID); err != nil {
+		return err
+	}
+
+	if _, exists := r.store.state.coupons[coupon.Code]; exists {
+		return repository.ErrDuplicateCouponCode
+	}
+	if _, pooled := r.store.state.poolOwners[coupon.Code]; pooled {
+		return repository.ErrDuplicateCouponCode
+	}
+
+	return r.store.commit(
+		&record{Op: opIssue, CampaignID: coupon.CampaignID, UserID: coupon.UserID},
+		&record{Op: opPutCoupon, Coupon: coupon},
+	)
+}
+
+// IssuePooledCoupon works like IssueCoupon with the oldest code of the
+// campaign's pool. Saving the coupon takes its code out of the pool when the
+// record is applied, so the pool change needs no record of its own.
+func (r *IssuanceRepository) IssuePooledCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if err := r.store.checkIssue(coupon.CampaignID, coupon.UserID); err != nil {
+		return err
+	}
+
+	// Codes are never pooled while taken by a coupon: imports skip taken
+	// codes and IssueCoupon refuses pooled ones
+	pool := r.store.state.pools[coupon.CampaignID]
+	if len(pool) == 0 {
+		return repository.ErrCodePoolEmpty
+	}
+
+	pooled := *coupon
+	pooled.Code = pool[0]
+	if err := r.store.commit(
+		&record{Op: opIssue, CampaignID: coupon.CampaignID, UserID: coupon.UserID},
+		&record{Op: opPutCoupon, Coupon: &pooled},
+	); err != nil {
+		return err
+	}
+
+	coupon.Code = pooled.Code
+	return nil
+}
@@ -0,0 +1,139 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+func openTestStore(t *testing.T, dir string) *Store {
+	t.Helper()
+
+	store, err := Open(dir, Options{})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	return store
+}
+
+func newTestCampaign() *domain.Campaign {
+	return &domain.Campaign{
+		ID:                "campaign-1",
+		Name:              "test",
+		TotalCoupons:      1,
+		MaxCouponsPerUser: 1,
+		StartTime:         time.Now().Add(-time.Minute),
+		CreatedAt:         time.Now().Add(-time.Minute),
+		Version:           1,
+	}
+}
+
+func createTestCampaign(t *testing.T, store *Store) {
+	t.Helper()
+
+	if err := store.CampaignRepository().Create(context.Background(), newTestCampaign()); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+}
+
+func newTestCoupon(code string) *domain.Coupon {
+	return &domain.Coupon{
+		Code:       code,
+		CampaignID: "campaign-1",
+		UserID:     "user-1",
+		Status:     domain.CouponStatusIssued,
+		IssuedAt:   time.Now(),
+	}
+}
+
+func TestIssueCouponFailedWriteChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir)
+	createTestCampaign(t, store)
+
+	// Make every log write fail
+	store.log.Close()
+
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE1")); err == nil {
+		t.Fatal("IssueCoupon succeeded with a broken log")
+	}
+
+	campaign, err := store.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 0 {
+		t.Fatalf("issued coupons after failed write = %d, want 0", campaign.IssuedCoupons)
+	}
+	if _, err := store.CouponRepository().GetByCode(ctx, "CODE1"); !errors.Is(err, repository.ErrCouponNotFound) {
+		t.Fatalf("GetByCode after failed write: got error %v, want %v", err, repository.ErrCouponNotFound)
+	}
+
+	// Nothing reached the disk either
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+
+	campaign, err = reopened.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign after reopen: %v", err)
+	}
+	if campaign.IssuedCoupons != 0 {
+		t.Fatalf("issued coupons after reopen = %d, want 0", campaign.IssuedCoupons)
+	}
+}
+
+func TestIssueCouponDuplicateCodeChangesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	store := openTestStore(t, t.TempDir())
+	defer store.Close()
+	createTestCampaign(t, store)
+
+	if err := store.CouponRepository().Create(ctx, newTestCoupon("TAKEN")); err != nil {
+		t.Fatalf("create coupon: %v", err)
+	}
+
+	err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("TAKEN"))
+	if !errors.Is(err, repository.ErrDuplicateCouponCode) {
+		t.Fatalf("IssueCoupon with taken code: got error %v, want %v", err, repository.ErrDuplicateCouponCode)
+	}
+
+	// The user's quota is still available
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("FRESH")); err != nil {
+		t.Fatalf("IssueCoupon with fresh code: %v", err)
+	}
+}
+
+func TestIssueCouponSurvivesReopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir)
+	createTestCampaign(t, store)
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE1")); err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("close store: %v", err)
+	}
+
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+
+	campaign, err := reopened.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 1 {
+		t.Fatalf("issued coupons = %d, want 1", campaign.IssuedCoupons)
+	}
+	if _, err := reopened.CouponRepository().GetByCode(ctx, "CODE1"); err != nil {
+		t.Fatalf("GetByCode: %v", err)
+	}
+}
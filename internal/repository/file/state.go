@@ -0,0 +1,222 @@
+// internal/repository/file/state.go
+package file
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// state is the in-memory view rebuilt from the snapshot and the log.
+// Stored campaigns and coupons are never modified in place; every change
+// replaces the entry with a new copy. Reads hand out copies of their own, so
+// callers cannot reach the stored values.
+type state struct {
+	campaigns map[string]*domain.Campaign
+	// userIssued tracks issued coupons per campaign and user
+	userIssued map[string]map[string]int
+	// coupons indexes every coupon by its code
+	coupons map[string]*domain.Coupon
+	// codes holds each campaign's coupon codes ordered by issue time and code
+	codes map[string][]string
+	// pools holds each campaign's imported, unissued codes in import order
+	pools map[string][]string
+	// poolOwners maps every pooled code to its campaign ID
+	poolOwners map[string]string
+}
+
+func newState() *state {
+	return &state{
+		campaigns:  make(map[string]*domain.Campaign),
+		userIssued: make(map[string]map[string]int),
+		coupons:    make(map[string]*domain.Coupon),
+		codes:      make(map[string][]string),
+		pools:      make(map[string][]string),
+		poolOwners: make(map[string]string),
+	}
+}
+
+// apply changes the state according to a log record
+func (st *state) apply(rec *record) {
+	switch rec.Op {
+	case opPutCampaign:
+		st.campaigns[rec.Campaign.ID] = rec.Campaign.Clone()
+
+	case opIssue:
+		stored, exists := st.campaigns[rec.CampaignID]
+		if !exists {
+			return
+		}
+		campaign := *stored
+		campaign.IssuedCoupons++
+		st.campaigns[campaign.ID] = &campaign
+
+		if rec.UserID != "" {
+			users, exists := st.userIssued[rec.CampaignID]
+			if !exists {
+				users = make(map[string]int)
+				st.userIssued[rec.CampaignID] = users
+			}
+			users[rec.UserID]++
+		}
+
+	case opRelease:
+		stored, exists := st.campaigns[rec.CampaignID]
+		if !exists {
+			return
+		}
+		campaign := *stored
+		campaign.IssuedCoupons = max(campaign.IssuedCoupons-rec.Count, 0)
+		st.campaigns[campaign.ID] = &campaign
+
+		users := st.userIssued[rec.CampaignID]
+		for userID, count := range rec.UserCounts {
+			if remaining := users[userID] - count; remaining > 0 {
+				users[userID] = remaining
+			} else {
+				delete(users, userID)
+			}
+		}
+
+	case opNextSerial:
+		stored, exists := st.campaigns[rec.CampaignID]
+		if !exists {
+			return
+		}
+		campaign := *stored
+		campaign.CodeSerial++
+		st.campaigns[campaign.ID] = &campaign
+
+	case opDeleteCampaign:
+		delete(st.campaigns, rec.CampaignID)
+		delete(st.userIssued, rec.CampaignID)
+
+	case opPutCoupon:
+		coupon := rec.Coupon.Clone()
+		_, exists := st.coupons[coupon.Code]
+		st.coupons[coupon.Code] = coupon
+		if !exists {
+			st.insertCode(coupon)
+			st.removePooled(coupon.Code)
+		}
+
+	case opDeleteCoupons:
+		for _, code := range st.codes[rec.CampaignID] {
+			delete(st.coupons, code)
+		}
+		delete(st.codes, rec.CampaignID)
+
+	case opAddCodes:
+		for _, code := range rec.Codes {
+			st.poolOwners[code] = rec.CampaignID
+		}
+		st.pools[rec.CampaignID] = append(st.pools[rec.CampaignID], rec.Codes...)
+
+	case opDeleteCodes:
+		for _, code := range st.pools[rec.CampaignID] {
+			delete(st.poolOwners, code)
+		}
+		delete(st.pools, rec.CampaignID)
+	}
+}
+
+// removePooled takes a code that a coupon now uses out of its pool. Pooled
+// issuance takes the oldest code, so it is almost always first in line.
+func (st *state) removePooled(code string) {
+	campaignID, pooled := st.poolOwners[code]
+	if !pooled {
+		return
+	}
+	delete(st.poolOwners, code)
+
+	pool := st.pools[campaignID]
+	if i := slices.Index(pool, code); i == 0 {
+		pool = pool[1:]
+	} else if i > 0 {
+		pool = slices.Delete(pool, i, i+1)
+	}
+	if len(pool) == 0 {
+		delete(st.pools, campaignID)
+		return
+	}
+	st.pools[campaignID] = pool
+}
+
+// insertCode adds a new coupon's code to its campaign's sorted code list
+func (st *state) insertCode(coupon *domain.Coupon) {
+	// Coupons almost always arrive in issue order, so walk back from the end
+	// to find the insert position that keeps the list sorted
+	codes := append(st.codes[coupon.CampaignID], coupon.Code)
+	for i := len(codes) - 1; i > 0 && repository.CouponLess(coupon, st.coupons[codes[i-1]]); i-- {
+		codes[i], codes[i-1] = codes[i-1], codes[i]
+	}
+	st.codes[coupon.CampaignID] = codes
+}
+
+// snapshot is the on-disk form of the state
+type snapshot struct {
+	// LastSeq is the sequence number of the last log record included in the snapshot
+	LastSeq    uint64                    `json:"last_seq"`
+	Campaigns  []*domain.Campaign        `json:"campaigns"`
+	UserIssued map[string]map[string]int `json:"user_issued"`
+	Coupons    []*domain.Coupon          `json:"coupons"`
+	Pools      map[string][]string       `json:"pools,omitempty"`
+}
+
+// toSnapshot captures the state as of the given sequence number. Campaigns and
+// coupons are shared since they are never modified in place; the per-user
+// counters are copied.
+func (st *state) toSnapshot(lastSeq uint64) *snapshot {
+	snap := &snapshot{
+		LastSeq:    lastSeq,
+		Campaigns:  make([]*domain.Campaign, 0, len(st.campaigns)),
+		UserIssued: make(map[string]map[string]int, len(st.userIssued)),
+		Coupons:    make([]*domain.Coupon, 0, len(st.coupons)),
+		Pools:      make(map[string][]string, len(st.pools)),
+	}
+	for _, campaign := range st.campaigns {
+		snap.Campaigns = append(snap.Campaigns, campaign)
+	}
+	for campaignID, users := range st.userIssued {
+		counts := make(map[string]int, len(users))
+		for userID, count := range users {
+			counts[userID] = count
+		}
+		snap.UserIssued[campaignID] = counts
+	}
+	for _, coupon := range st.coupons {
+		snap.Coupons = append(snap.Coupons, coupon)
+	}
+	for campaignID, pool := range st.pools {
+		snap.Pools[campaignID] = slices.Clone(pool)
+	}
+	return snap
+}
+
+// stateFromSnapshot rebuilds the state, including the code ordering, from a snapshot
+func stateFromSnapshot(snap *snapshot) *state {
+	st := newState()
+	for _, campaign := range snap.Campaigns {
+		st.campaigns[campaign.ID] = campaign
+	}
+	for campaignID, users := range snap.UserIssued {
+		st.userIssued[campaignID] = users
+	}
+
+	sort.Slice(snap.Coupons, func(i, j int) bool {
+		return repository.CouponLess(snap.Coupons[i], snap.Coupons[j])
+	})
+	for _, coupon := range snap.Coupons {
+		st.coupons[coupon.Code] = coupon
+		st.codes[coupon.CampaignID] = append(st.codes[coupon.CampaignID], coupon.Code)
+	}
+	for campaignID, pool := range snap.Pools {
+		st.pools[campaignID] = pool
+		for _, code := range pool {
+			st.poolOwners[code] = campaignID
+		}
+	}
+	return st
+}
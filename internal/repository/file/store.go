@@ -0,0 +1,370 @@
+// internal/repository/file/store.go
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+)
+
+const (
+	logFileName      = "wal.log"
+	snapshotFileName = "snapshot.json"
+)
+
+var (
+	// ErrClosed is returned for writes after the store has been closed
+	ErrClosed = errors.New("file store is closed")
+	// ErrFailed is returned for writes after a failed write could not be
+	// removed from the log again
+	ErrFailed = errors.New("file store failed after a log write error")
+)
+
+// logFile is the write-ahead log file; an interface so tests can make it fail
+type logFile interface {
+	io.Writer
+	io.ReaderAt
+	io.Seeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// Options configures a Store
+type Options struct {
+	// SyncWrites fsyncs the log after every record. Without it a crash of the
+	// machine (but not of the process) can lose the most recent changes.
+	SyncWrites bool
+	// Clock is what start and end times are checked against; nil means the
+	// system clock
+	Clock clock.Clock
+}
+
+// Store is a durable repository backend. It keeps all data in memory, appends
+// every change to a checksummed write-ahead log before applying it, replays the
+// log at startup and can compact the log into a snapshot.
+type Store struct {
+	dir  string
+	opts Options
+
+	// mutex guards the state, the log file and the sequence numbers.
+	// Writes hold it exclusively so log order matches apply order.
+	mutex   sync.RWMutex
+	state   *state
+	log     logFile
+	logSize int64
+	seq     uint64
+	closed  bool
+	// failed is set when the log may hold records past logSize that were
+	// never applied; writes are refused from then on
+	failed bool
+
+	// compactMutex prevents concurrent compactions
+	compactMutex sync.Mutex
+
+	campaigns *CampaignRepository
+	coupons   *CouponRepository
+	issuance  *IssuanceRepository
+	codePool  *CodePoolRepository
+}
+
+// Open loads the snapshot and replays the write-ahead log in dir, creating the directory if needed
+func Open(dir string, opts Options) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	opts.Clock = clock.OrReal(opts.Clock)
+	s := &Store{
+		dir:   dir,
+		opts:  opts,
+		state: newState(),
+	}
+
+	// Load the latest snapshot, if any
+	snap, err := readSnapshot(filepath.Join(dir, snapshotFileName))
+	if err != nil {
+		return nil, err
+	}
+	if snap != nil {
+		s.state = stateFromSnapshot(snap)
+		s.seq = snap.LastSeq
+	}
+
+	// Replay log records that are newer than the snapshot
+	log, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	validSize, err := readLog(log, func(rec *record) {
+		if rec.Seq <= s.seq {
+			return
+		}
+		s.state.apply(rec)
+		s.seq = rec.Seq
+	})
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	// Drop a torn record at the end so new records start on a clean line
+	if err := log.Truncate(validSize); err != nil {
+		log.Close()
+		return nil, err
+	}
+	if _, err := log.Seek(validSize, io.SeekStart); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	s.log = log
+	s.logSize = validSize
+	s.campaigns = &CampaignRepository{store: s}
+	s.coupons = &CouponRepository{store: s}
+	s.issuance = &IssuanceRepository{store: s}
+	s.codePool = &CodePoolRepository{store: s}
+	return s, nil
+}
+
+// CampaignRepository returns the store's campaign repository
+func (s *Store) CampaignRepository() *CampaignRepository {
+	return s.campaigns
+}
+
+// CouponRepository returns the store's coupon repository
+func (s *Store) CouponRepository() *CouponRepository {
+	return s.coupons
+}
+
+// IssuanceRepository returns the store's issuance repository
+func (s *Store) IssuanceRepository() *IssuanceRepository {
+	return s.issuance
+}
+
+// CodePoolRepository returns the store's code pool repository
+func (s *Store) CodePoolRepository() *CodePoolRepository {
+	return s.codePool
+}
+
+// commit appends records to the log and applies them to the state.
+// The caller must hold s.mutex for writing.
+func (s *Store) commit(records ...*record) error {
+	if s.closed {
+		return ErrClosed
+	}
+	if s.failed {
+		return ErrFailed
+	}
+
+	var buf []byte
+	for i, rec := range records {
+		rec.Seq = s.seq + uint64(i) + 1
+		line, err := encodeRecord(rec)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, line...)
+	}
+
+	// Nothing is applied unless the whole batch reached the log. A batch that
+	// did not is cut off again, or replay would apply what the caller was
+	// told had failed.
+	if _, err := s.log.Write(buf); err != nil {
+		return errors.Join(err, s.discardUncommitted())
+	}
+	if s.opts.SyncWrites {
+		if err := s.log.Sync(); err != nil {
+			return errors.Join(err, s.discardUncommitted())
+		}
+	}
+
+	s.logSize += int64(len(buf))
+	for _, rec := range records {
+		s.state.apply(rec)
+		s.seq = rec.Seq
+	}
+	return nil
+}
+
+// discardUncommitted cuts the log back to its last committed record. If that
+// fails the store is marked failed, as the log may hold unapplied records.
+// The caller must hold s.mutex.
+func (s *Store) discardUncommitted() error {
+	if err := s.log.Truncate(s.logSize); err != nil {
+		s.failed = true
+		return err
+	}
+	if _, err := s.log.Seek(s.logSize, io.SeekStart); err != nil {
+		s.failed = true
+		return err
+	}
+	return nil
+}
+
+// Compact writes the current state to a snapshot and removes the log records it covers
+func (s *Store) Compact() error {
+	s.compactMutex.Lock()
+	defer s.compactMutex.Unlock()
+
+	// Capture the state without blocking writers while the snapshot is written
+	s.mutex.RLock()
+	if s.closed {
+		s.mutex.RUnlock()
+		return ErrClosed
+	}
+	if s.failed {
+		s.mutex.RUnlock()
+		return ErrFailed
+	}
+	snap := s.state.toSnapshot(s.seq)
+	coveredSize := s.logSize
+	s.mutex.RUnlock()
+
+	if err := writeSnapshot(filepath.Join(s.dir, snapshotFileName), snap); err != nil {
+		return err
+	}
+
+	// Keep only the records appended since the snapshot was taken. If the
+	// process dies before this completes, replay skips the covered records
+	// by their sequence numbers.
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if s.failed {
+		return ErrFailed
+	}
+
+	tail := make([]byte, s.logSize-coveredSize)
+	if _, err := s.log.ReadAt(tail, coveredSize); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	logPath := filepath.Join(s.dir, logFileName)
+	if err := writeFileAtomic(logPath, tail); err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(logPath, os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := log.Seek(0, io.SeekEnd); err != nil {
+		log.Close()
+		return err
+	}
+
+	s.log.Close()
+	s.log = log
+	s.logSize = int64(len(tail))
+	return nil
+}
+
+// RunCompaction compacts the store every interval until ctx is done. It does
+// nothing if interval is not positive. Errors are passed to onError, which may be nil.
+func (s *Store) RunCompaction(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Compact(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close flushes and closes the log. Reads keep working on the in-memory state.
+func (s *Store) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if err := s.log.Sync(); err != nil {
+		s.log.Close()
+		return err
+	}
+	return s.log.Close()
+}
+
+// readSnapshot loads a snapshot file, returning nil if it does not exist
+func readSnapshot(path string) (*snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// writeSnapshot stores a snapshot file atomically
+func writeSnapshot(path string, snap *snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic replaces path with data so readers see either the old or the new content
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
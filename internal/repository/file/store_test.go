@@ -0,0 +1,103 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var errDiskFull = errors.New("disk full")
+
+// failingLog is a log file whose syncs and truncations fail on demand
+type failingLog struct {
+	logFile
+	failSync     bool
+	failTruncate bool
+}
+
+func (l *failingLog) Sync() error {
+	if l.failSync {
+		return errDiskFull
+	}
+	return l.logFile.Sync()
+}
+
+func (l *failingLog) Truncate(size int64) error {
+	if l.failTruncate {
+		return errDiskFull
+	}
+	return l.logFile.Truncate(size)
+}
+
+func TestFailedSyncIsNotReplayed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := Open(dir, Options{SyncWrites: true})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	log := &failingLog{logFile: store.log}
+	store.log = log
+	createTestCampaign(t, store)
+
+	log.failSync = true
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE1")); !errors.Is(err, errDiskFull) {
+		t.Fatalf("IssueCoupon with failing sync: got error %v, want %v", err, errDiskFull)
+	}
+
+	// The log was cut back, so later records follow the last committed one
+	log.failSync = false
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE2")); err != nil {
+		t.Fatalf("IssueCoupon after sync recovered: %v", err)
+	}
+	if err := store.Compact(); err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	if err := store.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE3")); err == nil {
+		t.Fatal("IssueCoupon past the campaign's only coupon succeeded")
+	}
+	store.Close()
+
+	reopened := openTestStore(t, dir)
+	defer reopened.Close()
+
+	if _, err := reopened.CouponRepository().GetByCode(ctx, "CODE1"); err == nil {
+		t.Fatal("coupon whose sync failed was replayed")
+	}
+	if _, err := reopened.CouponRepository().GetByCode(ctx, "CODE2"); err != nil {
+		t.Fatalf("get committed coupon after reopen: %v", err)
+	}
+	campaign, err := reopened.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign after reopen: %v", err)
+	}
+	if campaign.IssuedCoupons != 1 {
+		t.Fatalf("issued coupons after reopen = %d, want 1", campaign.IssuedCoupons)
+	}
+}
+
+func TestFailedCleanupRefusesWrites(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := Open(t.TempDir(), Options{SyncWrites: true})
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer store.Close()
+	log := &failingLog{logFile: store.log, failSync: true, failTruncate: true}
+	store.log = log
+
+	if err := store.CampaignRepository().Create(ctx, newTestCampaign()); !errors.Is(err, errDiskFull) {
+		t.Fatalf("create campaign with failing log: got error %v, want %v", err, errDiskFull)
+	}
+
+	// The log may still hold the failed record, so nothing is written after it
+	log.failSync, log.failTruncate = false, false
+	if err := store.CampaignRepository().Create(ctx, newTestCampaign()); !errors.Is(err, ErrFailed) {
+		t.Fatalf("create campaign after failed cleanup: got error %v, want %v", err, ErrFailed)
+	}
+	if err := store.Compact(); !errors.Is(err, ErrFailed) {
+		t.Fatalf("compact after failed cleanup: got error %v, want %v", err, ErrFailed)
+	}
+}
@@ -0,0 +1,26 @@
+// internal/repository/issuance.go
+package repository
+
+import (
+	"context"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+)
+
+// IssuanceRepository issues coupons, keeping a campaign's issued counters and
+// its stored coupons in step
+type IssuanceRepository interface {
+	// IssueCoupon takes one of the campaign's coupons for coupon.UserID under
+	// the same rules as CampaignRepository.AtomicIncrementIssued and saves the
+	// coupon as a single unit: if the coupon cannot be saved, the counters are
+	// left unchanged.
+	// Returns ErrDuplicateCouponCode if the code is already taken or waiting
+	// in a campaign's code pool.
+	IssueCoupon(ctx context.Context, coupon *domain.Coupon) error
+
+	// IssuePooledCoupon is IssueCoupon with the code taken from the campaign's
+	// code pool, oldest import first, and stored in coupon.Code. Pooled codes
+	// that a coupon has taken in the meantime are dropped from the pool.
+	// Returns ErrCodePoolEmpty, leaving the counters unchanged, when no code is left.
+	IssuePooledCoupon(ctx context.Context, coupon *domain.Coupon) error
+}
@@ -0,0 +1,171 @@
+// internal/repository/memory/budget.go
+package memory
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+const (
+	// maxBudgetShards bounds the shards of a budget however many cores there are
+	maxBudgetShards = 64
+	// chunksPerShard is how many refills a shard's share of the total is split
+	// into, so slots are not stranded in idle shards near the end of a campaign
+	chunksPerShard = 4
+)
+
+// budgetShard holds slots one group of issuers takes from. The padding keeps
+// shards on separate cache lines, so issuers on different cores do not slow
+// each other down.
+type budgetShard struct {
+	slots atomic.Int64
+	_     [56]byte
+}
+
+// budget hands out a campaign's issuance slots. The slots left are split
+// between per-shard budgets and a shared spare: an issuer takes a slot from a
+// shard without locking, and only a dry shard refills from the spare, or,
+// once the spare is empty too, gathers the slots left in the other shards.
+// Slots only move between the shards and the spare and are never created,
+// so no more than the total are ever handed out.
+type budget struct {
+	shards []budgetShard
+	// paused is read without the mutex by take and written with it held
+	paused atomic.Bool
+
+	// mutex guards total and spare, and is held while moving slots between
+	// the spare and the shards
+	mutex sync.Mutex
+	total int
+	spare int64
+}
+
+func newBudget(issued, total int, paused bool) *budget {
+	b := &budget{
+		shards: make([]budgetShard, min(runtime.GOMAXPROCS(0), maxBudgetShards)),
+		total:  total,
+		spare:  int64(max(total-issued, 0)),
+	}
+	b.paused.Store(paused)
+	return b
+}
+
+// take hands out one slot, failing with repository.ErrCampaignPaused or
+// ErrLimitReached if there is none to hand out
+func (b *budget) take() error {
+	if b.paused.Load() {
+		return repository.ErrCampaignPaused
+	}
+
+	// The top-level math/rand functions do not lock, which suits spreading
+	// issuers over the shards
+	shard := &b.shards[rand.Intn(len(b.shards))]
+	for {
+		slots := shard.slots.Load()
+		if slots == 0 {
+			return b.refill(shard)
+		}
+		if shard.slots.CompareAndSwap(slots, slots-1) {
+			return nil
+		}
+	}
+}
+
+// refill hands out one slot to an issuer whose shard ran dry and gives the
+// shard a chunk of the spare
+func (b *budget) refill(shard *budgetShard) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// Pausing empties the shards, so this catches issuers that saw the
+	// campaign running just before it was paused
+	if b.paused.Load() {
+		return repository.ErrCampaignPaused
+	}
+
+	if b.spare == 0 {
+		b.gather()
+	}
+	if b.spare == 0 {
+		return ErrLimitReached
+	}
+
+	chunk := min(b.spare, max(int64(b.total/(len(b.shards)*chunksPerShard)), 1))
+	b.spare -= chunk
+	shard.slots.Add(chunk - 1)
+	return nil
+}
+
+// gather moves the slots left in all shards to the spare.
+// The caller must hold b.mutex.
+func (b *budget) gather() {
+	for i := range b.shards {
+		b.spare += b.shards[i].slots.Swap(0)
+	}
+}
+
+// remaining returns the slots not handed out yet.
+// The caller must hold b.mutex.
+func (b *budget) remaining() int {
+	remaining := b.spare
+	for i := range b.shards {
+		remaining += b.shards[i].slots.Load()
+	}
+	return int(remaining)
+}
+
+// counts returns the slots handed out, the total and whether the budget is paused
+func (b *budget) counts() (int, int, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.total - b.remaining(), b.total, b.paused.Load()
+}
+
+// soldOut checks if every slot was handed out
+func (b *budget) soldOut() bool {
+	issued, total, _ := b.counts()
+	return issued >= total
+}
+
+// setTotal changes the total, failing with repository.ErrTotalBelowIssued if
+// more slots than that were handed out already
+func (b *budget) setTotal(total int) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	// Gathered first, so no slot is taken between counting and resizing
+	b.gather()
+	issued := b.total - int(b.spare)
+	if total < issued {
+		return repository.ErrTotalBelowIssued
+	}
+
+	b.total = total
+	b.spare = int64(total - issued)
+	return nil
+}
+
+// setPaused pauses or resumes the budget. No slot is handed out once pausing returns.
+func (b *budget) setPaused(paused bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.paused.Store(paused)
+	if paused {
+		b.gather()
+	}
+}
+
+// release gives back up to count slots, never more than were handed out
+func (b *budget) release(count int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	issued := b.total - b.remaining()
+	b.spare += int64(min(count, max(issued, 0)))
+}
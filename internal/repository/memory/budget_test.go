@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// takeAll takes slots from parallel goroutines until the budget runs out and
+// returns how many were handed out
+func takeAll(t *testing.T, b *budget) int {
+	t.Helper()
+
+	var (
+		taken atomic.Int64
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				err := b.take()
+				if errors.Is(err, ErrLimitReached) {
+					return
+				}
+				if err != nil {
+					t.Errorf("take: %v", err)
+					return
+				}
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	return int(taken.Load())
+}
+
+func TestBudgetNeverExceedsTotal(t *testing.T) {
+	// Spread the slots over several shards even on a single core
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	b := newBudget(100, 10000, false)
+	if len(b.shards) < 2 {
+		t.Fatalf("budget has %d shards, want several", len(b.shards))
+	}
+
+	if taken := takeAll(t, b); taken != 9900 {
+		t.Fatalf("took %d slots, want 9900", taken)
+	}
+	if issued, total, _ := b.counts(); issued != 10000 || total != 10000 {
+		t.Fatalf("counts are %d of %d, want 10000 of 10000", issued, total)
+	}
+
+	// Released and added slots can be taken again, and no more
+	b.release(10)
+	if err := b.setTotal(10005); err != nil {
+		t.Fatalf("raise total: %v", err)
+	}
+	if taken := takeAll(t, b); taken != 15 {
+		t.Fatalf("took %d slots after release, want 15", taken)
+	}
+
+	if err := b.setTotal(100); !errors.Is(err, repository.ErrTotalBelowIssued) {
+		t.Fatalf("lower total below issued: got %v, want ErrTotalBelowIssued", err)
+	}
+}
+
+func TestBudgetPauseReclaimsShards(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	b := newBudget(0, 1000, false)
+	for i := 0; i < 10; i++ {
+		if err := b.take(); err != nil {
+			t.Fatalf("take: %v", err)
+		}
+	}
+
+	b.setPaused(true)
+	if err := b.take(); !errors.Is(err, repository.ErrCampaignPaused) {
+		t.Fatalf("take while paused: got %v, want ErrCampaignPaused", err)
+	}
+	for i := range b.shards {
+		if slots := b.shards[i].slots.Load(); slots != 0 {
+			t.Fatalf("shard %d holds %d slots while paused", i, slots)
+		}
+	}
+
+	b.setPaused(false)
+	if taken := takeAll(t, b); taken != 990 {
+		t.Fatalf("took %d slots after resuming, want 990", taken)
+	}
+}
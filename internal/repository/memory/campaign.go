@@ -3,89 +3,246 @@ package memory
 
 import (
 	"context"
-	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
 	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
 	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
 )
 
 var (
-	ErrCampaignNotFound = errors.New("campaign not found")
-	ErrLimitReached     = errors.New("coupon limit reached")
+	ErrCampaignNotFound = repository.ErrCampaignNotFound
+	ErrLimitReached     = repository.ErrLimitReached
 )
 
-// CampaignRepository is an in-memory implementation of repository.CampaignRepository
+// CampaignRepository is an in-memory implementation of repository.CampaignRepository.
+// The map lock is only held to look up, add and remove campaigns; issuance
+// draws from each campaign's own sharded budget, so busy campaigns do not
+// hold up others and one busy campaign scales with the cores issuing from it.
 type CampaignRepository struct {
-	campaigns map[string]*domain.Campaign
+	campaigns map[string]*campaignEntry
 	mutex     sync.RWMutex
+	clock     clock.Clock
 }
 
-// NewCampaignRepository creates a new in-memory campaign repository
-func NewCampaignRepository() repository.CampaignRepository {
+var _ repository.CampaignRepository = (*CampaignRepository)(nil)
+
+// campaignEntry is one stored campaign. Issuance only touches budget and
+// users; the other settings are replaced as a whole by Update.
+type campaignEntry struct {
+	// settings holds everything but the fields kept in budget and codeSerial
+	settings atomic.Pointer[domain.Campaign]
+	// budget counts the issued coupons against the total and holds the paused flag
+	budget     *budget
+	codeSerial atomic.Int64
+
+	// users counts the coupons issued to each user
+	users *userCounts
+
+	// mutex serializes settings changes. It is taken before the budget's
+	// mutex when both are needed.
+	mutex sync.Mutex
+}
+
+// snapshot returns a copy of the campaign with its current counters
+func (e *campaignEntry) snapshot() *domain.Campaign {
+	campaign := e.settings.Load().Clone()
+	campaign.IssuedCoupons, campaign.TotalCoupons, campaign.Paused = e.budget.counts()
+	campaign.CodeSerial = e.codeSerial.Load()
+	return campaign
+}
+
+// NewCampaignRepository creates a new in-memory campaign repository that
+// checks start and end times against clk; nil means the system clock
+func NewCampaignRepository(clk clock.Clock) *CampaignRepository {
 	return &CampaignRepository{
-		campaigns: make(map[string]*domain.Campaign),
+		campaigns: make(map[string]*campaignEntry),
+		clock:     clock.OrReal(clk),
+	}
+}
+
+// entry looks up a campaign's entry
+func (r *CampaignRepository) entry(id string) (*campaignEntry, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entry, exists := r.campaigns[id]
+	if !exists {
+		return nil, ErrCampaignNotFound
 	}
+	return entry, nil
 }
 
 // Create saves a new campaign
 func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	entry := &campaignEntry{
+		budget: newBudget(campaign.IssuedCoupons, campaign.TotalCoupons, campaign.Paused),
+		users:  newUserCounts(),
+	}
+	entry.settings.Store(campaign.Clone())
+	entry.codeSerial.Store(campaign.CodeSerial)
+
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.campaigns[campaign.ID] = campaign
+	r.campaigns[campaign.ID] = entry
 	return nil
 }
 
 // Get retrieves a campaign by ID
 func (r *CampaignRepository) Get(ctx context.Context, id string) (*domain.Campaign, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
-
-	campaign, exists := r.campaigns[id]
-	if !exists {
-		return nil, ErrCampaignNotFound
+	entry, err := r.entry(id)
+	if err != nil {
+		return nil, err
 	}
-
-	return campaign, nil
+	return entry.snapshot(), nil
 }
 
-// Update updates an existing campaign
+// Update replaces the settings of an existing campaign if its version matches
 func (r *CampaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	entry, err := r.entry(campaign.ID)
+	if err != nil {
+		return err
+	}
 
-	_, exists := r.campaigns[campaign.ID]
-	if !exists {
-		return ErrCampaignNotFound
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	stored := entry.settings.Load()
+	if stored.Version != campaign.Version {
+		return repository.ErrVersionConflict
+	}
+
+	// Checked by the budget as it resizes, so coupons issued since the caller
+	// read the campaign are taken into account
+	if err := entry.budget.setTotal(campaign.TotalCoupons); err != nil {
+		return err
 	}
 
-	r.campaigns[campaign.ID] = campaign
+	updated := campaign.Clone()
+	updated.Version = stored.Version + 1
+	entry.settings.Store(updated)
+
+	current := entry.snapshot()
+	campaign.IssuedCoupons = current.IssuedCoupons
+	campaign.Paused = current.Paused
+	campaign.CodeSerial = current.CodeSerial
+	campaign.Version = current.Version
 	return nil
 }
 
 // AtomicIncrementIssued atomically increments the issued_coupons counter
-func (r *CampaignRepository) AtomicIncrementIssued(ctx context.Context, campaignID string) (bool, error) {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+// and, when userID is set, the number of coupons issued to that user
+func (r *CampaignRepository) AtomicIncrementIssued(ctx context.Context, campaignID, userID string) (bool, error) {
+	entry, err := r.entry(campaignID)
+	if err != nil {
+		return false, err
+	}
+	if err := entry.reserve(userID, r.clock.Now()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
-	campaign, exists := r.campaigns[campaignID]
-	if !exists {
-		return false, ErrCampaignNotFound
+// reserve checks that the campaign can issue a coupon to userID at now and
+// counts it. With a user, the user's count is checked and raised first under
+// its stripe's lock, so one user cannot exceed the cap, and given back if the
+// budget has no slot left. Until then a concurrent issuance to the same user
+// may be refused for the user limit, though it would have failed anyway.
+func (e *campaignEntry) reserve(userID string, now time.Time) error {
+	campaign := e.settings.Load()
+
+	// Refuse issuance while the campaign is paused; the budget checks again
+	if e.budget.paused.Load() {
+		return repository.ErrCampaignPaused
 	}
 
-	if campaign.IssuedCoupons >= campaign.TotalCoupons {
-		return false, ErrLimitReached
+	// Check if the campaign has started or already closed. A sold-out
+	// campaign is reported as such first, in the same order as the other
+	// backends; only failing issuers pay for counting the budget.
+	if !campaign.HasStarted(now) {
+		if e.budget.soldOut() {
+			return ErrLimitReached
+		}
+		return repository.ErrCampaignNotStarted
+	}
+	if campaign.HasEnded(now) {
+		if e.budget.soldOut() {
+			return ErrLimitReached
+		}
+		return repository.ErrCampaignEnded
 	}
 
-	// Check if the campaign has started
-	if time.Now().Before(campaign.StartTime) {
-		return false, errors.New("campaign has not started yet")
+	if userID == "" {
+		return e.budget.take()
 	}
 
-	campaign.IssuedCoupons++
-	return true, nil
+	limit := 0
+	if campaign.HasUserLimit() {
+		limit = campaign.MaxCouponsPerUser
+	}
+	if err := e.users.take(userID, limit); err != nil {
+		return err
+	}
+	if err := e.budget.take(); err != nil {
+		e.users.release(userID, 1)
+		return err
+	}
+	return nil
+}
+
+// release gives back up to count issued coupons, and those of each user in byUser
+func (e *campaignEntry) release(count int, byUser map[string]int) {
+	e.budget.release(count)
+	for userID, count := range byUser {
+		e.users.release(userID, count)
+	}
+}
+
+// IssuedByUser returns how many coupons each user has been issued for a campaign
+func (r *CampaignRepository) IssuedByUser(ctx context.Context, campaignID string) (map[string]int, error) {
+	entry, err := r.entry(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.users.counts(), nil
+}
+
+// ReleaseIssued gives back issuance slots that never became stored coupons
+func (r *CampaignRepository) ReleaseIssued(ctx context.Context, campaignID string, total int, byUser map[string]int) error {
+	entry, err := r.entry(campaignID)
+	if err != nil {
+		return err
+	}
+
+	entry.release(total, byUser)
+	return nil
+}
+
+// NextCodeSerial hands out the campaign's next code serial
+func (r *CampaignRepository) NextCodeSerial(ctx context.Context, campaignID string) (int64, error) {
+	entry, err := r.entry(campaignID)
+	if err != nil {
+		return 0, err
+	}
+
+	return entry.codeSerial.Add(1) - 1, nil
+}
+
+// SetPaused pauses or resumes issuance for a campaign
+func (r *CampaignRepository) SetPaused(ctx context.Context, id string, paused bool) (*domain.Campaign, error) {
+	entry, err := r.entry(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The budget takes back the slots its shards hold, so no issuance is
+	// counted once this returns
+	entry.budget.setPaused(paused)
+	return entry.snapshot(), nil
 }
 
 // FindByName finds a campaign by its name
@@ -93,9 +250,9 @@ func (r *CampaignRepository) FindByName(ctx context.Context, name string) (*doma
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	for _, campaign := range r.campaigns {
-		if campaign.Name == name {
-			return campaign, nil
+	for _, entry := range r.campaigns {
+		if entry.settings.Load().Name == name {
+			return entry.snapshot(), nil
 		}
 	}
 
@@ -121,8 +278,8 @@ func (r *CampaignRepository) DeleteByName(ctx context.Context, name string) (boo
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	for id, campaign := range r.campaigns {
-		if campaign.Name == name {
+	for id, entry := range r.campaigns {
+		if entry.settings.Load().Name == name {
 			delete(r.campaigns, id)
 			return true, nil
 		}
@@ -130,3 +287,15 @@ func (r *CampaignRepository) DeleteByName(ctx context.Context, name string) (boo
 
 	return false, nil
 }
+
+// List returns one page of campaigns matching the filter, ordered by creation time and ID
+func (r *CampaignRepository) List(ctx context.Context, filter repository.CampaignFilter, page repository.Page) ([]*domain.Campaign, string, error) {
+	r.mutex.RLock()
+	campaigns := make([]*domain.Campaign, 0, len(r.campaigns))
+	for _, entry := range r.campaigns {
+		campaigns = append(campaigns, entry.snapshot())
+	}
+	r.mutex.RUnlock()
+
+	return repository.ListCampaigns(campaigns, filter, page, r.clock.Now())
+}
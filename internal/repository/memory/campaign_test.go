@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+func TestAtomicIncrementIssuedNeverExceedsTotal(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCampaignRepository(clock.Real{})
+	campaign := &domain.Campaign{
+		ID:                "campaign-1",
+		Name:              "test",
+		TotalCoupons:      50,
+		MaxCouponsPerUser: 3,
+		StartTime:         time.Now().Add(-time.Minute),
+		CreatedAt:         time.Now().Add(-time.Minute),
+		Version:           1,
+	}
+	if err := repo.Create(ctx, campaign); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	var (
+		issued atomic.Int64
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := ""
+			if i%2 == 0 {
+				userID = fmt.Sprintf("user-%d", i%4)
+			}
+			for j := 0; j < 10; j++ {
+				_, err := repo.AtomicIncrementIssued(ctx, campaign.ID, userID)
+				switch {
+				case err == nil:
+					issued.Add(1)
+				case errors.Is(err, ErrLimitReached), errors.Is(err, repository.ErrUserLimitReached):
+				default:
+					t.Errorf("increment issued: %v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stored, err := repo.Get(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if stored.IssuedCoupons != 50 || issued.Load() != 50 {
+		t.Fatalf("issued %d coupons, counter %d, want 50", issued.Load(), stored.IssuedCoupons)
+	}
+
+	byUser, err := repo.IssuedByUser(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("issued by user: %v", err)
+	}
+	for userID, count := range byUser {
+		if count > campaign.MaxCouponsPerUser {
+			t.Fatalf("user %s got %d coupons, limit is %d", userID, count, campaign.MaxCouponsPerUser)
+		}
+	}
+}
+
+// TestConcurrentReadsAndIssuance hammers reads and issuance of one campaign
+// from many goroutines. Run with -race: readers also modify what they read,
+// which must never reach the stored campaign or another reader's copy.
+func TestConcurrentReadsAndIssuance(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCampaignRepository(clock.Real{})
+	campaign := &domain.Campaign{
+		ID:           "campaign-1",
+		Name:         "test",
+		TotalCoupons: 1000,
+		StartTime:    time.Now().Add(-time.Minute),
+		CreatedAt:    time.Now().Add(-time.Minute),
+		Version:      1,
+		CodeTemplate: &domain.CodeTemplate{Alphabet: "latin", Groups: []int{4, 4}},
+	}
+	if err := repo.Create(ctx, campaign); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	// The repository keeps its own copy of what it was given
+	campaign.Name = "changed by caller"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := repo.AtomicIncrementIssued(ctx, "campaign-1", ""); err != nil && !errors.Is(err, repository.ErrCampaignPaused) {
+					t.Errorf("increment issued: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			last := 0
+			for j := 0; j < 100; j++ {
+				got, err := repo.Get(ctx, "campaign-1")
+				if err != nil {
+					t.Errorf("get campaign: %v", err)
+					return
+				}
+				if got.IssuedCoupons < last || got.IssuedCoupons > got.TotalCoupons {
+					t.Errorf("issued coupons went from %d to %d of %d", last, got.IssuedCoupons, got.TotalCoupons)
+					return
+				}
+				last = got.IssuedCoupons
+				got.IssuedCoupons = -1
+				got.CodeTemplate.Groups[0] = 99
+
+				page, _, err := repo.List(ctx, repository.CampaignFilter{}, repository.Page{})
+				if err != nil || len(page) != 1 {
+					t.Errorf("list campaigns: %d campaigns, %v", len(page), err)
+					return
+				}
+				page[0].Name = "changed by reader"
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 50; j++ {
+			if _, err := repo.SetPaused(ctx, "campaign-1", j%2 == 0); err != nil {
+				t.Errorf("set paused: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	got, err := repo.Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if got.Name != "test" || got.CodeTemplate.Groups[0] != 4 {
+		t.Fatalf("stored campaign was changed through a returned copy: name %q, groups %v", got.Name, got.CodeTemplate.Groups)
+	}
+	if got.IssuedCoupons <= 0 || got.IssuedCoupons > 800 {
+		t.Fatalf("issued coupons is %d, want between 1 and 800", got.IssuedCoupons)
+	}
+}
+
+func TestNilClockMeansSystemClock(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCampaignRepository(nil)
+	campaign := &domain.Campaign{
+		ID:           "campaign-1",
+		Name:         "test",
+		TotalCoupons: 1,
+		StartTime:    time.Now().Add(-time.Minute),
+		CreatedAt:    time.Now().Add(-time.Minute),
+		Version:      1,
+	}
+	if err := repo.Create(ctx, campaign); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	if _, err := repo.AtomicIncrementIssued(ctx, "campaign-1", ""); err != nil {
+		t.Fatalf("increment issued: %v", err)
+	}
+	if _, _, err := repo.List(ctx, repository.CampaignFilter{}, repository.Page{}); err != nil {
+		t.Fatalf("list campaigns: %v", err)
+	}
+}
+
+// newBenchmarkRepository creates a repository with n running campaigns that
+// never sell out during a benchmark
+func newBenchmarkRepository(b *testing.B, n int) (*CampaignRepository, []string) {
+	b.Helper()
+
+	repo := NewCampaignRepository(clock.Real{})
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("campaign-%d", i)
+		campaign := &domain.Campaign{
+			ID:           ids[i],
+			Name:         ids[i],
+			TotalCoupons: 1 << 40,
+			StartTime:    time.Now().Add(-time.Minute),
+			CreatedAt:    time.Now().Add(-time.Minute),
+			Version:      1,
+		}
+		if err := repo.Create(context.Background(), campaign); err != nil {
+			b.Fatalf("create campaign: %v", err)
+		}
+	}
+	return repo, ids
+}
+
+// BenchmarkAtomicIncrementIssued issues from parallel goroutines spread over
+// a growing number of campaigns, with and without user IDs. With per-campaign
+// sharded budgets and striped per-user counts, throughput should grow with
+// -cpu even for a single campaign.
+func BenchmarkAtomicIncrementIssued(b *testing.B) {
+	userIDs := make([]string, 1024)
+	for i := range userIDs {
+		userIDs[i] = fmt.Sprintf("user-%d", i)
+	}
+
+	for _, campaigns := range []int{1, 16, 256} {
+		for _, withUsers := range []bool{false, true} {
+			b.Run(fmt.Sprintf("campaigns=%d/users=%t", campaigns, withUsers), func(b *testing.B) {
+				repo, ids := newBenchmarkRepository(b, campaigns)
+				var next atomic.Int64
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					worker := int(next.Add(1))
+					id := ids[worker%len(ids)]
+					for i := worker; pb.Next(); i++ {
+						userID := ""
+						if withUsers {
+							userID = userIDs[i%len(userIDs)]
+						}
+						if _, err := repo.AtomicIncrementIssued(context.Background(), id, userID); err != nil {
+							b.Errorf("increment issued: %v", err)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
+
+// BenchmarkGetWhileIssuing reads campaigns while other goroutines issue from
+// one hot campaign, the read load a flash sale puts on its neighbours
+func BenchmarkGetWhileIssuing(b *testing.B) {
+	repo, ids := newBenchmarkRepository(b, 16)
+	var next atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		worker := int(next.Add(1))
+		for pb.Next() {
+			var err error
+			if worker%2 == 0 {
+				_, err = repo.AtomicIncrementIssued(context.Background(), ids[0], "")
+			} else {
+				_, err = repo.Get(context.Background(), ids[worker%len(ids)])
+			}
+			if err != nil {
+				b.Errorf("worker %d: %v", worker, err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,120 @@
+// internal/repository/memory/codepool.go
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CodePoolRepository is an in-memory implementation of repository.CodePoolRepository
+type CodePoolRepository struct {
+	// pools holds each campaign's unissued codes in import order
+	pools map[string][]string
+	// owners maps every pooled code to its campaign ID
+	owners    map[string]string
+	campaigns *CampaignRepository
+	coupons   repository.CouponRepository
+	mutex     sync.RWMutex
+}
+
+var _ repository.CodePoolRepository = (*CodePoolRepository)(nil)
+
+// NewCodePoolRepository creates a code pool for the campaigns in campaigns
+// that checks imported codes against the coupons already saved in coupons
+func NewCodePoolRepository(campaigns *CampaignRepository, coupons repository.CouponRepository) *CodePoolRepository {
+	return &CodePoolRepository{
+		pools:     make(map[string][]string),
+		owners:    make(map[string]string),
+		campaigns: campaigns,
+		coupons:   coupons,
+	}
+}
+
+// AddCodes adds codes to a campaign's pool, returning the duplicates
+func (r *CodePoolRepository) AddCodes(ctx context.Context, campaignID string, codes []string) ([]string, error) {
+	if _, err := r.campaigns.entry(campaignID); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var (
+		added      []string
+		duplicates []string
+		seen       = make(map[string]struct{}, len(codes))
+	)
+	for _, code := range codes {
+		if _, repeated := seen[code]; repeated {
+			duplicates = append(duplicates, code)
+			continue
+		}
+		seen[code] = struct{}{}
+
+		if _, pooled := r.owners[code]; pooled {
+			duplicates = append(duplicates, code)
+			continue
+		}
+		_, err := r.coupons.GetByCode(ctx, code)
+		if err == nil {
+			duplicates = append(duplicates, code)
+			continue
+		}
+		if !errors.Is(err, repository.ErrCouponNotFound) {
+			return nil, err
+		}
+
+		added = append(added, code)
+	}
+
+	for _, code := range added {
+		r.owners[code] = campaignID
+	}
+	r.pools[campaignID] = append(r.pools[campaignID], added...)
+	return duplicates, nil
+}
+
+// CountCodes returns the number of codes left in a campaign's pool
+func (r *CodePoolRepository) CountCodes(ctx context.Context, campaignID string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.pools[campaignID]), nil
+}
+
+// DeleteByCampaignID removes a campaign's pool
+func (r *CodePoolRepository) DeleteByCampaignID(ctx context.Context, campaignID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, code := range r.pools[campaignID] {
+		delete(r.owners, code)
+	}
+	delete(r.pools, campaignID)
+	return nil
+}
+
+// peek returns the oldest code in a campaign's pool; the caller must hold mutex
+func (r *CodePoolRepository) peek(campaignID string) (string, bool) {
+	pool := r.pools[campaignID]
+	if len(pool) == 0 {
+		return "", false
+	}
+	return pool[0], true
+}
+
+// pop removes the oldest code from a campaign's pool; the caller must hold mutex
+func (r *CodePoolRepository) pop(campaignID string) {
+	pool := r.pools[campaignID]
+	delete(r.owners, pool[0])
+	if len(pool) == 1 {
+		delete(r.pools, campaignID)
+		return
+	}
+	// Clear the slot so the backing array does not keep the code alive
+	pool[0] = ""
+	r.pools[campaignID] = pool[1:]
+}
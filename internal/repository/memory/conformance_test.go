@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/repotest"
+)
+
+func TestCampaignRepositoryConformance(t *testing.T) {
+	repotest.TestCampaignRepository(t, func(t *testing.T, clk clock.Clock) repository.CampaignRepository {
+		return NewCampaignRepository(clk)
+	})
+}
+
+func TestCouponRepositoryConformance(t *testing.T) {
+	repotest.TestCouponRepository(t, func(t *testing.T) repository.CouponRepository {
+		return NewCouponRepository()
+	})
+}
+
+func TestCodePoolRepositoryConformance(t *testing.T) {
+	repotest.TestCodePoolRepository(t, func(t *testing.T) repotest.CodePoolRepositories {
+		campaigns := NewCampaignRepository(nil)
+		coupons := NewCouponRepository()
+		codePool := NewCodePoolRepository(campaigns, coupons)
+		return repotest.CodePoolRepositories{
+			Campaigns: campaigns,
+			Coupons:   coupons,
+			CodePool:  codePool,
+			Issuance:  NewIssuanceRepository(campaigns, coupons, codePool),
+		}
+	})
+}
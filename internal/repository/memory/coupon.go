@@ -4,6 +4,7 @@ package memory
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
 	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
@@ -11,14 +12,21 @@ import (
 
 // CouponRepository is an in-memory implementation of repository.CouponRepository
 type CouponRepository struct {
-	coupons map[string][]*domain.Coupon
-	mutex   sync.RWMutex
+	// codes holds each campaign's coupon codes ordered by issue time and code
+	codes map[string][]string
+	// byCode indexes every coupon by its code. Stored coupons are never
+	// modified in place and never handed out; reads return copies.
+	byCode map[string]*domain.Coupon
+	mutex  sync.RWMutex
 }
 
+var _ repository.CouponRepository = (*CouponRepository)(nil)
+
 // NewCouponRepository creates a new in-memory coupon repository
-func NewCouponRepository() repository.CouponRepository {
+func NewCouponRepository() *CouponRepository {
 	return &CouponRepository{
-		coupons: make(map[string][]*domain.Coupon),
+		codes:  make(map[string][]string),
+		byCode: make(map[string]*domain.Coupon),
 	}
 }
 
@@ -27,29 +35,113 @@ func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) er
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if _, exists := r.coupons[coupon.CampaignID]; !exists {
-		r.coupons[coupon.CampaignID] = make([]*domain.Coupon, 0)
+	if _, exists := r.byCode[coupon.Code]; exists {
+		return repository.ErrDuplicateCouponCode
 	}
 
-	r.coupons[coupon.CampaignID] = append(r.coupons[coupon.CampaignID], coupon)
+	stored := coupon.Clone()
+	r.byCode[coupon.Code] = stored
+
+	// Coupons almost always arrive in issue order, so walk back from the end
+	// to find the insert position that keeps the list sorted
+	codes := append(r.codes[coupon.CampaignID], coupon.Code)
+	for i := len(codes) - 1; i > 0 && repository.CouponLess(stored, r.byCode[codes[i-1]]); i-- {
+		codes[i], codes[i-1] = codes[i-1], codes[i]
+	}
+	r.codes[coupon.CampaignID] = codes
 	return nil
 }
 
-// GetByCampaign retrieves all coupons for a campaign
-func (r *CouponRepository) GetByCampaign(ctx context.Context, campaignID string) ([]*domain.Coupon, error) {
+// GetByCampaign retrieves one page of a campaign's coupons in issue order
+func (r *CouponRepository) GetByCampaign(ctx context.Context, campaignID string, page repository.Page) ([]*domain.Coupon, string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	codes := r.codes[campaignID]
+	coupons, nextPageToken, err := repository.PageCoupons(len(codes), func(i int) *domain.Coupon {
+		return r.byCode[codes[i]]
+	}, page)
+	if err != nil {
+		return nil, "", err
+	}
+	return repository.CloneCoupons(coupons), nextPageToken, nil
+}
+
+// GetByCode retrieves a coupon by its code
+func (r *CouponRepository) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	coupons, exists := r.coupons[campaignID]
+	coupon, exists := r.byCode[code]
+	if !exists {
+		return nil, repository.ErrCouponNotFound
+	}
+
+	return coupon.Clone(), nil
+}
+
+// Redeem atomically transitions an issued coupon to redeemed
+func (r *CouponRepository) Redeem(ctx context.Context, code string, redeemedAt time.Time) (*domain.Coupon, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	coupon, exists := r.byCode[code]
+	if !exists {
+		return nil, repository.ErrCouponNotFound
+	}
+
+	switch coupon.Status {
+	case domain.CouponStatusRedeemed:
+		return nil, repository.ErrCouponAlreadyRedeemed
+	case domain.CouponStatusExpired:
+		return nil, repository.ErrCouponExpired
+	case domain.CouponStatusRevoked:
+		return nil, repository.ErrCouponRevoked
+	}
+
+	redeemed := coupon.Clone()
+	redeemed.Status = domain.CouponStatusRedeemed
+	redeemed.RedeemedAt = redeemedAt
+	r.byCode[code] = redeemed
+
+	return redeemed.Clone(), nil
+}
+
+// Revoke atomically transitions an issued coupon to revoked
+func (r *CouponRepository) Revoke(ctx context.Context, code string) (*domain.Coupon, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	coupon, exists := r.byCode[code]
 	if !exists {
-		return make([]*domain.Coupon, 0), nil
+		return nil, repository.ErrCouponNotFound
+	}
+
+	switch coupon.Status {
+	case domain.CouponStatusRedeemed:
+		return nil, repository.ErrCouponAlreadyRedeemed
+	case domain.CouponStatusExpired:
+		return nil, repository.ErrCouponExpired
+	case domain.CouponStatusRevoked:
+		return nil, repository.ErrCouponRevoked
 	}
 
-	// Return a copy to prevent concurrent modification
-	result := make([]*domain.Coupon, len(coupons))
-	copy(result, coupons)
+	revoked := coupon.Clone()
+	revoked.Status = domain.CouponStatusRevoked
+	r.byCode[code] = revoked
 
-	return result, nil
+	return revoked.Clone(), nil
+}
+
+// CountByCampaign counts a campaign's stored coupons in total and per user
+func (r *CouponRepository) CountByCampaign(ctx context.Context, campaignID string) (int, map[string]int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	codes := r.codes[campaignID]
+	return len(codes), repository.CountByUser(len(codes), func(i int) *domain.Coupon {
+		return r.byCode[codes[i]]
+	}), nil
 }
 
 // DeleteByCampaignID deletes all coupons for a specific campaign
@@ -57,7 +149,10 @@ func (r *CouponRepository) DeleteByCampaignID(ctx context.Context, campaignID st
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	// Simply remove the entire slice of coupons for this campaign
-	delete(r.coupons, campaignID)
+	// Remove the campaign's coupons from the code index, then drop the list
+	for _, code := range r.codes[campaignID] {
+		delete(r.byCode, code)
+	}
+	delete(r.codes, campaignID)
 	return nil
 }
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// TestConcurrentCouponReads redeems and reads coupons from many goroutines
+// while readers modify what they read. Run with -race.
+func TestConcurrentCouponReads(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCouponRepository()
+
+	for i := 0; i < 100; i++ {
+		coupon := &domain.Coupon{
+			Code:       fmt.Sprintf("code-%03d", i),
+			CampaignID: "campaign-1",
+			Status:     domain.CouponStatusIssued,
+			IssuedAt:   time.Now(),
+		}
+		if err := repo.Create(ctx, coupon); err != nil {
+			t.Fatalf("create coupon: %v", err)
+		}
+		// The repository keeps its own copy of what it was given
+		coupon.Status = domain.CouponStatusRevoked
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := i; j < 100; j += 8 {
+				redeemed, err := repo.Redeem(ctx, fmt.Sprintf("code-%03d", j), time.Now())
+				if err != nil {
+					t.Errorf("redeem: %v", err)
+					return
+				}
+				redeemed.Status = domain.CouponStatusIssued
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				coupons, _, err := repo.GetByCampaign(ctx, "campaign-1", repository.Page{})
+				if err != nil {
+					t.Errorf("get coupons: %v", err)
+					return
+				}
+				for _, coupon := range coupons {
+					if coupon.Status != domain.CouponStatusIssued && coupon.Status != domain.CouponStatusRedeemed {
+						t.Errorf("coupon %s has status %s", coupon.Code, coupon.Status)
+						return
+					}
+					coupon.Status = domain.CouponStatusExpired
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		coupon, err := repo.GetByCode(ctx, fmt.Sprintf("code-%03d", i))
+		if err != nil {
+			t.Fatalf("get coupon: %v", err)
+		}
+		if coupon.Status != domain.CouponStatusRedeemed {
+			t.Fatalf("coupon %s has status %s, want redeemed", coupon.Code, coupon.Status)
+		}
+	}
+	if _, err := repo.Redeem(ctx, "code-000", time.Now()); !errors.Is(err, repository.ErrCouponAlreadyRedeemed) {
+		t.Fatalf("redeem twice: got %v, want ErrCouponAlreadyRedeemed", err)
+	}
+}
@@ -0,0 +1,116 @@
+// internal/repository/memory/issuance.go
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// IssuanceRepository is an in-memory implementation of repository.IssuanceRepository
+type IssuanceRepository struct {
+	campaigns *CampaignRepository
+	coupons   repository.CouponRepository
+	codePool  *CodePoolRepository
+}
+
+var _ repository.IssuanceRepository = (*IssuanceRepository)(nil)
+
+// NewIssuanceRepository creates an issuance repository that counts coupons in
+// campaigns, takes pooled codes from codePool and saves the coupons to coupons
+func NewIssuanceRepository(campaigns *CampaignRepository, coupons repository.CouponRepository, codePool *CodePoolRepository) *IssuanceRepository {
+	return &IssuanceRepository{
+		campaigns: campaigns,
+		coupons:   coupons,
+		codePool:  codePool,
+	}
+}
+
+// IssueCoupon counts the coupon against the campaign's limits, then saves it
+// and gives the slot back if the save fails, so a failed save never consumes
+// a slot. Only the campaign's own counter is contended.
+func (r *IssuanceRepository) IssueCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	entry, err := r.campaigns.entry(coupon.CampaignID)
+	if err != nil {
+		return err
+	}
+	if err := entry.reserve(coupon.UserID, r.campaigns.clock.Now()); err != nil {
+		return err
+	}
+
+	err = r.saveCoupon(ctx, coupon)
+	if err != nil {
+		entry.release(1, userCount(coupon.UserID))
+	}
+	return err
+}
+
+// saveCoupon saves a coupon with a generated code. The pool lock is held for
+// reading while saving, so the code cannot be imported meanwhile.
+func (r *IssuanceRepository) saveCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	r.codePool.mutex.RLock()
+	defer r.codePool.mutex.RUnlock()
+
+	if _, pooled := r.codePool.owners[coupon.Code]; pooled {
+		return repository.ErrDuplicateCouponCode
+	}
+	return r.coupons.Create(ctx, coupon)
+}
+
+// IssuePooledCoupon works like IssueCoupon with the code taken from the
+// campaign's pool. The pool lock is held from taking the code until the
+// coupon is saved, so a code is never handed out twice.
+func (r *IssuanceRepository) IssuePooledCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	entry, err := r.campaigns.entry(coupon.CampaignID)
+	if err != nil {
+		return err
+	}
+	if err := entry.reserve(coupon.UserID, r.campaigns.clock.Now()); err != nil {
+		return err
+	}
+
+	err = r.savePooledCoupon(ctx, coupon)
+	if err != nil {
+		entry.release(1, userCount(coupon.UserID))
+	}
+	return err
+}
+
+// savePooledCoupon saves a coupon with the oldest code of the campaign's pool
+func (r *IssuanceRepository) savePooledCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	r.codePool.mutex.Lock()
+	defer r.codePool.mutex.Unlock()
+
+	for {
+		code, ok := r.codePool.peek(coupon.CampaignID)
+		if !ok {
+			return repository.ErrCodePoolEmpty
+		}
+
+		coupon.Code = code
+		err := r.coupons.Create(ctx, coupon)
+		if errors.Is(err, repository.ErrDuplicateCouponCode) {
+			// Imports skip taken codes, but a coupon saved through
+			// CouponRepository.Create directly may have taken it since
+			r.codePool.pop(coupon.CampaignID)
+			continue
+		}
+		if err != nil {
+			coupon.Code = ""
+			return err
+		}
+
+		r.codePool.pop(coupon.CampaignID)
+		return nil
+	}
+}
+
+// userCount is the per-user part of releasing one coupon issued to userID
+func userCount(userID string) map[string]int {
+	if userID == "" {
+		return nil
+	}
+	return map[string]int{userID: 1}
+}
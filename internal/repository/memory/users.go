@@ -0,0 +1,83 @@
+// internal/repository/memory/users.go
+package memory
+
+import (
+	"hash/maphash"
+	"sync"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// userStripes is the number of locks a campaign's per-user counts are spread over
+const userStripes = 64
+
+// userStripe holds the counts of the users hashed to it. The padding keeps
+// stripes on separate cache lines, like the budget's shards.
+type userStripe struct {
+	mutex  sync.Mutex
+	issued map[string]int
+	_      [48]byte
+}
+
+// userCounts tracks how many coupons each user of a campaign was issued.
+// Users are spread over striped locks, so issuing to one user only waits
+// for issuers of users on the same stripe, never for the whole campaign.
+type userCounts struct {
+	seed    maphash.Seed
+	stripes [userStripes]userStripe
+}
+
+func newUserCounts() *userCounts {
+	return &userCounts{seed: maphash.MakeSeed()}
+}
+
+// stripe returns the stripe holding userID's count
+func (u *userCounts) stripe(userID string) *userStripe {
+	return &u.stripes[maphash.String(u.seed, userID)%userStripes]
+}
+
+// take counts one coupon for userID, failing with
+// repository.ErrUserLimitReached if the user already has limit coupons.
+// A limit of 0 means no limit.
+func (u *userCounts) take(userID string, limit int) error {
+	stripe := u.stripe(userID)
+	stripe.mutex.Lock()
+	defer stripe.mutex.Unlock()
+
+	if limit > 0 && stripe.issued[userID] >= limit {
+		return repository.ErrUserLimitReached
+	}
+	if stripe.issued == nil {
+		stripe.issued = make(map[string]int)
+	}
+	stripe.issued[userID]++
+	return nil
+}
+
+// release gives back up to count of userID's coupons
+func (u *userCounts) release(userID string, count int) {
+	stripe := u.stripe(userID)
+	stripe.mutex.Lock()
+	defer stripe.mutex.Unlock()
+
+	if remaining := stripe.issued[userID] - count; remaining > 0 {
+		stripe.issued[userID] = remaining
+	} else {
+		delete(stripe.issued, userID)
+	}
+}
+
+// counts returns a copy of every user's count. Each stripe is read at once,
+// but issuance can go on in the stripes not being read.
+func (u *userCounts) counts() map[string]int {
+	counts := make(map[string]int)
+	for i := range u.stripes {
+		stripe := &u.stripes[i]
+		stripe.mutex.Lock()
+		for userID, count := range stripe.issued {
+			counts[userID] = count
+		}
+		stripe.mutex.Unlock()
+	}
+	return counts
+}
@@ -0,0 +1,153 @@
+// internal/repository/page.go
+package repository
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+)
+
+// Page selects a slice of an ordered listing
+type Page struct {
+	// Token is the next-page token returned by a previous call; empty starts from the beginning
+	Token string
+	// Size is the maximum number of items to return; 0 means no limit
+	Size int
+}
+
+// EncodeCursor builds an opaque page token from the sort key of the last returned item
+func EncodeCursor(t time.Time, key string) string {
+	raw := strconv.FormatInt(t.UnixNano(), 10) + ":" + key
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a page token created by EncodeCursor
+func DecodeCursor(token string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+
+	nanos, key, found := strings.Cut(string(raw), ":")
+	if !found {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+
+	return time.Unix(0, n), key, nil
+}
+
+// CursorAfter checks if the item identified by (t, key) sorts after the cursor position
+func CursorAfter(t time.Time, key string, cursorTime time.Time, cursorKey string) bool {
+	if !t.Equal(cursorTime) {
+		return t.After(cursorTime)
+	}
+	return key > cursorKey
+}
+
+// ListCampaigns applies a filter and page to an unordered set of campaigns,
+// ordering them by creation time and then ID. Statuses are judged at now.
+func ListCampaigns(campaigns []*domain.Campaign, filter CampaignFilter, page Page, now time.Time) ([]*domain.Campaign, string, error) {
+	var (
+		cursorTime time.Time
+		cursorID   string
+	)
+	if page.Token != "" {
+		var err error
+		cursorTime, cursorID, err = DecodeCursor(page.Token)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	matched := make([]*domain.Campaign, 0)
+	for _, campaign := range campaigns {
+		if page.Token != "" && !CursorAfter(campaign.CreatedAt, campaign.ID, cursorTime, cursorID) {
+			continue
+		}
+		if filter.Matches(campaign, now) {
+			matched = append(matched, campaign)
+		}
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.Before(matched[j].CreatedAt)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	if page.Size <= 0 || len(matched) <= page.Size {
+		return matched, "", nil
+	}
+
+	matched = matched[:page.Size]
+	last := matched[len(matched)-1]
+	return matched, EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// PageCoupons selects a page from n coupons already ordered by issue time and
+// code, where at returns the i-th coupon
+func PageCoupons(n int, at func(i int) *domain.Coupon, page Page) ([]*domain.Coupon, string, error) {
+	// The coupons are sorted, so binary search for the first one after the cursor
+	start := 0
+	if page.Token != "" {
+		cursorTime, cursorCode, err := DecodeCursor(page.Token)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(n, func(i int) bool {
+			c := at(i)
+			return CursorAfter(c.IssuedAt, c.Code, cursorTime, cursorCode)
+		})
+	}
+
+	end := n
+	if page.Size > 0 && start+page.Size < end {
+		end = start + page.Size
+	}
+
+	result := make([]*domain.Coupon, 0, end-start)
+	for i := start; i < end; i++ {
+		result = append(result, at(i))
+	}
+
+	if end == n {
+		return result, "", nil
+	}
+
+	last := result[len(result)-1]
+	return result, EncodeCursor(last.IssuedAt, last.Code), nil
+}
+
+// CouponLess reports whether coupon a sorts before coupon b in a campaign listing
+func CouponLess(a, b *domain.Coupon) bool {
+	if !a.IssuedAt.Equal(b.IssuedAt) {
+		return a.IssuedAt.Before(b.IssuedAt)
+	}
+	return a.Code < b.Code
+}
+
+// CloneCampaigns replaces the campaigns in a slice with copies the caller owns
+func CloneCampaigns(campaigns []*domain.Campaign) []*domain.Campaign {
+	for i, campaign := range campaigns {
+		campaigns[i] = campaign.Clone()
+	}
+	return campaigns
+}
+
+// CloneCoupons replaces the coupons in a slice with copies the caller owns
+func CloneCoupons(coupons []*domain.Coupon) []*domain.Coupon {
+	for i, coupon := range coupons {
+		coupons[i] = coupon.Clone()
+	}
+	return coupons
+}
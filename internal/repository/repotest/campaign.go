@@ -0,0 +1,624 @@
+// internal/repository/repotest/campaign.go
+
+// Package repotest checks that a repository implementation keeps the promises
+// of the interfaces in package repository. A backend runs the suites from its
+// own tests, handing over a constructor for empty repositories:
+//
+//	func TestConformance(t *testing.T) {
+//		repotest.TestCampaignRepository(t, func(t *testing.T, clk clock.Clock) repository.CampaignRepository {
+//			return NewCampaignRepository(clk)
+//		})
+//	}
+//
+// Run them with -race: the concurrency tests hammer shared campaigns and
+// coupons from many goroutines.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock/fakeclock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CampaignFactory returns a new, empty campaign repository for one test that
+// checks start and end times against clk. It should register any cleanup
+// with t.Cleanup.
+type CampaignFactory func(t *testing.T, clk clock.Clock) repository.CampaignRepository
+
+// TestCampaignRepository runs the campaign repository conformance tests
+func TestCampaignRepository(t *testing.T, newRepo CampaignFactory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.CampaignRepository)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"ReturnsCopies", testCampaignCopies},
+		{"FindAndDelete", testFindAndDelete},
+		{"Update", testUpdate},
+		{"IssueChecks", testIssueChecks},
+		{"IssuedByUserAndRelease", testIssuedByUserAndRelease},
+		{"NextCodeSerial", testNextCodeSerial},
+		{"List", testList},
+		{"NeverOverIssues", testNeverOverIssues},
+		{"NeverOverIssuesPerUser", testNeverOverIssuesPerUser},
+		{"UpdateDuringIssuance", testUpdateDuringIssuance},
+		{"PauseStopsIssuance", testPauseStopsIssuance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t, clock.Real{}))
+		})
+	}
+
+	t.Run("StartAndEndTimes", func(t *testing.T) {
+		clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+		testStartAndEndTimes(t, newRepo(t, clk), clk)
+	})
+}
+
+// newCampaign returns a running campaign without a user limit
+func newCampaign(id string, total int) *domain.Campaign {
+	return &domain.Campaign{
+		ID:           id,
+		Name:         "campaign " + id,
+		TotalCoupons: total,
+		StartTime:    time.Now().Add(-time.Minute),
+		CreatedAt:    time.Now().Add(-time.Hour),
+		Version:      1,
+	}
+}
+
+// mustCreate saves a campaign or fails the test
+func mustCreate(t *testing.T, repo repository.CampaignRepository, campaign *domain.Campaign) {
+	t.Helper()
+
+	if err := repo.Create(context.Background(), campaign); err != nil {
+		t.Fatalf("create campaign %s: %v", campaign.ID, err)
+	}
+}
+
+// mustGet reads a campaign or fails the test
+func mustGet(t *testing.T, repo repository.CampaignRepository, id string) *domain.Campaign {
+	t.Helper()
+
+	campaign, err := repo.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("get campaign %s: %v", id, err)
+	}
+	return campaign
+}
+
+// mustIssue counts one coupon or fails the test
+func mustIssue(t *testing.T, repo repository.CampaignRepository, id, userID string) {
+	t.Helper()
+
+	if _, err := repo.AtomicIncrementIssued(context.Background(), id, userID); err != nil {
+		t.Fatalf("increment issued for %s/%q: %v", id, userID, err)
+	}
+}
+
+// wantIssueError checks that counting one more coupon fails with want
+func wantIssueError(t *testing.T, repo repository.CampaignRepository, id, userID string, want error) {
+	t.Helper()
+
+	ok, err := repo.AtomicIncrementIssued(context.Background(), id, userID)
+	if ok || !errors.Is(err, want) {
+		t.Fatalf("increment issued for %s/%q: got %t, %v; want %v", id, userID, ok, err, want)
+	}
+}
+
+func testCreateAndGet(t *testing.T, repo repository.CampaignRepository) {
+	campaign := newCampaign("campaign-1", 10)
+	campaign.MaxCouponsPerUser = 2
+	campaign.EndTime = time.Now().Add(time.Hour)
+	campaign.CodeMode = domain.CodeModeRandom
+	campaign.CodeTemplate = &domain.CodeTemplate{Prefix: "X-", Alphabet: "latin", Groups: []int{4, 4}, Separator: "-"}
+	mustCreate(t, repo, campaign)
+
+	got := mustGet(t, repo, "campaign-1")
+	if got.ID != campaign.ID || got.Name != campaign.Name || got.TotalCoupons != 10 ||
+		got.MaxCouponsPerUser != 2 || got.IssuedCoupons != 0 || got.Version != 1 || got.Paused {
+		t.Fatalf("stored campaign %+v does not match created %+v", got, campaign)
+	}
+	if !got.StartTime.Equal(campaign.StartTime) || !got.EndTime.Equal(campaign.EndTime) || !got.CreatedAt.Equal(campaign.CreatedAt) {
+		t.Fatalf("stored times %v/%v/%v, want %v/%v/%v",
+			got.StartTime, got.EndTime, got.CreatedAt, campaign.StartTime, campaign.EndTime, campaign.CreatedAt)
+	}
+	if got.CodeMode != domain.CodeModeRandom || got.CodeTemplate == nil ||
+		got.CodeTemplate.Prefix != "X-" || len(got.CodeTemplate.Groups) != 2 {
+		t.Fatalf("stored code settings %q/%+v do not match created", got.CodeMode, got.CodeTemplate)
+	}
+
+	if _, err := repo.Get(context.Background(), "missing"); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("get missing campaign: got %v, want ErrCampaignNotFound", err)
+	}
+}
+
+func testCampaignCopies(t *testing.T, repo repository.CampaignRepository) {
+	campaign := newCampaign("campaign-1", 10)
+	campaign.CodeTemplate = &domain.CodeTemplate{Alphabet: "digits", Groups: []int{6}}
+	mustCreate(t, repo, campaign)
+
+	// Neither the campaign passed in nor the ones returned reach storage
+	campaign.Name = "changed after create"
+	campaign.CodeTemplate.Groups[0] = 1
+
+	got := mustGet(t, repo, "campaign-1")
+	got.Name = "changed after get"
+	got.CodeTemplate.Groups[0] = 2
+
+	listed, _, err := repo.List(context.Background(), repository.CampaignFilter{}, repository.Page{})
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("list campaigns: %d campaigns, %v", len(listed), err)
+	}
+	listed[0].Name = "changed after list"
+
+	got = mustGet(t, repo, "campaign-1")
+	if got.Name != "campaign campaign-1" || got.CodeTemplate.Groups[0] != 6 {
+		t.Fatalf("stored campaign changed through a caller's copy: name %q, groups %v", got.Name, got.CodeTemplate.Groups)
+	}
+}
+
+func testFindAndDelete(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 10))
+	mustCreate(t, repo, newCampaign("campaign-2", 10))
+
+	found, err := repo.FindByName(ctx, "campaign campaign-2")
+	if err != nil || found.ID != "campaign-2" {
+		t.Fatalf("find by name: got %+v, %v", found, err)
+	}
+	if _, err := repo.FindByName(ctx, "missing"); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("find missing name: got %v, want ErrCampaignNotFound", err)
+	}
+
+	if deleted, err := repo.DeleteByID(ctx, "campaign-1"); !deleted || err != nil {
+		t.Fatalf("delete by ID: got %t, %v", deleted, err)
+	}
+	if _, err := repo.Get(ctx, "campaign-1"); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("get deleted campaign: got %v, want ErrCampaignNotFound", err)
+	}
+	if deleted, err := repo.DeleteByID(ctx, "campaign-1"); deleted || err != nil {
+		t.Fatalf("delete by ID twice: got %t, %v", deleted, err)
+	}
+
+	if deleted, err := repo.DeleteByName(ctx, "campaign campaign-2"); !deleted || err != nil {
+		t.Fatalf("delete by name: got %t, %v", deleted, err)
+	}
+	if deleted, err := repo.DeleteByName(ctx, "campaign campaign-2"); deleted || err != nil {
+		t.Fatalf("delete by name twice: got %t, %v", deleted, err)
+	}
+}
+
+func testUpdate(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 10))
+	mustIssue(t, repo, "campaign-1", "")
+	mustIssue(t, repo, "campaign-1", "")
+
+	// A stale version is refused
+	stale := mustGet(t, repo, "campaign-1")
+	stale.Version = 0
+	if err := repo.Update(ctx, stale); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("update stale version: got %v, want ErrVersionConflict", err)
+	}
+
+	// The counters are owned by the repository, whatever the caller sends
+	updated := mustGet(t, repo, "campaign-1")
+	updated.Name = "renamed"
+	updated.TotalCoupons = 20
+	updated.IssuedCoupons = 0
+	if err := repo.Update(ctx, updated); err != nil {
+		t.Fatalf("update campaign: %v", err)
+	}
+	if updated.Version != 2 || updated.IssuedCoupons != 2 {
+		t.Fatalf("updated campaign has version %d and %d issued, want 2 and 2", updated.Version, updated.IssuedCoupons)
+	}
+	got := mustGet(t, repo, "campaign-1")
+	if got.Name != "renamed" || got.TotalCoupons != 20 || got.IssuedCoupons != 2 || got.Version != 2 {
+		t.Fatalf("stored campaign after update: %+v", got)
+	}
+
+	got.TotalCoupons = 1
+	if err := repo.Update(ctx, got); !errors.Is(err, repository.ErrTotalBelowIssued) {
+		t.Fatalf("update total below issued: got %v, want ErrTotalBelowIssued", err)
+	}
+
+	missing := newCampaign("missing", 10)
+	if err := repo.Update(ctx, missing); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("update missing campaign: got %v, want ErrCampaignNotFound", err)
+	}
+}
+
+func testIssueChecks(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+
+	scheduled := newCampaign("scheduled", 10)
+	scheduled.StartTime = time.Now().Add(time.Hour)
+	mustCreate(t, repo, scheduled)
+	wantIssueError(t, repo, "scheduled", "", repository.ErrCampaignNotStarted)
+
+	ended := newCampaign("ended", 10)
+	ended.StartTime = time.Now().Add(-2 * time.Hour)
+	ended.EndTime = time.Now().Add(-time.Hour)
+	mustCreate(t, repo, ended)
+	wantIssueError(t, repo, "ended", "", repository.ErrCampaignEnded)
+
+	wantIssueError(t, repo, "missing", "", repository.ErrCampaignNotFound)
+
+	mustCreate(t, repo, newCampaign("running", 2))
+	paused, err := repo.SetPaused(ctx, "running", true)
+	if err != nil || !paused.Paused {
+		t.Fatalf("pause campaign: got %+v, %v", paused, err)
+	}
+	wantIssueError(t, repo, "running", "", repository.ErrCampaignPaused)
+	if resumed, err := repo.SetPaused(ctx, "running", false); err != nil || resumed.Paused {
+		t.Fatalf("resume campaign: got %+v, %v", resumed, err)
+	}
+	mustIssue(t, repo, "running", "")
+	mustIssue(t, repo, "running", "")
+	wantIssueError(t, repo, "running", "", repository.ErrLimitReached)
+
+	// The checks run in a fixed order: paused, sold out, not started, ended
+	if _, err := repo.SetPaused(ctx, "running", true); err != nil {
+		t.Fatalf("pause campaign: %v", err)
+	}
+	wantIssueError(t, repo, "running", "", repository.ErrCampaignPaused)
+
+	soldOut := newCampaign("sold-out-scheduled", 1)
+	soldOut.IssuedCoupons = 1
+	soldOut.StartTime = time.Now().Add(time.Hour)
+	mustCreate(t, repo, soldOut)
+	wantIssueError(t, repo, "sold-out-scheduled", "", repository.ErrLimitReached)
+
+	limited := newCampaign("limited", 10)
+	limited.MaxCouponsPerUser = 1
+	mustCreate(t, repo, limited)
+	mustIssue(t, repo, "limited", "user-1")
+	wantIssueError(t, repo, "limited", "user-1", repository.ErrUserLimitReached)
+	mustIssue(t, repo, "limited", "user-2")
+	if got := mustGet(t, repo, "limited"); got.IssuedCoupons != 2 {
+		t.Fatalf("limited campaign has %d issued, want 2", got.IssuedCoupons)
+	}
+}
+
+func testIssuedByUserAndRelease(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 10))
+	for _, userID := range []string{"user-a", "user-a", "user-b", ""} {
+		mustIssue(t, repo, "campaign-1", userID)
+	}
+
+	byUser, err := repo.IssuedByUser(ctx, "campaign-1")
+	if err != nil || len(byUser) != 2 || byUser["user-a"] != 2 || byUser["user-b"] != 1 {
+		t.Fatalf("issued by user: got %v, %v", byUser, err)
+	}
+
+	if err := repo.ReleaseIssued(ctx, "campaign-1", 2, map[string]int{"user-a": 1}); err != nil {
+		t.Fatalf("release issued: %v", err)
+	}
+	if got := mustGet(t, repo, "campaign-1"); got.IssuedCoupons != 2 {
+		t.Fatalf("campaign has %d issued after release, want 2", got.IssuedCoupons)
+	}
+	byUser, err = repo.IssuedByUser(ctx, "campaign-1")
+	if err != nil || byUser["user-a"] != 1 || byUser["user-b"] != 1 {
+		t.Fatalf("issued by user after release: got %v, %v", byUser, err)
+	}
+
+	// Counters never drop below zero
+	if err := repo.ReleaseIssued(ctx, "campaign-1", 10, map[string]int{"user-a": 5}); err != nil {
+		t.Fatalf("release more than issued: %v", err)
+	}
+	if got := mustGet(t, repo, "campaign-1"); got.IssuedCoupons != 0 {
+		t.Fatalf("campaign has %d issued after releasing everything, want 0", got.IssuedCoupons)
+	}
+	byUser, err = repo.IssuedByUser(ctx, "campaign-1")
+	if err != nil || byUser["user-a"] != 0 {
+		t.Fatalf("issued by user after releasing everything: got %v, %v", byUser, err)
+	}
+
+	// Released slots can be issued again
+	mustIssue(t, repo, "campaign-1", "user-a")
+
+	if _, err := repo.IssuedByUser(ctx, "missing"); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("issued by user of missing campaign: got %v, want ErrCampaignNotFound", err)
+	}
+}
+
+func testNextCodeSerial(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 10))
+
+	const n = 50
+	var (
+		seen  = make([]atomic.Bool, n)
+		wg    sync.WaitGroup
+		extra atomic.Int64
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			serial, err := repo.NextCodeSerial(ctx, "campaign-1")
+			if err != nil {
+				t.Errorf("next code serial: %v", err)
+				return
+			}
+			if serial < 0 || serial >= n || seen[serial].Swap(true) {
+				extra.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if extra.Load() != 0 {
+		t.Fatalf("%d serials were repeated or out of range 0-%d", extra.Load(), n-1)
+	}
+	if got := mustGet(t, repo, "campaign-1"); got.CodeSerial != n {
+		t.Fatalf("campaign code serial is %d, want %d", got.CodeSerial, n)
+	}
+	if _, err := repo.NextCodeSerial(ctx, "missing"); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("next code serial of missing campaign: got %v, want ErrCampaignNotFound", err)
+	}
+}
+
+func testList(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i := 4; i >= 0; i-- {
+		campaign := newCampaign(fmt.Sprintf("campaign-%d", i), 10)
+		campaign.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		if i%2 == 1 {
+			campaign.Name = fmt.Sprintf("odd %d", i)
+		}
+		mustCreate(t, repo, campaign)
+	}
+
+	var (
+		ids  []string
+		page = repository.Page{Size: 2}
+	)
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("list did not end after %d pages", pages)
+		}
+		campaigns, next, err := repo.List(ctx, repository.CampaignFilter{}, page)
+		if err != nil {
+			t.Fatalf("list campaigns: %v", err)
+		}
+		if len(campaigns) > 2 {
+			t.Fatalf("page holds %d campaigns, want at most 2", len(campaigns))
+		}
+		for _, campaign := range campaigns {
+			ids = append(ids, campaign.ID)
+		}
+		if next == "" {
+			break
+		}
+		page.Token = next
+	}
+	if fmt.Sprint(ids) != "[campaign-0 campaign-1 campaign-2 campaign-3 campaign-4]" {
+		t.Fatalf("listed %v, want campaigns in creation order", ids)
+	}
+
+	odd, _, err := repo.List(ctx, repository.CampaignFilter{NamePrefix: "odd"}, repository.Page{})
+	if err != nil || len(odd) != 2 || odd[0].ID != "campaign-1" || odd[1].ID != "campaign-3" {
+		t.Fatalf("list by name prefix: got %d campaigns, %v", len(odd), err)
+	}
+
+	if _, _, err := repo.List(ctx, repository.CampaignFilter{}, repository.Page{Token: "not a token"}); !errors.Is(err, repository.ErrInvalidPageToken) {
+		t.Fatalf("list with a bad token: got %v, want ErrInvalidPageToken", err)
+	}
+}
+
+// issueConcurrently counts coupons from workers goroutines making attempts
+// increments each, where userOf picks the user of an attempt. It returns the
+// number of increments that succeeded and fails the test on unexpected errors.
+func issueConcurrently(t *testing.T, repo repository.CampaignRepository, id string, workers, attempts int, userOf func(worker, attempt int) string, allowed ...error) int {
+	t.Helper()
+
+	var (
+		issued atomic.Int64
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for a := 0; a < attempts; a++ {
+				ok, err := repo.AtomicIncrementIssued(context.Background(), id, userOf(w, a))
+				if err == nil && ok {
+					issued.Add(1)
+					continue
+				}
+				if !isOneOf(err, allowed) {
+					t.Errorf("increment issued: got %t, %v", ok, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	return int(issued.Load())
+}
+
+// isOneOf checks if err is one of the allowed errors
+func isOneOf(err error, allowed []error) bool {
+	for _, target := range allowed {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func testNeverOverIssues(t *testing.T, repo repository.CampaignRepository) {
+	mustCreate(t, repo, newCampaign("campaign-1", 100))
+
+	issued := issueConcurrently(t, repo, "campaign-1", 50, 5, func(int, int) string { return "" }, repository.ErrLimitReached)
+	if issued != 100 {
+		t.Fatalf("%d increments succeeded, want exactly 100", issued)
+	}
+	if got := mustGet(t, repo, "campaign-1"); got.IssuedCoupons != 100 {
+		t.Fatalf("campaign has %d issued, want 100", got.IssuedCoupons)
+	}
+}
+
+func testNeverOverIssuesPerUser(t *testing.T, repo repository.CampaignRepository) {
+	campaign := newCampaign("campaign-1", 15)
+	campaign.MaxCouponsPerUser = 2
+	mustCreate(t, repo, campaign)
+
+	// 10 users could get 20 coupons, but the total stops them at 15
+	issued := issueConcurrently(t, repo, "campaign-1", 20, 5, func(worker, _ int) string {
+		return fmt.Sprintf("user-%d", worker%10)
+	}, repository.ErrLimitReached, repository.ErrUserLimitReached)
+	if issued != 15 {
+		t.Fatalf("%d increments succeeded, want exactly 15", issued)
+	}
+
+	byUser, err := repo.IssuedByUser(context.Background(), "campaign-1")
+	if err != nil {
+		t.Fatalf("issued by user: %v", err)
+	}
+	sum := 0
+	for userID, count := range byUser {
+		if count > 2 {
+			t.Fatalf("user %s got %d coupons, limit is 2", userID, count)
+		}
+		sum += count
+	}
+	if sum != 15 {
+		t.Fatalf("users got %d coupons in all, want 15", sum)
+	}
+}
+
+func testUpdateDuringIssuance(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 1000))
+
+	// Keep shrinking the total towards the issued count while issuing
+	stop := make(chan struct{})
+	updaterDone := make(chan struct{})
+	go func() {
+		defer close(updaterDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			campaign, err := repo.Get(ctx, "campaign-1")
+			if err != nil {
+				t.Errorf("get campaign: %v", err)
+				return
+			}
+			campaign.TotalCoupons = campaign.IssuedCoupons + 5
+			err = repo.Update(ctx, campaign)
+			if err != nil && !errors.Is(err, repository.ErrVersionConflict) && !errors.Is(err, repository.ErrTotalBelowIssued) {
+				t.Errorf("update campaign: %v", err)
+				return
+			}
+		}
+	}()
+
+	issued := issueConcurrently(t, repo, "campaign-1", 10, 20, func(int, int) string { return "" }, repository.ErrLimitReached)
+	close(stop)
+	<-updaterDone
+
+	got := mustGet(t, repo, "campaign-1")
+	if got.IssuedCoupons != issued || got.IssuedCoupons > got.TotalCoupons {
+		t.Fatalf("campaign has %d of %d issued, %d increments succeeded", got.IssuedCoupons, got.TotalCoupons, issued)
+	}
+}
+
+func testPauseStopsIssuance(t *testing.T, repo repository.CampaignRepository) {
+	ctx := context.Background()
+	mustCreate(t, repo, newCampaign("campaign-1", 1000))
+
+	var (
+		wg      sync.WaitGroup
+		started = make(chan struct{})
+		once    sync.Once
+	)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				_, err := repo.AtomicIncrementIssued(ctx, "campaign-1", "")
+				once.Do(func() { close(started) })
+				// Backends that serialize writes may let the campaign sell
+				// out before the pause gets its turn
+				if errors.Is(err, repository.ErrCampaignPaused) || errors.Is(err, repository.ErrLimitReached) {
+					return
+				}
+				if err != nil {
+					t.Errorf("increment issued: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	<-started
+	_, err := repo.SetPaused(ctx, "campaign-1", true)
+	atPause := mustGet(t, repo, "campaign-1").IssuedCoupons
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("pause campaign: %v", err)
+	}
+
+	// Nothing is counted once pausing returned
+	if got := mustGet(t, repo, "campaign-1"); got.IssuedCoupons != atPause {
+		t.Fatalf("campaign had %d issued when paused and %d afterwards", atPause, got.IssuedCoupons)
+	}
+}
+
+func testStartAndEndTimes(t *testing.T, repo repository.CampaignRepository, clk *fakeclock.Clock) {
+	ctx := context.Background()
+	campaign := newCampaign("campaign-1", 10)
+	campaign.StartTime = clk.Now().Add(time.Hour)
+	campaign.EndTime = clk.Now().Add(2 * time.Hour)
+	mustCreate(t, repo, campaign)
+
+	scheduled, _, err := repo.List(ctx, repository.CampaignFilter{Status: domain.CampaignStatusScheduled}, repository.Page{})
+	if err != nil || len(scheduled) != 1 {
+		t.Fatalf("list scheduled campaigns: %d campaigns, %v", len(scheduled), err)
+	}
+	wantIssueError(t, repo, "campaign-1", "", repository.ErrCampaignNotStarted)
+
+	// A campaign opens at its start time, not after it
+	clk.Advance(time.Hour - time.Nanosecond)
+	wantIssueError(t, repo, "campaign-1", "", repository.ErrCampaignNotStarted)
+	clk.Advance(time.Nanosecond)
+	mustIssue(t, repo, "campaign-1", "")
+
+	active, _, err := repo.List(ctx, repository.CampaignFilter{Status: domain.CampaignStatusActive}, repository.Page{})
+	if err != nil || len(active) != 1 {
+		t.Fatalf("list active campaigns: %d campaigns, %v", len(active), err)
+	}
+
+	// and closes at its end time
+	clk.Set(campaign.EndTime)
+	wantIssueError(t, repo, "campaign-1", "", repository.ErrCampaignEnded)
+
+	ended, _, err := repo.List(ctx, repository.CampaignFilter{Status: domain.CampaignStatusEnded}, repository.Page{})
+	if err != nil || len(ended) != 1 {
+		t.Fatalf("list ended campaigns: %d campaigns, %v", len(ended), err)
+	}
+	if got := mustGet(t, repo, "campaign-1"); got.IssuedCoupons != 1 {
+		t.Fatalf("campaign has %d issued, want 1", got.IssuedCoupons)
+	}
+}
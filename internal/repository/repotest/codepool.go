@@ -0,0 +1,257 @@
+// internal/repository/repotest/codepool.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CodePoolRepositories are the repositories of one store that the code pool
+// tests use: the pool checks imports against its campaigns and coupons, and
+// its issuance repository hands the pooled codes out
+type CodePoolRepositories struct {
+	Campaigns repository.CampaignRepository
+	Coupons   repository.CouponRepository
+	CodePool  repository.CodePoolRepository
+	Issuance  repository.IssuanceRepository
+}
+
+// CodePoolFactory returns new, empty repositories sharing one store for one
+// test. It should register any cleanup with t.Cleanup.
+type CodePoolFactory func(t *testing.T) CodePoolRepositories
+
+// TestCodePoolRepository runs the code pool conformance tests, which cover
+// IssuanceRepository.IssuePooledCoupon along with the pool itself
+func TestCodePoolRepository(t *testing.T, newRepos CodePoolFactory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repos CodePoolRepositories)
+	}{
+		{"AddCodesSkipsDuplicates", testAddCodesSkipsDuplicates},
+		{"AddCodesToMissingCampaign", testAddCodesToMissingCampaign},
+		{"IssuePooledInImportOrder", testIssuePooledInImportOrder},
+		{"IssuePooledChecksLimits", testIssuePooledChecksLimits},
+		{"IssuePooledSkipsTakenCodes", testIssuePooledSkipsTakenCodes},
+		{"IssuePooledOnce", testIssuePooledOnce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepos(t))
+		})
+	}
+}
+
+// mustAddCodes adds codes to a campaign's pool and returns the duplicates, or fails the test
+func mustAddCodes(t *testing.T, repo repository.CodePoolRepository, campaignID string, codes ...string) []string {
+	t.Helper()
+
+	duplicates, err := repo.AddCodes(context.Background(), campaignID, codes)
+	if err != nil {
+		t.Fatalf("add codes to %s: %v", campaignID, err)
+	}
+	return duplicates
+}
+
+// wantCodeCount checks the number of codes left in a campaign's pool
+func wantCodeCount(t *testing.T, repo repository.CodePoolRepository, campaignID string, want int) {
+	t.Helper()
+
+	count, err := repo.CountCodes(context.Background(), campaignID)
+	if err != nil || count != want {
+		t.Fatalf("count codes of %s: got %d, %v; want %d", campaignID, count, err, want)
+	}
+}
+
+// issuePooled issues a coupon of campaignID to userID with a pooled code
+func issuePooled(repo repository.IssuanceRepository, campaignID, userID string) (*domain.Coupon, error) {
+	coupon := newCoupon("", campaignID, userID, time.Now())
+	return coupon, repo.IssuePooledCoupon(context.Background(), coupon)
+}
+
+// mustIssuePooled issues a coupon with a pooled code and checks the code, or fails the test
+func mustIssuePooled(t *testing.T, repo repository.IssuanceRepository, campaignID, userID, wantCode string) {
+	t.Helper()
+
+	coupon, err := issuePooled(repo, campaignID, userID)
+	if err != nil {
+		t.Fatalf("issue pooled coupon of %s to %q: %v", campaignID, userID, err)
+	}
+	if coupon.Code != wantCode {
+		t.Fatalf("issue pooled coupon of %s: got code %q, want %q", campaignID, coupon.Code, wantCode)
+	}
+}
+
+// wantIssuePooledError checks that issuing a pooled coupon fails with want
+func wantIssuePooledError(t *testing.T, repo repository.IssuanceRepository, campaignID, userID string, want error) {
+	t.Helper()
+
+	if _, err := issuePooled(repo, campaignID, userID); !errors.Is(err, want) {
+		t.Fatalf("issue pooled coupon of %s to %q: got %v, want %v", campaignID, userID, err, want)
+	}
+}
+
+func testAddCodesSkipsDuplicates(t *testing.T, repos CodePoolRepositories) {
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-1", 10))
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-2", 10))
+	mustCreateCoupon(t, repos.Coupons, newCoupon("TAKEN", "campaign-2", "", time.Now()))
+
+	duplicates := mustAddCodes(t, repos.CodePool, "campaign-1", "A", "B", "A", "TAKEN", "C")
+	if !slices.Equal(duplicates, []string{"A", "TAKEN"}) {
+		t.Fatalf("duplicates within a batch and of taken codes: got %q, want [A TAKEN]", duplicates)
+	}
+	wantCodeCount(t, repos.CodePool, "campaign-1", 3)
+
+	// Codes pooled for another campaign are duplicates too
+	duplicates = mustAddCodes(t, repos.CodePool, "campaign-2", "B", "D")
+	if !slices.Equal(duplicates, []string{"B"}) {
+		t.Fatalf("duplicates of pooled codes: got %q, want [B]", duplicates)
+	}
+	wantCodeCount(t, repos.CodePool, "campaign-1", 3)
+	wantCodeCount(t, repos.CodePool, "campaign-2", 1)
+
+	// So are codes issued from the pool
+	mustIssuePooled(t, repos.Issuance, "campaign-1", "", "A")
+	duplicates = mustAddCodes(t, repos.CodePool, "campaign-2", "A")
+	if !slices.Equal(duplicates, []string{"A"}) {
+		t.Fatalf("duplicates of issued codes: got %q, want [A]", duplicates)
+	}
+	wantCodeCount(t, repos.CodePool, "campaign-2", 1)
+}
+
+func testAddCodesToMissingCampaign(t *testing.T, repos CodePoolRepositories) {
+	ctx := context.Background()
+
+	if _, err := repos.CodePool.AddCodes(ctx, "missing", []string{"A"}); !errors.Is(err, repository.ErrCampaignNotFound) {
+		t.Fatalf("add codes to missing campaign: got %v, want ErrCampaignNotFound", err)
+	}
+	wantCodeCount(t, repos.CodePool, "missing", 0)
+
+	// The refused codes were not pooled
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-1", 10))
+	if duplicates := mustAddCodes(t, repos.CodePool, "campaign-1", "A"); len(duplicates) != 0 {
+		t.Fatalf("codes refused for a missing campaign are duplicates: %q", duplicates)
+	}
+}
+
+func testIssuePooledInImportOrder(t *testing.T, repos CodePoolRepositories) {
+	ctx := context.Background()
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-1", 10))
+	mustAddCodes(t, repos.CodePool, "campaign-1", "B", "A")
+	mustAddCodes(t, repos.CodePool, "campaign-1", "C")
+
+	for _, code := range []string{"B", "A", "C"} {
+		mustIssuePooled(t, repos.Issuance, "campaign-1", "", code)
+
+		stored, err := repos.Coupons.GetByCode(ctx, code)
+		if err != nil {
+			t.Fatalf("get pooled coupon %s: %v", code, err)
+		}
+		if stored.CampaignID != "campaign-1" || stored.Status != domain.CouponStatusIssued {
+			t.Fatalf("pooled coupon stored as %+v", stored)
+		}
+	}
+	wantCodeCount(t, repos.CodePool, "campaign-1", 0)
+
+	// An exhausted pool leaves the counters alone
+	wantIssuePooledError(t, repos.Issuance, "campaign-1", "", repository.ErrCodePoolEmpty)
+	if issued := mustGet(t, repos.Campaigns, "campaign-1").IssuedCoupons; issued != 3 {
+		t.Fatalf("issued coupons after exhausting the pool = %d, want 3", issued)
+	}
+
+	// A pooled code cannot be issued as a generated one
+	mustAddCodes(t, repos.CodePool, "campaign-1", "D")
+	if err := repos.Issuance.IssueCoupon(ctx, newCoupon("D", "campaign-1", "", time.Now())); !errors.Is(err, repository.ErrDuplicateCouponCode) {
+		t.Fatalf("issue pooled code as generated: got %v, want ErrDuplicateCouponCode", err)
+	}
+	wantCodeCount(t, repos.CodePool, "campaign-1", 1)
+	if issued := mustGet(t, repos.Campaigns, "campaign-1").IssuedCoupons; issued != 3 {
+		t.Fatalf("issued coupons after a duplicate code = %d, want 3", issued)
+	}
+}
+
+func testIssuePooledChecksLimits(t *testing.T, repos CodePoolRepositories) {
+	campaign := newCampaign("campaign-1", 2)
+	campaign.MaxCouponsPerUser = 1
+	mustCreate(t, repos.Campaigns, campaign)
+	mustAddCodes(t, repos.CodePool, "campaign-1", "A", "B", "C", "D")
+
+	mustIssuePooled(t, repos.Issuance, "campaign-1", "user-1", "A")
+	wantIssuePooledError(t, repos.Issuance, "campaign-1", "user-1", repository.ErrUserLimitReached)
+	mustIssuePooled(t, repos.Issuance, "campaign-1", "user-2", "B")
+	wantIssuePooledError(t, repos.Issuance, "campaign-1", "user-3", repository.ErrLimitReached)
+	wantIssuePooledError(t, repos.Issuance, "missing", "user-1", repository.ErrCampaignNotFound)
+
+	// Refused issues keep their codes in the pool
+	wantCodeCount(t, repos.CodePool, "campaign-1", 2)
+	if issued := mustGet(t, repos.Campaigns, "campaign-1").IssuedCoupons; issued != 2 {
+		t.Fatalf("issued coupons = %d, want 2", issued)
+	}
+}
+
+func testIssuePooledSkipsTakenCodes(t *testing.T, repos CodePoolRepositories) {
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-1", 10))
+	mustAddCodes(t, repos.CodePool, "campaign-1", "A", "B")
+
+	// Saved directly, bypassing the pool's checks
+	mustCreateCoupon(t, repos.Coupons, newCoupon("A", "campaign-2", "", time.Now()))
+
+	mustIssuePooled(t, repos.Issuance, "campaign-1", "", "B")
+	wantCodeCount(t, repos.CodePool, "campaign-1", 0)
+	wantIssuePooledError(t, repos.Issuance, "campaign-1", "", repository.ErrCodePoolEmpty)
+}
+
+func testIssuePooledOnce(t *testing.T, repos CodePoolRepositories) {
+	const (
+		workers = 8
+		codes   = 100
+	)
+	mustCreate(t, repos.Campaigns, newCampaign("campaign-1", 2*codes))
+	pooled := make([]string, codes)
+	for i := range pooled {
+		pooled[i] = fmt.Sprintf("CODE-%03d", i)
+	}
+	mustAddCodes(t, repos.CodePool, "campaign-1", pooled...)
+
+	var (
+		wg     sync.WaitGroup
+		mutex  sync.Mutex
+		issued []string
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				coupon, err := issuePooled(repos.Issuance, "campaign-1", "")
+				if errors.Is(err, repository.ErrCodePoolEmpty) {
+					return
+				}
+				if err != nil {
+					t.Errorf("issue pooled coupon: %v", err)
+					return
+				}
+				mutex.Lock()
+				issued = append(issued, coupon.Code)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(issued)
+	if !slices.Equal(issued, pooled) {
+		t.Fatalf("issued %d codes, want each of the %d pooled codes once", len(issued), codes)
+	}
+	if count := mustGet(t, repos.Campaigns, "campaign-1").IssuedCoupons; count != codes {
+		t.Fatalf("issued coupons = %d, want %d", count, codes)
+	}
+}
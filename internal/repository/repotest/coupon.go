@@ -0,0 +1,318 @@
+// internal/repository/repotest/coupon.go
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CouponFactory returns a new, empty coupon repository for one test.
+// It should register any cleanup with t.Cleanup.
+type CouponFactory func(t *testing.T) repository.CouponRepository
+
+// TestCouponRepository runs the coupon repository conformance tests
+func TestCouponRepository(t *testing.T, newRepo CouponFactory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, repo repository.CouponRepository)
+	}{
+		{"CreateAndGet", testCreateAndGetCoupon},
+		{"ReturnsCopies", testCouponCopies},
+		{"Redeem", testRedeem},
+		{"RedeemOnce", testRedeemOnce},
+		{"Revoke", testRevoke},
+		{"GetByCampaign", testGetByCampaign},
+		{"CountAndDelete", testCountAndDelete},
+		{"CreateOnce", testCreateOnce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepo(t))
+		})
+	}
+}
+
+// newCoupon returns an issued coupon
+func newCoupon(code, campaignID, userID string, issuedAt time.Time) *domain.Coupon {
+	return &domain.Coupon{
+		Code:       code,
+		CampaignID: campaignID,
+		UserID:     userID,
+		Status:     domain.CouponStatusIssued,
+		IssuedAt:   issuedAt,
+	}
+}
+
+// mustCreateCoupon saves a coupon or fails the test
+func mustCreateCoupon(t *testing.T, repo repository.CouponRepository, coupon *domain.Coupon) {
+	t.Helper()
+
+	if err := repo.Create(context.Background(), coupon); err != nil {
+		t.Fatalf("create coupon %s: %v", coupon.Code, err)
+	}
+}
+
+func testCreateAndGetCoupon(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	coupon := newCoupon("CODE-1", "campaign-1", "user-1", time.Now())
+	mustCreateCoupon(t, repo, coupon)
+
+	got, err := repo.GetByCode(ctx, "CODE-1")
+	if err != nil {
+		t.Fatalf("get coupon: %v", err)
+	}
+	if got.Code != coupon.Code || got.CampaignID != coupon.CampaignID || got.UserID != coupon.UserID ||
+		got.Status != domain.CouponStatusIssued || !got.IssuedAt.Equal(coupon.IssuedAt) || !got.RedeemedAt.IsZero() {
+		t.Fatalf("stored coupon %+v does not match created %+v", got, coupon)
+	}
+
+	duplicate := newCoupon("CODE-1", "campaign-2", "", time.Now())
+	if err := repo.Create(ctx, duplicate); !errors.Is(err, repository.ErrDuplicateCouponCode) {
+		t.Fatalf("create duplicate code: got %v, want ErrDuplicateCouponCode", err)
+	}
+
+	if _, err := repo.GetByCode(ctx, "missing"); !errors.Is(err, repository.ErrCouponNotFound) {
+		t.Fatalf("get missing coupon: got %v, want ErrCouponNotFound", err)
+	}
+}
+
+func testCouponCopies(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	coupon := newCoupon("CODE-1", "campaign-1", "user-1", time.Now())
+	mustCreateCoupon(t, repo, coupon)
+	coupon.UserID = "changed after create"
+
+	got, err := repo.GetByCode(ctx, "CODE-1")
+	if err != nil {
+		t.Fatalf("get coupon: %v", err)
+	}
+	got.Status = domain.CouponStatusRevoked
+
+	listed, _, err := repo.GetByCampaign(ctx, "campaign-1", repository.Page{})
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("get coupons by campaign: %d coupons, %v", len(listed), err)
+	}
+	listed[0].UserID = "changed after list"
+
+	got, err = repo.GetByCode(ctx, "CODE-1")
+	if err != nil {
+		t.Fatalf("get coupon: %v", err)
+	}
+	if got.UserID != "user-1" || got.Status != domain.CouponStatusIssued {
+		t.Fatalf("stored coupon changed through a caller's copy: %+v", got)
+	}
+}
+
+func testRedeem(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	mustCreateCoupon(t, repo, newCoupon("CODE-1", "campaign-1", "", time.Now()))
+
+	redeemedAt := time.Now().Add(time.Minute)
+	redeemed, err := repo.Redeem(ctx, "CODE-1", redeemedAt)
+	if err != nil {
+		t.Fatalf("redeem coupon: %v", err)
+	}
+	if redeemed.Status != domain.CouponStatusRedeemed || !redeemed.RedeemedAt.Equal(redeemedAt) {
+		t.Fatalf("redeemed coupon is %q at %v, want redeemed at %v", redeemed.Status, redeemed.RedeemedAt, redeemedAt)
+	}
+	got, err := repo.GetByCode(ctx, "CODE-1")
+	if err != nil || got.Status != domain.CouponStatusRedeemed || !got.RedeemedAt.Equal(redeemedAt) {
+		t.Fatalf("stored coupon after redeem: got %+v, %v", got, err)
+	}
+
+	if _, err := repo.Redeem(ctx, "CODE-1", time.Now()); !errors.Is(err, repository.ErrCouponAlreadyRedeemed) {
+		t.Fatalf("redeem twice: got %v, want ErrCouponAlreadyRedeemed", err)
+	}
+	if _, err := repo.Redeem(ctx, "missing", time.Now()); !errors.Is(err, repository.ErrCouponNotFound) {
+		t.Fatalf("redeem missing coupon: got %v, want ErrCouponNotFound", err)
+	}
+
+	for status, want := range map[domain.CouponStatus]error{
+		domain.CouponStatusExpired: repository.ErrCouponExpired,
+		domain.CouponStatusRevoked: repository.ErrCouponRevoked,
+	} {
+		coupon := newCoupon("CODE-"+string(status), "campaign-1", "", time.Now())
+		coupon.Status = status
+		mustCreateCoupon(t, repo, coupon)
+		if _, err := repo.Redeem(ctx, coupon.Code, time.Now()); !errors.Is(err, want) {
+			t.Fatalf("redeem %s coupon: got %v, want %v", status, err, want)
+		}
+	}
+}
+
+func testRedeemOnce(t *testing.T, repo repository.CouponRepository) {
+	mustCreateCoupon(t, repo, newCoupon("CODE-1", "campaign-1", "", time.Now()))
+
+	var (
+		redeemed atomic.Int64
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Redeem(context.Background(), "CODE-1", time.Now())
+			switch {
+			case err == nil:
+				redeemed.Add(1)
+			case !errors.Is(err, repository.ErrCouponAlreadyRedeemed):
+				t.Errorf("redeem coupon: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if redeemed.Load() != 1 {
+		t.Fatalf("coupon was redeemed %d times, want once", redeemed.Load())
+	}
+}
+
+func testRevoke(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	mustCreateCoupon(t, repo, newCoupon("CODE-1", "campaign-1", "user-1", time.Now()))
+
+	revoked, err := repo.Revoke(ctx, "CODE-1")
+	if err != nil {
+		t.Fatalf("revoke coupon: %v", err)
+	}
+	if revoked.Status != domain.CouponStatusRevoked || revoked.UserID != "user-1" {
+		t.Fatalf("revoked coupon is %+v", revoked)
+	}
+	if got, err := repo.GetByCode(ctx, "CODE-1"); err != nil || got.Status != domain.CouponStatusRevoked {
+		t.Fatalf("stored coupon after revoke: got %+v, %v", got, err)
+	}
+
+	if _, err := repo.Redeem(ctx, "CODE-1", time.Now()); !errors.Is(err, repository.ErrCouponRevoked) {
+		t.Fatalf("redeem revoked coupon: got %v, want ErrCouponRevoked", err)
+	}
+	if _, err := repo.Revoke(ctx, "CODE-1"); !errors.Is(err, repository.ErrCouponRevoked) {
+		t.Fatalf("revoke twice: got %v, want ErrCouponRevoked", err)
+	}
+	if _, err := repo.Revoke(ctx, "missing"); !errors.Is(err, repository.ErrCouponNotFound) {
+		t.Fatalf("revoke missing coupon: got %v, want ErrCouponNotFound", err)
+	}
+
+	mustCreateCoupon(t, repo, newCoupon("CODE-2", "campaign-1", "", time.Now()))
+	if _, err := repo.Redeem(ctx, "CODE-2", time.Now()); err != nil {
+		t.Fatalf("redeem coupon: %v", err)
+	}
+	if _, err := repo.Revoke(ctx, "CODE-2"); !errors.Is(err, repository.ErrCouponAlreadyRedeemed) {
+		t.Fatalf("revoke redeemed coupon: got %v, want ErrCouponAlreadyRedeemed", err)
+	}
+}
+
+func testGetByCampaign(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+
+	// Stored out of order, with two coupons sharing an issue time
+	for _, i := range []int{3, 0, 4, 1, 2} {
+		issuedAt := base.Add(time.Duration(i) * time.Second)
+		if i == 2 {
+			issuedAt = base.Add(time.Second)
+		}
+		mustCreateCoupon(t, repo, newCoupon(fmt.Sprintf("CODE-%d", i), "campaign-1", "", issuedAt))
+	}
+	mustCreateCoupon(t, repo, newCoupon("OTHER", "campaign-2", "", base))
+
+	var (
+		codes []string
+		page  = repository.Page{Size: 2}
+	)
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("listing did not end after %d pages", pages)
+		}
+		coupons, next, err := repo.GetByCampaign(ctx, "campaign-1", page)
+		if err != nil {
+			t.Fatalf("get coupons by campaign: %v", err)
+		}
+		if len(coupons) > 2 {
+			t.Fatalf("page holds %d coupons, want at most 2", len(coupons))
+		}
+		for _, coupon := range coupons {
+			codes = append(codes, coupon.Code)
+		}
+		if next == "" {
+			break
+		}
+		page.Token = next
+	}
+	if fmt.Sprint(codes) != "[CODE-0 CODE-1 CODE-2 CODE-3 CODE-4]" {
+		t.Fatalf("listed %v, want coupons by issue time and then code", codes)
+	}
+
+	if coupons, next, err := repo.GetByCampaign(ctx, "missing", repository.Page{}); err != nil || len(coupons) != 0 || next != "" {
+		t.Fatalf("get coupons of unknown campaign: got %d coupons, %q, %v", len(coupons), next, err)
+	}
+	if _, _, err := repo.GetByCampaign(ctx, "campaign-1", repository.Page{Token: "not a token"}); !errors.Is(err, repository.ErrInvalidPageToken) {
+		t.Fatalf("get coupons with a bad token: got %v, want ErrInvalidPageToken", err)
+	}
+}
+
+func testCountAndDelete(t *testing.T, repo repository.CouponRepository) {
+	ctx := context.Background()
+	for i, userID := range []string{"user-a", "user-a", "user-b", "", ""} {
+		mustCreateCoupon(t, repo, newCoupon(fmt.Sprintf("CODE-%d", i), "campaign-1", userID, time.Now()))
+	}
+	mustCreateCoupon(t, repo, newCoupon("OTHER", "campaign-2", "user-a", time.Now()))
+
+	total, byUser, err := repo.CountByCampaign(ctx, "campaign-1")
+	if err != nil || total != 5 || len(byUser) != 2 || byUser["user-a"] != 2 || byUser["user-b"] != 1 {
+		t.Fatalf("count by campaign: got %d, %v, %v", total, byUser, err)
+	}
+
+	if err := repo.DeleteByCampaignID(ctx, "campaign-1"); err != nil {
+		t.Fatalf("delete by campaign: %v", err)
+	}
+	if total, _, err := repo.CountByCampaign(ctx, "campaign-1"); err != nil || total != 0 {
+		t.Fatalf("count after delete: got %d, %v", total, err)
+	}
+	if _, err := repo.GetByCode(ctx, "CODE-0"); !errors.Is(err, repository.ErrCouponNotFound) {
+		t.Fatalf("get deleted coupon: got %v, want ErrCouponNotFound", err)
+	}
+	if _, err := repo.GetByCode(ctx, "OTHER"); err != nil {
+		t.Fatalf("get coupon of another campaign: %v", err)
+	}
+
+	// Deleted codes can be used again
+	mustCreateCoupon(t, repo, newCoupon("CODE-0", "campaign-1", "", time.Now()))
+}
+
+func testCreateOnce(t *testing.T, repo repository.CouponRepository) {
+	var (
+		created atomic.Int64
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			coupon := newCoupon("CODE-1", "campaign-1", fmt.Sprintf("user-%d", i), time.Now())
+			err := repo.Create(context.Background(), coupon)
+			switch {
+			case err == nil:
+				created.Add(1)
+			case !errors.Is(err, repository.ErrDuplicateCouponCode):
+				t.Errorf("create coupon: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if created.Load() != 1 {
+		t.Fatalf("code was stored %d times, want once", created.Load())
+	}
+	if total, _, err := repo.CountByCampaign(context.Background(), "campaign-1"); err != nil || total != 1 {
+		t.Fatalf("count by campaign: got %d, %v", total, err)
+	}
+}
@@ -0,0 +1,448 @@
+// internal/repository/sqlite/campaign.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+const campaignColumns = `id, name, total_coupons, issued_coupons, max_coupons_per_user, start_time, end_time, created_at, version, paused,
+	code_mode, code_key, code_serial, code_template`
+
+// campaignStatusExpr computes domain.Campaign.Status in SQL.
+// Both parameters are the current time in Unix nanoseconds.
+const campaignStatusExpr = `CASE
+		WHEN paused THEN 'paused'
+		WHEN start_time > ? THEN 'scheduled'
+		WHEN issued_coupons >= total_coupons THEN 'sold_out'
+		WHEN end_time != 0 AND end_time <= ? THEN 'ended'
+		ELSE 'active'
+	END`
+
+// CampaignRepository is a SQLite implementation of repository.CampaignRepository
+type CampaignRepository struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+var _ repository.CampaignRepository = (*CampaignRepository)(nil)
+
+// Create saves a new campaign
+func (r *CampaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	codeTemplate, err := encodeCodeTemplate(campaign.CodeTemplate)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `INSERT INTO campaigns (`+campaignColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		campaign.ID, campaign.Name, campaign.TotalCoupons, campaign.IssuedCoupons, campaign.MaxCouponsPerUser,
+		toNanos(campaign.StartTime), toNanos(campaign.EndTime), toNanos(campaign.CreatedAt), campaign.Version, campaign.Paused,
+		string(campaign.CodeMode), campaign.CodeKey, campaign.CodeSerial, codeTemplate)
+	return err
+}
+
+// Get retrieves a campaign by ID
+func (r *CampaignRepository) Get(ctx context.Context, id string) (*domain.Campaign, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE id = ?`, id)
+	return scanCampaign(row)
+}
+
+// Update replaces the settings of an existing campaign if its version matches
+func (r *CampaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `UPDATE campaigns
+		SET name = ?, total_coupons = ?, max_coupons_per_user = ?, start_time = ?, end_time = ?, created_at = ?, version = version + 1
+		WHERE id = ? AND version = ? AND issued_coupons <= ?
+		RETURNING issued_coupons, paused, code_serial, version`,
+		campaign.Name, campaign.TotalCoupons, campaign.MaxCouponsPerUser,
+		toNanos(campaign.StartTime), toNanos(campaign.EndTime), toNanos(campaign.CreatedAt),
+		campaign.ID, campaign.Version, campaign.TotalCoupons)
+
+	var (
+		issued  int
+		paused  bool
+		serial  int64
+		version int64
+	)
+	err = row.Scan(&issued, &paused, &serial, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Nothing matched; look at the stored campaign within the same
+		// transaction to tell why, so another process cannot change it meanwhile
+		stored, err := scanCampaign(tx.QueryRowContext(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE id = ?`, campaign.ID))
+		if err != nil {
+			return err
+		}
+		if stored.Version != campaign.Version {
+			return repository.ErrVersionConflict
+		}
+		return repository.ErrTotalBelowIssued
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	campaign.IssuedCoupons = issued
+	campaign.Paused = paused
+	campaign.CodeSerial = serial
+	campaign.Version = version
+	return nil
+}
+
+// AtomicIncrementIssued atomically increments the issued_coupons counter
+// and, when userID is set, the number of coupons issued to that user.
+// The campaign counter is guarded by a single conditional UPDATE, so other
+// processes sharing the database file can never push it past the total.
+func (r *CampaignRepository) AtomicIncrementIssued(ctx context.Context, campaignID, userID string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := incrementIssued(ctx, tx, campaignID, userID, r.clock.Now()); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// incrementIssued counts a coupon issued to userID within tx, failing if the
+// campaign cannot issue it at now
+func incrementIssued(ctx context.Context, tx *sql.Tx, campaignID, userID string, now time.Time) error {
+	nanos := now.UnixNano()
+	result, err := tx.ExecContext(ctx, `UPDATE campaigns SET issued_coupons = issued_coupons + 1
+		WHERE id = ? AND NOT paused AND issued_coupons < total_coupons
+			AND start_time <= ? AND (end_time = 0 OR end_time > ?)`,
+		campaignID, nanos, nanos)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return issueFailure(ctx, tx, campaignID, nanos)
+	}
+
+	if userID != "" {
+		// The upsert only changes a row while the user is under the campaign's
+		// cap (0 means no cap), so no rows affected means the cap was reached
+		result, err := tx.ExecContext(ctx, `INSERT INTO campaign_user_issued (campaign_id, user_id, issued)
+			SELECT id, ?, 1 FROM campaigns WHERE id = ?
+			ON CONFLICT (campaign_id, user_id) DO UPDATE SET issued = issued + 1
+			WHERE issued < (SELECT max_coupons_per_user FROM campaigns WHERE id = excluded.campaign_id)
+				OR (SELECT max_coupons_per_user FROM campaigns WHERE id = excluded.campaign_id) <= 0`,
+			userID, campaignID)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return repository.ErrUserLimitReached
+		}
+	}
+
+	return nil
+}
+
+// issueFailure explains why the conditional increment matched no campaign,
+// checking in the same order as the other backends
+func issueFailure(ctx context.Context, tx *sql.Tx, campaignID string, now int64) error {
+	campaign, err := scanCampaign(tx.QueryRowContext(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE id = ?`, campaignID))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case campaign.Paused:
+		return repository.ErrCampaignPaused
+	case campaign.IssuedCoupons >= campaign.TotalCoupons:
+		return repository.ErrLimitReached
+	case toNanos(campaign.StartTime) > now:
+		return repository.ErrCampaignNotStarted
+	default:
+		return repository.ErrCampaignEnded
+	}
+}
+
+// IssuedByUser returns how many coupons each user has been issued for a campaign
+func (r *CampaignRepository) IssuedByUser(ctx context.Context, campaignID string) (map[string]int, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM campaigns WHERE id = ?)`, campaignID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, repository.ErrCampaignNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT user_id, issued FROM campaign_user_issued WHERE campaign_id = ?`, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserCounts(rows)
+}
+
+// ReleaseIssued gives back issuance slots that never became stored coupons
+func (r *CampaignRepository) ReleaseIssued(ctx context.Context, campaignID string, total int, byUser map[string]int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE campaigns SET issued_coupons = MAX(issued_coupons - ?, 0) WHERE id = ?`, total, campaignID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrCampaignNotFound
+	}
+
+	for userID, count := range byUser {
+		if _, err := tx.ExecContext(ctx, `UPDATE campaign_user_issued SET issued = issued - ? WHERE campaign_id = ? AND user_id = ?`,
+			count, campaignID, userID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_user_issued WHERE campaign_id = ? AND issued <= 0`, campaignID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// NextCodeSerial hands out the campaign's next code serial
+func (r *CampaignRepository) NextCodeSerial(ctx context.Context, campaignID string) (int64, error) {
+	var serial int64
+	err := r.db.QueryRowContext(ctx, `UPDATE campaigns SET code_serial = code_serial + 1 WHERE id = ? RETURNING code_serial - 1`,
+		campaignID).Scan(&serial)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.ErrCampaignNotFound
+	}
+	return serial, err
+}
+
+// SetPaused pauses or resumes issuance for a campaign
+func (r *CampaignRepository) SetPaused(ctx context.Context, id string, paused bool) (*domain.Campaign, error) {
+	row := r.db.QueryRowContext(ctx, `UPDATE campaigns SET paused = ? WHERE id = ? RETURNING `+campaignColumns, paused, id)
+	return scanCampaign(row)
+}
+
+// FindByName finds a campaign by its name
+func (r *CampaignRepository) FindByName(ctx context.Context, name string) (*domain.Campaign, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+campaignColumns+` FROM campaigns WHERE name = ? LIMIT 1`, name)
+	return scanCampaign(row)
+}
+
+// DeleteByID deletes a campaign by ID
+func (r *CampaignRepository) DeleteByID(ctx context.Context, id string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	deleted, err := deleteCampaign(ctx, tx, id)
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// DeleteByName deletes a campaign by name
+func (r *CampaignRepository) DeleteByName(ctx context.Context, name string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM campaigns WHERE name = ? LIMIT 1`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := deleteCampaign(ctx, tx, id)
+	if err != nil || !deleted {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// deleteCampaign removes a campaign and its per-user counters
+func deleteCampaign(ctx context.Context, tx *sql.Tx, id string) (bool, error) {
+	result, err := tx.ExecContext(ctx, `DELETE FROM campaigns WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM campaign_user_issued WHERE campaign_id = ?`, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List returns one page of campaigns matching the filter, ordered by creation time and ID
+func (r *CampaignRepository) List(ctx context.Context, filter repository.CampaignFilter, page repository.Page) ([]*domain.Campaign, string, error) {
+	var (
+		where strings.Builder
+		args  []any
+	)
+	where.WriteString(`WHERE 1`)
+
+	if page.Token != "" {
+		cursorTime, cursorID, err := repository.DecodeCursor(page.Token)
+		if err != nil {
+			return nil, "", err
+		}
+		where.WriteString(` AND (created_at, id) > (?, ?)`)
+		args = append(args, toNanos(cursorTime), cursorID)
+	}
+	if filter.Status != "" {
+		where.WriteString(` AND ` + campaignStatusExpr + ` = ?`)
+		now := r.clock.Now().UnixNano()
+		args = append(args, now, now, string(filter.Status))
+	}
+	if filter.NamePrefix != "" {
+		where.WriteString(` AND substr(name, 1, length(?)) = ?`)
+		args = append(args, filter.NamePrefix, filter.NamePrefix)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where.WriteString(` AND created_at >= ?`)
+		args = append(args, filter.CreatedAfter.UnixNano())
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where.WriteString(` AND created_at < ?`)
+		args = append(args, filter.CreatedBefore.UnixNano())
+	}
+
+	query := `SELECT ` + campaignColumns + ` FROM campaigns ` + where.String() + ` ORDER BY created_at, id`
+	if page.Size > 0 {
+		// Fetch one extra row to learn whether there is a next page
+		query += ` LIMIT ?`
+		args = append(args, page.Size+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	campaigns := make([]*domain.Campaign, 0)
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		campaigns = append(campaigns, campaign)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if page.Size <= 0 || len(campaigns) <= page.Size {
+		return campaigns, "", nil
+	}
+
+	campaigns = campaigns[:page.Size]
+	last := campaigns[len(campaigns)-1]
+	return campaigns, repository.EncodeCursor(last.CreatedAt, last.ID), nil
+}
+
+// scanUserCounts reads (user_id, count) rows into a map and closes them
+func scanUserCounts(rows *sql.Rows) (map[string]int, error) {
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			userID string
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	return counts, rows.Err()
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCampaign reads a row selected with campaignColumns
+func scanCampaign(row scanner) (*domain.Campaign, error) {
+	var (
+		campaign                      domain.Campaign
+		startTime, endTime, createdAt int64
+		codeMode, codeTemplate        string
+	)
+	err := row.Scan(&campaign.ID, &campaign.Name, &campaign.TotalCoupons, &campaign.IssuedCoupons,
+		&campaign.MaxCouponsPerUser, &startTime, &endTime, &createdAt, &campaign.Version, &campaign.Paused,
+		&codeMode, &campaign.CodeKey, &campaign.CodeSerial, &codeTemplate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	campaign.StartTime = fromNanos(startTime)
+	campaign.EndTime = fromNanos(endTime)
+	campaign.CreatedAt = fromNanos(createdAt)
+	campaign.CodeMode = domain.CodeMode(codeMode)
+	if codeTemplate != "" {
+		campaign.CodeTemplate = new(domain.CodeTemplate)
+		if err := json.Unmarshal([]byte(codeTemplate), campaign.CodeTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return &campaign, nil
+}
+
+// encodeCodeTemplate stores a code template as JSON, keeping no template as an empty string
+func encodeCodeTemplate(template *domain.CodeTemplate) (string, error) {
+	if template == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(template)
+	return string(data), err
+}
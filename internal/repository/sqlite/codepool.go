@@ -0,0 +1,75 @@
+// internal/repository/sqlite/codepool.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// CodePoolRepository is a SQLite implementation of repository.CodePoolRepository
+type CodePoolRepository struct {
+	db *sql.DB
+}
+
+var _ repository.CodePoolRepository = (*CodePoolRepository)(nil)
+
+// AddCodes adds codes to a campaign's pool in one transaction, returning the duplicates
+func (r *CodePoolRepository) AddCodes(ctx context.Context, campaignID string, codes []string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM campaigns WHERE id = ?`, campaignID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrCampaignNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Codes already pooled hit the unique index and are skipped; codes taken
+	// by a coupon are filtered out by the WHERE clause
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO code_pool (campaign_id, code)
+		SELECT ?, ? WHERE NOT EXISTS (SELECT 1 FROM coupons WHERE code = ?)
+		ON CONFLICT (code) DO NOTHING`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var duplicates []string
+	for _, code := range codes {
+		result, err := stmt.ExecContext(ctx, campaignID, code, code)
+		if err != nil {
+			return nil, err
+		}
+		added, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if added == 0 {
+			duplicates = append(duplicates, code)
+		}
+	}
+
+	return duplicates, tx.Commit()
+}
+
+// CountCodes returns the number of codes left in a campaign's pool
+func (r *CodePoolRepository) CountCodes(ctx context.Context, campaignID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM code_pool WHERE campaign_id = ?`, campaignID).Scan(&count)
+	return count, err
+}
+
+// DeleteByCampaignID removes a campaign's pool
+func (r *CodePoolRepository) DeleteByCampaignID(ctx context.Context, campaignID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM code_pool WHERE campaign_id = ?`, campaignID)
+	return err
+}
@@ -0,0 +1,46 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/repotest"
+)
+
+// openConformanceDB opens an empty database that is closed after the test
+func openConformanceDB(t *testing.T, clk clock.Clock) *DB {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "coupons.db"), Options{Clock: clk})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestCampaignRepositoryConformance(t *testing.T) {
+	repotest.TestCampaignRepository(t, func(t *testing.T, clk clock.Clock) repository.CampaignRepository {
+		return openConformanceDB(t, clk).CampaignRepository()
+	})
+}
+
+func TestCouponRepositoryConformance(t *testing.T) {
+	repotest.TestCouponRepository(t, func(t *testing.T) repository.CouponRepository {
+		return openConformanceDB(t, nil).CouponRepository()
+	})
+}
+
+func TestCodePoolRepositoryConformance(t *testing.T) {
+	repotest.TestCodePoolRepository(t, func(t *testing.T) repotest.CodePoolRepositories {
+		db := openConformanceDB(t, nil)
+		return repotest.CodePoolRepositories{
+			Campaigns: db.CampaignRepository(),
+			Coupons:   db.CouponRepository(),
+			CodePool:  db.CodePoolRepository(),
+			Issuance:  db.IssuanceRepository(),
+		}
+	})
+}
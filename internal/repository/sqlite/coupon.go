@@ -0,0 +1,196 @@
+// internal/repository/sqlite/coupon.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+const couponColumns = `code, campaign_id, user_id, status, issued_at, redeemed_at`
+
+// CouponRepository is a SQLite implementation of repository.CouponRepository
+type CouponRepository struct {
+	db *sql.DB
+}
+
+var _ repository.CouponRepository = (*CouponRepository)(nil)
+
+// Create saves a new coupon
+func (r *CouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	return insertCoupon(ctx, r.db, coupon)
+}
+
+// execer is implemented by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertCoupon stores a new coupon, reporting a taken code as ErrDuplicateCouponCode
+func insertCoupon(ctx context.Context, db execer, coupon *domain.Coupon) error {
+	_, err := db.ExecContext(ctx, `INSERT INTO coupons (`+couponColumns+`) VALUES (?, ?, ?, ?, ?, ?)`,
+		coupon.Code, coupon.CampaignID, coupon.UserID, string(coupon.Status),
+		toNanos(coupon.IssuedAt), toNanos(coupon.RedeemedAt))
+	if isUniqueViolation(err) {
+		return repository.ErrDuplicateCouponCode
+	}
+	return err
+}
+
+// GetByCampaign retrieves one page of a campaign's coupons in issue order
+func (r *CouponRepository) GetByCampaign(ctx context.Context, campaignID string, page repository.Page) ([]*domain.Coupon, string, error) {
+	query := `SELECT ` + couponColumns + ` FROM coupons WHERE campaign_id = ?`
+	args := []any{campaignID}
+
+	if page.Token != "" {
+		cursorTime, cursorCode, err := repository.DecodeCursor(page.Token)
+		if err != nil {
+			return nil, "", err
+		}
+		query += ` AND (issued_at, code) > (?, ?)`
+		args = append(args, toNanos(cursorTime), cursorCode)
+	}
+
+	query += ` ORDER BY issued_at, code`
+	if page.Size > 0 {
+		// Fetch one extra row to learn whether there is a next page
+		query += ` LIMIT ?`
+		args = append(args, page.Size+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	coupons := make([]*domain.Coupon, 0)
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		coupons = append(coupons, coupon)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if page.Size <= 0 || len(coupons) <= page.Size {
+		return coupons, "", nil
+	}
+
+	coupons = coupons[:page.Size]
+	last := coupons[len(coupons)-1]
+	return coupons, repository.EncodeCursor(last.IssuedAt, last.Code), nil
+}
+
+// GetByCode retrieves a coupon by its code
+func (r *CouponRepository) GetByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+couponColumns+` FROM coupons WHERE code = ?`, code)
+	return scanCoupon(row)
+}
+
+// Redeem atomically transitions an issued coupon to redeemed
+func (r *CouponRepository) Redeem(ctx context.Context, code string, redeemedAt time.Time) (*domain.Coupon, error) {
+	row := r.db.QueryRowContext(ctx, `UPDATE coupons SET status = ?, redeemed_at = ?
+		WHERE code = ? AND status = ?
+		RETURNING `+couponColumns,
+		string(domain.CouponStatusRedeemed), toNanos(redeemedAt), code, string(domain.CouponStatusIssued))
+
+	coupon, err := scanCoupon(row)
+	if !errors.Is(err, repository.ErrCouponNotFound) {
+		return coupon, err
+	}
+	return nil, r.notIssued(ctx, code)
+}
+
+// Revoke atomically transitions an issued coupon to revoked
+func (r *CouponRepository) Revoke(ctx context.Context, code string) (*domain.Coupon, error) {
+	row := r.db.QueryRowContext(ctx, `UPDATE coupons SET status = ?
+		WHERE code = ? AND status = ?
+		RETURNING `+couponColumns,
+		string(domain.CouponStatusRevoked), code, string(domain.CouponStatusIssued))
+
+	coupon, err := scanCoupon(row)
+	if !errors.Is(err, repository.ErrCouponNotFound) {
+		return coupon, err
+	}
+	return nil, r.notIssued(ctx, code)
+}
+
+// notIssued tells why a coupon that an update expected to be issued was not
+// matched. A coupon's status only moves away from issued, so the stored
+// status still explains the miss.
+func (r *CouponRepository) notIssued(ctx context.Context, code string) error {
+	stored, err := r.GetByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	switch stored.Status {
+	case domain.CouponStatusExpired:
+		return repository.ErrCouponExpired
+	case domain.CouponStatusRevoked:
+		return repository.ErrCouponRevoked
+	default:
+		return repository.ErrCouponAlreadyRedeemed
+	}
+}
+
+// CountByCampaign counts a campaign's stored coupons in total and per user
+func (r *CouponRepository) CountByCampaign(ctx context.Context, campaignID string) (int, map[string]int, error) {
+	// Both counts come from one read transaction so they agree with each other
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	var total int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM coupons WHERE campaign_id = ?`, campaignID).Scan(&total); err != nil {
+		return 0, nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT user_id, COUNT(*) FROM coupons
+		WHERE campaign_id = ? AND user_id != ''
+		GROUP BY user_id`, campaignID)
+	if err != nil {
+		return 0, nil, err
+	}
+	byUser, err := scanUserCounts(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	return total, byUser, nil
+}
+
+// DeleteByCampaignID deletes all coupons for a specific campaign
+func (r *CouponRepository) DeleteByCampaignID(ctx context.Context, campaignID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM coupons WHERE campaign_id = ?`, campaignID)
+	return err
+}
+
+// scanCoupon reads a row selected with couponColumns
+func scanCoupon(row scanner) (*domain.Coupon, error) {
+	var (
+		coupon               domain.Coupon
+		status               string
+		issuedAt, redeemedAt int64
+	)
+	err := row.Scan(&coupon.Code, &coupon.CampaignID, &coupon.UserID, &status, &issuedAt, &redeemedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrCouponNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	coupon.Status = domain.CouponStatus(status)
+	coupon.IssuedAt = fromNanos(issuedAt)
+	coupon.RedeemedAt = fromNanos(redeemedAt)
+	return &coupon, nil
+}
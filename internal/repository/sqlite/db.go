@@ -0,0 +1,200 @@
+// internal/repository/sqlite/db.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	sqlitedriver "modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+)
+
+// migrations are applied in order; the schema version is the number of
+// applied migrations. Never edit an existing entry, append a new one instead.
+var migrations = []string{
+	`CREATE TABLE campaigns (
+		id                   TEXT PRIMARY KEY,
+		name                 TEXT NOT NULL,
+		total_coupons        INTEGER NOT NULL,
+		issued_coupons       INTEGER NOT NULL DEFAULT 0,
+		max_coupons_per_user INTEGER NOT NULL DEFAULT 0,
+		start_time           INTEGER NOT NULL,
+		end_time             INTEGER NOT NULL DEFAULT 0,
+		created_at           INTEGER NOT NULL,
+		version              INTEGER NOT NULL DEFAULT 0,
+		paused               INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX campaigns_created ON campaigns (created_at, id);
+	CREATE INDEX campaigns_name ON campaigns (name);
+
+	CREATE TABLE campaign_user_issued (
+		campaign_id TEXT NOT NULL,
+		user_id     TEXT NOT NULL,
+		issued      INTEGER NOT NULL,
+		PRIMARY KEY (campaign_id, user_id)
+	);
+
+	CREATE TABLE coupons (
+		code        TEXT NOT NULL,
+		campaign_id TEXT NOT NULL,
+		user_id     TEXT NOT NULL DEFAULT '',
+		status      TEXT NOT NULL,
+		issued_at   INTEGER NOT NULL,
+		redeemed_at INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE UNIQUE INDEX coupons_code ON coupons (code);
+	CREATE INDEX coupons_campaign ON coupons (campaign_id, issued_at, code);`,
+
+	`ALTER TABLE campaigns ADD COLUMN code_mode TEXT NOT NULL DEFAULT '';
+	ALTER TABLE campaigns ADD COLUMN code_key BLOB;
+	ALTER TABLE campaigns ADD COLUMN code_serial INTEGER NOT NULL DEFAULT 0;`,
+
+	`ALTER TABLE campaigns ADD COLUMN code_template TEXT NOT NULL DEFAULT '';`,
+
+	`CREATE TABLE code_pool (
+		seq         INTEGER PRIMARY KEY,
+		campaign_id TEXT NOT NULL,
+		code        TEXT NOT NULL
+	);
+	CREATE UNIQUE INDEX code_pool_code ON code_pool (code);
+	CREATE INDEX code_pool_campaign ON code_pool (campaign_id, seq);`,
+}
+
+// Options configures a DB
+type Options struct {
+	// Clock is what start and end times are checked against; nil means the
+	// system clock. Processes sharing a database should use the same clock.
+	Clock clock.Clock
+}
+
+// DB is a repository backend stored in a single SQLite database file.
+// Several processes may open the same file; every check-and-update is done
+// by one statement or transaction so the guarantees hold across them.
+type DB struct {
+	db *sql.DB
+
+	campaigns *CampaignRepository
+	coupons   *CouponRepository
+	issuance  *IssuanceRepository
+	codePool  *CodePoolRepository
+}
+
+// Open opens the database at path, creating it if needed, and applies pending migrations
+func Open(path string, opts Options) (*DB, error) {
+	// WAL lets readers run alongside the single writer, busy_timeout makes
+	// writers from other connections or processes wait instead of failing,
+	// and immediate transactions take the write lock up front so two
+	// read-then-write transactions cannot deadlock on the upgrade.
+	query := url.Values{}
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "synchronous(NORMAL)")
+	query.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	clk := clock.OrReal(opts.Clock)
+	d := &DB{db: db}
+	d.campaigns = &CampaignRepository{db: db, clock: clk}
+	d.coupons = &CouponRepository{db: db}
+	d.issuance = &IssuanceRepository{db: db, clock: clk}
+	d.codePool = &CodePoolRepository{db: db}
+	return d, nil
+}
+
+// CampaignRepository returns the database's campaign repository
+func (d *DB) CampaignRepository() *CampaignRepository {
+	return d.campaigns
+}
+
+// CouponRepository returns the database's coupon repository
+func (d *DB) CouponRepository() *CouponRepository {
+	return d.coupons
+}
+
+// IssuanceRepository returns the database's issuance repository
+func (d *DB) IssuanceRepository() *IssuanceRepository {
+	return d.issuance
+}
+
+// CodePoolRepository returns the database's code pool repository
+func (d *DB) CodePoolRepository() *CodePoolRepository {
+	return d.codePool
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// migrate brings the schema up to date in a single transaction
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// toNanos stores a time as Unix nanoseconds, keeping the zero time as 0
+func toNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// fromNanos reverses toNanos
+func fromNanos(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// isUniqueViolation checks if err was caused by a UNIQUE or PRIMARY KEY constraint
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlitelib.SQLITE_CONSTRAINT_UNIQUE || code == sqlitelib.SQLITE_CONSTRAINT_PRIMARYKEY
+}
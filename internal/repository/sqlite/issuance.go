@@ -0,0 +1,92 @@
+// internal/repository/sqlite/issuance.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// IssuanceRepository is a SQLite implementation of repository.IssuanceRepository
+type IssuanceRepository struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+var _ repository.IssuanceRepository = (*IssuanceRepository)(nil)
+
+// IssueCoupon increments the counters and inserts the coupon in one transaction
+func (r *IssuanceRepository) IssueCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := incrementIssued(ctx, tx, coupon.CampaignID, coupon.UserID, r.clock.Now()); err != nil {
+		return err
+	}
+
+	var pooled bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM code_pool WHERE code = ?)`, coupon.Code).Scan(&pooled)
+	if err != nil {
+		return err
+	}
+	if pooled {
+		return repository.ErrDuplicateCouponCode
+	}
+
+	if err := insertCoupon(ctx, tx, coupon); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IssuePooledCoupon increments the counters, takes the campaign's oldest
+// pooled code and inserts the coupon in one transaction
+func (r *IssuanceRepository) IssuePooledCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := incrementIssued(ctx, tx, coupon.CampaignID, coupon.UserID, r.clock.Now()); err != nil {
+		return err
+	}
+
+	pooled := *coupon
+	for {
+		err := tx.QueryRowContext(ctx, `DELETE FROM code_pool
+			WHERE seq = (SELECT seq FROM code_pool WHERE campaign_id = ? ORDER BY seq LIMIT 1)
+			RETURNING code`, coupon.CampaignID).Scan(&pooled.Code)
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrCodePoolEmpty
+		}
+		if err != nil {
+			return err
+		}
+
+		// Imports skip taken codes, but a coupon saved through
+		// CouponRepository.Create directly may have taken it since
+		err = insertCoupon(ctx, tx, &pooled)
+		if errors.Is(err, repository.ErrDuplicateCouponCode) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	coupon.Code = pooled.Code
+	return nil
+}
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "coupons.db"), Options{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	campaign := &domain.Campaign{
+		ID:                "campaign-1",
+		Name:              "test",
+		TotalCoupons:      1,
+		MaxCouponsPerUser: 1,
+		StartTime:         time.Now().Add(-time.Minute),
+		CreatedAt:         time.Now().Add(-time.Minute),
+		Version:           1,
+	}
+	if err := db.CampaignRepository().Create(context.Background(), campaign); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	return db
+}
+
+func newTestCoupon(code string) *domain.Coupon {
+	return &domain.Coupon{
+		Code:       code,
+		CampaignID: "campaign-1",
+		UserID:     "user-1",
+		Status:     domain.CouponStatusIssued,
+		IssuedAt:   time.Now(),
+	}
+}
+
+func TestIssueCouponFailedInsertRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	// Make the coupon store reject every insert
+	if _, err := db.db.Exec(`CREATE TRIGGER fail_coupons BEFORE INSERT ON coupons BEGIN SELECT RAISE(ABORT, 'coupon store unavailable'); END`); err != nil {
+		t.Fatalf("create trigger: %v", err)
+	}
+
+	if err := db.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE1")); err == nil {
+		t.Fatal("IssueCoupon succeeded with a failing coupon insert")
+	}
+
+	campaign, err := db.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 0 {
+		t.Fatalf("issued coupons after failed insert = %d, want 0", campaign.IssuedCoupons)
+	}
+
+	var userRows int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM campaign_user_issued`).Scan(&userRows); err != nil {
+		t.Fatalf("count user counters: %v", err)
+	}
+	if userRows != 0 {
+		t.Fatalf("user counters after failed insert = %d, want 0", userRows)
+	}
+
+	// Once the store works again the user's only coupon can still be issued
+	if _, err := db.db.Exec(`DROP TRIGGER fail_coupons`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if err := db.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("CODE1")); err != nil {
+		t.Fatalf("IssueCoupon after store recovered: %v", err)
+	}
+}
+
+func TestIssueCouponDuplicateCodeRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	if err := db.CouponRepository().Create(ctx, newTestCoupon("TAKEN")); err != nil {
+		t.Fatalf("create coupon: %v", err)
+	}
+
+	err := db.IssuanceRepository().IssueCoupon(ctx, newTestCoupon("TAKEN"))
+	if !errors.Is(err, repository.ErrDuplicateCouponCode) {
+		t.Fatalf("IssueCoupon with taken code: got error %v, want %v", err, repository.ErrDuplicateCouponCode)
+	}
+
+	campaign, err := db.CampaignRepository().Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 0 {
+		t.Fatalf("issued coupons after duplicate code = %d, want 0", campaign.IssuedCoupons)
+	}
+}
@@ -4,9 +4,11 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
 	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
 	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
 	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
@@ -16,37 +18,169 @@ var (
 	ErrInvalidRequest     = errors.New("invalid request parameters")
 	ErrCampaignNotFound   = errors.New("campaign not found")
 	ErrCampaignNotStarted = errors.New("campaign has not started yet")
+	ErrCampaignEnded      = errors.New("campaign has ended")
+	ErrCampaignPaused     = errors.New("campaign is paused")
 	ErrNoMoreCoupons      = errors.New("no more coupons available")
 	ErrDuplicateCampaign  = errors.New("a campaign with this name already exists")
 	ErrPastStartTime      = errors.New("campaign start time cannot be in the past")
+	ErrInvalidEndTime     = errors.New("campaign end time must be after its start time")
+	ErrInvalidPageToken   = errors.New("invalid page token")
+	ErrVersionConflict    = errors.New("campaign was modified concurrently")
+	ErrTotalBelowIssued   = errors.New("total coupons cannot be less than issued coupons")
+	ErrAlreadyStarted     = errors.New("campaign has already started")
+	ErrUserIDRequired     = errors.New("user ID is required for this campaign")
+	ErrUserLimitReached   = errors.New("user has reached the coupon limit for this campaign")
+	ErrCodeSpaceExhausted = errors.New("no unused coupon code is left for this campaign")
+	ErrSigningDisabled    = errors.New("signed coupon codes need code signing keys on the server")
+	ErrInvalidCodeFormat  = errors.New("invalid coupon code template")
+	ErrCodeSpaceTooSmall  = errors.New("coupon code template has too few codes for the total coupons")
+	ErrNotImportable      = errors.New("codes can only be imported into campaigns with imported codes")
+	ErrInvalidImportCode  = errors.New("invalid imported coupon code")
+
+	ErrCouponNotFound        = errors.New("coupon not found")
+	ErrMalformedCouponCode   = errors.New("coupon code is mistyped")
+	ErrCouponAlreadyRedeemed = errors.New("coupon has already been redeemed")
+	ErrCouponExpired         = errors.New("coupon has expired")
+	ErrCouponRevoked         = errors.New("coupon has been revoked")
+)
+
+const (
+	// DefaultPageSize is used when a listing request does not specify a page size
+	DefaultPageSize = 50
+	// MaxPageSize caps the number of items returned in a single page
+	MaxPageSize = 500
 )
 
+// InvalidReason explains why a coupon code cannot be used
+type InvalidReason string
+
+const (
+	InvalidReasonNone            InvalidReason = ""
+	InvalidReasonUnknownCode     InvalidReason = "unknown_code"
+	InvalidReasonRedeemed        InvalidReason = "redeemed"
+	InvalidReasonExpired         InvalidReason = "expired"
+	InvalidReasonRevoked         InvalidReason = "revoked"
+	InvalidReasonCampaignDeleted InvalidReason = "campaign_deleted"
+	InvalidReasonMalformedCode   InvalidReason = "malformed_code"
+)
+
+// CouponValidation is the result of checking a coupon code without redeeming it
+type CouponValidation struct {
+	Coupon   *domain.Coupon
+	Campaign *domain.Campaign
+	Reason   InvalidReason
+}
+
+// Valid reports whether the coupon can be redeemed
+func (v *CouponValidation) Valid() bool {
+	return v.Reason == InvalidReasonNone
+}
+
+// CampaignUpdate lists the campaign settings to change. Nil fields are left untouched.
+type CampaignUpdate struct {
+	Name         *string
+	StartTime    *time.Time
+	TotalCoupons *int
+	// ExpectedVersion is the campaign version the update is based on; 0 skips the check
+	ExpectedVersion int64
+}
+
 // CampaignService handles campaign-related business logic
 type CampaignService struct {
 	campaignRepo repository.CampaignRepository
 	couponRepo   repository.CouponRepository
+	issuanceRepo repository.IssuanceRepository
+	codePoolRepo repository.CodePoolRepository
+	options      Options
+	clock        clock.Clock
+
+	// codeSourcesMutex guards codeSources
+	codeSourcesMutex sync.Mutex
+	// codeSources holds each campaign's coupon code source
+	codeSources map[string]*codeSource
+	// pregenCounters track the pre-generated code pools
+	pregenCounters pregenCounters
+}
+
+// maxIssueAttempts bounds how often IssueCoupon draws a new code after
+// hitting one that is already taken
+const maxIssueAttempts = 10
+
+// Options configures a CampaignService
+type Options struct {
+	// CodeKeys signs the codes of signed-mode campaigns; without it such
+	// campaigns cannot be created or issue coupons
+	CodeKeys *coupongen.Keyring
+
+	// PregenSize is how many random codes RunPregeneration keeps ready per
+	// campaign; 0 disables pre-generation
+	PregenSize int
+	// PregenLead is how long before its start a campaign's pool is filled
+	PregenLead time.Duration
+
+	// Clock is what start and end times are checked against; nil means the
+	// system clock. The repositories should be given the same clock.
+	Clock clock.Clock
 }
 
 // NewCampaignService creates a new campaign service
-func NewCampaignService(campaignRepo repository.CampaignRepository, couponRepo repository.CouponRepository) *CampaignService {
+func NewCampaignService(campaignRepo repository.CampaignRepository, couponRepo repository.CouponRepository, issuanceRepo repository.IssuanceRepository, codePoolRepo repository.CodePoolRepository, options Options) *CampaignService {
 	return &CampaignService{
 		campaignRepo: campaignRepo,
 		couponRepo:   couponRepo,
+		issuanceRepo: issuanceRepo,
+		codePoolRepo: codePoolRepo,
+		options:      options,
+		clock:        clock.OrReal(options.Clock),
+		codeSources:  make(map[string]*codeSource),
 	}
 }
 
-// CreateCampaign creates a new coupon campaign
-func (s *CampaignService) CreateCampaign(ctx context.Context, name string, totalCoupons int, startTime time.Time) (*domain.Campaign, error) {
+// Now returns the current time on the service's clock
+func (s *CampaignService) Now() time.Time {
+	return s.clock.Now()
+}
+
+// CreateCampaign creates a new coupon campaign.
+// A maxCouponsPerUser of 0 means users are not limited, and a zero endTime
+// means the campaign stays open until it runs out of coupons. An empty
+// codeMode means random codes, and a nil codeTemplate the default code format.
+func (s *CampaignService) CreateCampaign(ctx context.Context, name string, totalCoupons, maxCouponsPerUser int, startTime, endTime time.Time, codeMode domain.CodeMode, codeTemplate *domain.CodeTemplate) (*domain.Campaign, error) {
 	// Validate input
-	if name == "" || totalCoupons <= 0 {
+	if name == "" || totalCoupons <= 0 || maxCouponsPerUser < 0 {
+		return nil, ErrInvalidRequest
+	}
+
+	switch codeMode {
+	case "", domain.CodeModeRandom, domain.CodeModePermuted:
+	case domain.CodeModeImported:
+		// Imported codes come as they are
+		if codeTemplate != nil {
+			return nil, ErrInvalidCodeFormat
+		}
+	case domain.CodeModeSigned:
+		if s.options.CodeKeys == nil {
+			return nil, ErrSigningDisabled
+		}
+		// Signed codes have a fixed format
+		if codeTemplate != nil {
+			return nil, ErrInvalidCodeFormat
+		}
+	default:
 		return nil, ErrInvalidRequest
 	}
 
 	// Check if start time is in the past
-	if startTime.Before(time.Now()) {
+	now := s.clock.Now()
+	if startTime.Before(now) {
 		return nil, ErrPastStartTime
 	}
 
+	// Check if end time comes after start time
+	if !endTime.IsZero() && !endTime.After(startTime) {
+		return nil, ErrInvalidEndTime
+	}
+
 	// Check if a campaign with the same name already exists
 	existingCampaign, err := s.campaignRepo.FindByName(ctx, name)
 	if err == nil && existingCampaign != nil {
@@ -55,12 +189,34 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, name string, total
 
 	// Create campaign
 	campaign := &domain.Campaign{
-		ID:            uuid.New().String(),
-		Name:          name,
-		TotalCoupons:  totalCoupons,
-		IssuedCoupons: 0,
-		StartTime:     startTime,
-		CreatedAt:     time.Now(),
+		ID:                uuid.New().String(),
+		Name:              name,
+		TotalCoupons:      totalCoupons,
+		IssuedCoupons:     0,
+		MaxCouponsPerUser: maxCouponsPerUser,
+		StartTime:         startTime,
+		EndTime:           endTime,
+		CreatedAt:         now,
+		Version:           1,
+		CodeMode:          codeMode,
+	}
+
+	// Store the template with its defaults, so the codes keep their format
+	// even if the defaults change
+	if codeTemplate != nil {
+		template := fromCodeTemplate(codeTemplate).WithDefaults()
+		campaign.CodeTemplate = toCodeTemplate(template)
+	}
+	if err := checkCodeSpace(campaign, totalCoupons); err != nil {
+		return nil, err
+	}
+
+	// Permuted codes are only as secret as the campaign's key
+	if campaign.UsesPermutedCodes() {
+		campaign.CodeKey, err = coupongen.NewKey()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Save campaign
@@ -72,25 +228,203 @@ func (s *CampaignService) CreateCampaign(ctx context.Context, name string, total
 	return campaign, nil
 }
 
-// GetCampaign retrieves a campaign by ID
-func (s *CampaignService) GetCampaign(ctx context.Context, id string) (*domain.Campaign, []*domain.Coupon, error) {
+// UpdateCampaign applies safe changes to a campaign: renaming it, moving its
+// start time while it has not started yet, and changing TotalCoupons as long as
+// it does not drop below the coupons already issued.
+func (s *CampaignService) UpdateCampaign(ctx context.Context, id string, update CampaignUpdate) (*domain.Campaign, error) {
+	if id == "" || (update.Name == nil && update.StartTime == nil && update.TotalCoupons == nil) {
+		return nil, ErrInvalidRequest
+	}
+
+	// Get campaign
+	campaign, err := s.campaignRepo.Get(ctx, id)
+	if err != nil {
+		return nil, ErrCampaignNotFound
+	}
+
+	if update.ExpectedVersion != 0 && update.ExpectedVersion != campaign.Version {
+		return nil, ErrVersionConflict
+	}
+
+	// Work on a copy so the stored campaign is only changed by the repository
+	updated := *campaign
+
+	if update.Name != nil {
+		if *update.Name == "" {
+			return nil, ErrInvalidRequest
+		}
+		if *update.Name != campaign.Name {
+			existingCampaign, err := s.campaignRepo.FindByName(ctx, *update.Name)
+			if err == nil && existingCampaign != nil {
+				return nil, ErrDuplicateCampaign
+			}
+		}
+		updated.Name = *update.Name
+	}
+
+	if update.StartTime != nil {
+		// Moving the start time of a running campaign would change who got coupons
+		now := s.clock.Now()
+		if campaign.HasStarted(now) {
+			return nil, ErrAlreadyStarted
+		}
+		if update.StartTime.Before(now) {
+			return nil, ErrPastStartTime
+		}
+		if campaign.HasEndTime() && !campaign.EndTime.After(*update.StartTime) {
+			return nil, ErrInvalidEndTime
+		}
+		updated.StartTime = *update.StartTime
+	}
+
+	if update.TotalCoupons != nil {
+		if *update.TotalCoupons <= 0 {
+			return nil, ErrInvalidRequest
+		}
+		if *update.TotalCoupons < campaign.IssuedCoupons {
+			return nil, ErrTotalBelowIssued
+		}
+		if err := checkCodeSpace(campaign, *update.TotalCoupons); err != nil {
+			return nil, err
+		}
+		updated.TotalCoupons = *update.TotalCoupons
+	}
+
+	// Save campaign; the repository rejects the write if another update won the race
+	if err := s.campaignRepo.Update(ctx, &updated); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			return nil, ErrVersionConflict
+		case errors.Is(err, repository.ErrTotalBelowIssued):
+			return nil, ErrTotalBelowIssued
+		case errors.Is(err, repository.ErrCampaignNotFound):
+			return nil, ErrCampaignNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &updated, nil
+}
+
+// PauseCampaign stops issuance for a campaign immediately, keeping the campaign and its coupons.
+// Pausing an already paused campaign is a no-op.
+func (s *CampaignService) PauseCampaign(ctx context.Context, id string) (*domain.Campaign, error) {
+	return s.setPaused(ctx, id, true)
+}
+
+// ResumeCampaign restarts issuance for a paused campaign.
+// Resuming a campaign that is not paused is a no-op.
+func (s *CampaignService) ResumeCampaign(ctx context.Context, id string) (*domain.Campaign, error) {
+	return s.setPaused(ctx, id, false)
+}
+
+// setPaused switches a campaign between the paused and active states
+func (s *CampaignService) setPaused(ctx context.Context, id string, paused bool) (*domain.Campaign, error) {
+	if id == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	campaign, err := s.campaignRepo.SetPaused(ctx, id, paused)
+	if err != nil {
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			return nil, ErrCampaignNotFound
+		}
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// GetCampaign retrieves a campaign by ID.
+// If userID is not empty, only coupons issued to that user are returned.
+// If withCoupons is false, coupons are not loaded and nil is returned for them.
+func (s *CampaignService) GetCampaign(ctx context.Context, id, userID string, withCoupons bool) (*domain.Campaign, []*domain.Coupon, error) {
 	// Get campaign
 	campaign, err := s.campaignRepo.Get(ctx, id)
 	if err != nil {
 		return nil, nil, ErrCampaignNotFound
 	}
 
+	if !withCoupons {
+		return campaign, nil, nil
+	}
+
 	// Get coupons for this campaign
-	coupons, err := s.couponRepo.GetByCampaign(ctx, id)
+	coupons, _, err := s.couponRepo.GetByCampaign(ctx, id, repository.Page{})
 	if err != nil {
 		return campaign, nil, err
 	}
 
+	// Filter coupons by user if requested
+	if userID != "" {
+		filtered := make([]*domain.Coupon, 0)
+		for _, c := range coupons {
+			if c.UserID == userID {
+				filtered = append(filtered, c)
+			}
+		}
+		coupons = filtered
+	}
+
 	return campaign, coupons, nil
 }
 
-// IssueCoupon issues a coupon for a campaign
-func (s *CampaignService) IssueCoupon(ctx context.Context, campaignID string) (*domain.Coupon, error) {
+// ListCampaigns returns one page of campaigns matching the filter and the token of the next page
+func (s *CampaignService) ListCampaigns(ctx context.Context, filter repository.CampaignFilter, pageToken string, pageSize int) ([]*domain.Campaign, string, error) {
+	if pageSize < 0 {
+		return nil, "", ErrInvalidRequest
+	}
+
+	// Clamp page size so a single request cannot return every campaign
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	campaigns, nextPageToken, err := s.campaignRepo.List(ctx, filter, repository.Page{Token: pageToken, Size: pageSize})
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPageToken) {
+			return nil, "", ErrInvalidPageToken
+		}
+		return nil, "", err
+	}
+
+	return campaigns, nextPageToken, nil
+}
+
+// ListCoupons returns one page of a campaign's coupons in issue order and the token of the next page
+func (s *CampaignService) ListCoupons(ctx context.Context, campaignID, pageToken string, pageSize int) ([]*domain.Coupon, string, error) {
+	if campaignID == "" || pageSize < 0 {
+		return nil, "", ErrInvalidRequest
+	}
+
+	// Make sure the campaign exists so unknown IDs are not reported as empty
+	if _, err := s.campaignRepo.Get(ctx, campaignID); err != nil {
+		return nil, "", ErrCampaignNotFound
+	}
+
+	// Clamp page size so a single request cannot return every coupon
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	coupons, nextPageToken, err := s.couponRepo.GetByCampaign(ctx, campaignID, repository.Page{Token: pageToken, Size: pageSize})
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPageToken) {
+			return nil, "", ErrInvalidPageToken
+		}
+		return nil, "", err
+	}
+
+	return coupons, nextPageToken, nil
+}
+
+// IssueCoupon issues a coupon for a campaign to the given user
+func (s *CampaignService) IssueCoupon(ctx context.Context, campaignID, userID string) (*domain.Coupon, error) {
 	// Get campaign
 	campaign, err := s.campaignRepo.Get(ctx, campaignID)
 	if err != nil {
@@ -98,40 +432,235 @@ func (s *CampaignService) IssueCoupon(ctx context.Context, campaignID string) (*
 	}
 
 	// Check if campaign has started
-	if !campaign.HasStarted() {
+	now := s.clock.Now()
+	if !campaign.HasStarted(now) {
 		return nil, ErrCampaignNotStarted
 	}
 
-	// Try to atomically increment the issued count
-	success, err := s.campaignRepo.AtomicIncrementIssued(ctx, campaignID)
+	// Check if campaign has already closed
+	if campaign.HasEnded(now) {
+		return nil, ErrCampaignEnded
+	}
+
+	// Check if issuance has been paused
+	if campaign.Paused {
+		return nil, ErrCampaignPaused
+	}
+
+	// Campaigns with a per-user limit need to know who the coupon is for
+	if campaign.HasUserLimit() && userID == "" {
+		return nil, ErrUserIDRequired
+	}
+
+	// Don't spend a code on a campaign that is already sold out
+	if campaign.IssuedCoupons >= campaign.TotalCoupons {
+		return nil, ErrNoMoreCoupons
+	}
+
+	if campaign.UsesImportedCodes() {
+		return s.issuePooledCoupon(ctx, campaignID, userID)
+	}
+
+	codes, err := s.codeSourceFor(campaign)
 	if err != nil {
 		return nil, err
 	}
-	if !success {
-		return nil, ErrNoMoreCoupons
+
+	// The repository's unique code index is what keeps codes unique across
+	// campaigns. Repeated collisions mean the code space is nearly full.
+	for attempt := 0; attempt < maxIssueAttempts; attempt++ {
+		couponCode, err := s.nextCode(ctx, campaignID, codes)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create coupon
+		coupon := &domain.Coupon{
+			Code:       couponCode,
+			CampaignID: campaignID,
+			UserID:     userID,
+			Status:     domain.CouponStatusIssued,
+			IssuedAt:   s.clock.Now(),
+		}
+
+		// Count and save the coupon as one unit, so a failed save never uses up
+		// one of the campaign's coupons or the user's quota
+		err = s.issuanceRepo.IssueCoupon(ctx, coupon)
+		if errors.Is(err, repository.ErrDuplicateCouponCode) {
+			continue
+		}
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			// Deleted since it was read; don't keep a code source for it
+			s.forgetCodeSource(campaignID)
+		}
+		if err != nil {
+			return nil, translateIssueError(err)
+		}
+
+		return coupon, nil
 	}
 
-	// Generate unique coupon code
-	couponCode := coupongen.GenerateCode(10)
+	return nil, ErrCodeSpaceExhausted
+}
 
-	// Create coupon
+// issuePooledCoupon issues a coupon with the next code from the campaign's code pool
+func (s *CampaignService) issuePooledCoupon(ctx context.Context, campaignID, userID string) (*domain.Coupon, error) {
 	coupon := &domain.Coupon{
-		Code:       couponCode,
 		CampaignID: campaignID,
-		IssuedAt:   time.Now(),
+		UserID:     userID,
+		Status:     domain.CouponStatusIssued,
+		IssuedAt:   s.clock.Now(),
 	}
 
-	// Save coupon
-	err = s.couponRepo.Create(ctx, coupon)
+	// The pool is the campaign's whole stock of codes
+	err := s.issuanceRepo.IssuePooledCoupon(ctx, coupon)
+	if errors.Is(err, repository.ErrCodePoolEmpty) {
+		return nil, ErrNoMoreCoupons
+	}
 	if err != nil {
-		// This is a critical error - we incremented the counter but failed to save the coupon
-		// In a production system, this should be handled with a transaction or compensation logic
-		return nil, err
+		return nil, translateIssueError(err)
+	}
+
+	return coupon, nil
+}
+
+// RedeemCoupon marks a coupon as used. Concurrent attempts to redeem the
+// same code are resolved by the repository, so exactly one of them succeeds.
+// Coupons of a campaign that has ended fail with ErrCouponExpired, and those
+// of a deleted campaign with ErrCampaignNotFound.
+func (s *CampaignService) RedeemCoupon(ctx context.Context, code string) (*domain.Coupon, error) {
+	if code == "" {
+		return nil, ErrInvalidRequest
+	}
+	if malformedCode(code) {
+		return nil, ErrMalformedCouponCode
+	}
+
+	stored, err := s.couponRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, translateRedeemError(err)
+	}
+	campaign, err := s.campaignRepo.Get(ctx, stored.CampaignID)
+	if err != nil {
+		return nil, translateIssueError(err)
+	}
+
+	// A coupon that is no longer issued is refused by the repository below,
+	// which tells why
+	now := s.clock.Now()
+	if stored.Status == domain.CouponStatusIssued && campaign.HasEnded(now) {
+		return nil, ErrCouponExpired
+	}
+
+	coupon, err := s.couponRepo.Redeem(ctx, code, now)
+	if err != nil {
+		return nil, translateRedeemError(err)
+	}
+
+	return coupon, nil
+}
+
+// RevokeCoupon withdraws an issued coupon so it can no longer be redeemed.
+// It fails like RedeemCoupon for a coupon that is no longer issued.
+func (s *CampaignService) RevokeCoupon(ctx context.Context, code string) (*domain.Coupon, error) {
+	if code == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	coupon, err := s.couponRepo.Revoke(ctx, code)
+	if err != nil {
+		return nil, translateRedeemError(err)
 	}
 
 	return coupon, nil
 }
 
+// ValidateCoupon resolves a coupon code to its campaign and reports whether it
+// is still usable. Unusable codes are not an error; the reason is returned instead.
+func (s *CampaignService) ValidateCoupon(ctx context.Context, code string) (*CouponValidation, error) {
+	if code == "" {
+		return nil, ErrInvalidRequest
+	}
+	if malformedCode(code) {
+		return &CouponValidation{Reason: InvalidReasonMalformedCode}, nil
+	}
+
+	// Resolve the code through the repository's code index
+	coupon, err := s.couponRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrCouponNotFound) {
+			return &CouponValidation{Reason: InvalidReasonUnknownCode}, nil
+		}
+		return nil, err
+	}
+
+	validation := &CouponValidation{Coupon: coupon}
+
+	// DeleteCampaign deletes the campaign before its coupons, so a coupon can
+	// outlive its campaign for a while
+	campaign, err := s.campaignRepo.Get(ctx, coupon.CampaignID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCampaignNotFound) {
+			validation.Reason = InvalidReasonCampaignDeleted
+			return validation, nil
+		}
+		return nil, err
+	}
+	validation.Campaign = campaign
+
+	switch coupon.Status {
+	case domain.CouponStatusRedeemed:
+		validation.Reason = InvalidReasonRedeemed
+	case domain.CouponStatusExpired:
+		validation.Reason = InvalidReasonExpired
+	case domain.CouponStatusRevoked:
+		validation.Reason = InvalidReasonRevoked
+	default:
+		// Coupons can only be redeemed while their campaign runs
+		if campaign.HasEnded(s.clock.Now()) {
+			validation.Reason = InvalidReasonExpired
+		}
+	}
+
+	return validation, nil
+}
+
+// translateIssueError maps repository errors raised during issuance to service errors
+func translateIssueError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCampaignNotFound):
+		return ErrCampaignNotFound
+	case errors.Is(err, repository.ErrCampaignNotStarted):
+		return ErrCampaignNotStarted
+	case errors.Is(err, repository.ErrCampaignEnded):
+		return ErrCampaignEnded
+	case errors.Is(err, repository.ErrCampaignPaused):
+		return ErrCampaignPaused
+	case errors.Is(err, repository.ErrLimitReached):
+		return ErrNoMoreCoupons
+	case errors.Is(err, repository.ErrUserLimitReached):
+		return ErrUserLimitReached
+	default:
+		return err
+	}
+}
+
+// translateRedeemError maps repository errors raised during redemption to service errors
+func translateRedeemError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCouponNotFound):
+		return ErrCouponNotFound
+	case errors.Is(err, repository.ErrCouponAlreadyRedeemed):
+		return ErrCouponAlreadyRedeemed
+	case errors.Is(err, repository.ErrCouponExpired):
+		return ErrCouponExpired
+	case errors.Is(err, repository.ErrCouponRevoked):
+		return ErrCouponRevoked
+	default:
+		return err
+	}
+}
+
 // DeleteCampaign deletes a campaign by ID or name
 func (s *CampaignService) DeleteCampaign(ctx context.Context, id, name string) (bool, string, error) {
 	// If both ID and name are empty, return an error
@@ -177,6 +706,12 @@ func (s *CampaignService) DeleteCampaign(ctx context.Context, id, name string) (
 
 	// If a campaign was deleted, also delete its coupons
 	if campaignDeleted && campaignID != "" {
+		s.forgetCodeSource(campaignID)
+
+		if err := s.codePoolRepo.DeleteByCampaignID(ctx, campaignID); err != nil {
+			return true, "Campaign deleted but failed to delete its imported codes", err
+		}
+
 		err = s.couponRepo.DeleteByCampaignID(ctx, campaignID)
 		if err != nil {
 			// This is a partial failure - the campaign was deleted but coupons weren't
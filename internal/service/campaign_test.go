@@ -0,0 +1,903 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock/fakeclock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/memory"
+)
+
+var errCouponStoreDown = errors.New("coupon store unavailable")
+
+// failingCouponRepository is a coupon store whose saves fail while failing is set
+type failingCouponRepository struct {
+	*memory.CouponRepository
+	failing bool
+}
+
+func (r *failingCouponRepository) Create(ctx context.Context, coupon *domain.Coupon) error {
+	if r.failing {
+		return errCouponStoreDown
+	}
+	return r.CouponRepository.Create(ctx, coupon)
+}
+
+func newIssueTestService(t *testing.T, campaign *domain.Campaign) (*CampaignService, *memory.CampaignRepository, *failingCouponRepository) {
+	t.Helper()
+
+	campaigns := memory.NewCampaignRepository(clock.Real{})
+	coupons := &failingCouponRepository{CouponRepository: memory.NewCouponRepository()}
+	if err := campaigns.Create(context.Background(), campaign); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	codePool := memory.NewCodePoolRepository(campaigns, coupons)
+	svc := NewCampaignService(campaigns, coupons, memory.NewIssuanceRepository(campaigns, coupons, codePool), codePool, Options{})
+	return svc, campaigns, coupons
+}
+
+func TestIssueCouponFailedSaveDoesNotConsumeSlot(t *testing.T) {
+	ctx := context.Background()
+	svc, campaigns, coupons := newIssueTestService(t, &domain.Campaign{
+		ID:                "campaign-1",
+		Name:              "failing store",
+		TotalCoupons:      1,
+		MaxCouponsPerUser: 1,
+		StartTime:         time.Now().Add(-time.Minute),
+		CreatedAt:         time.Now().Add(-time.Minute),
+		Version:           1,
+	})
+
+	coupons.failing = true
+	if _, err := svc.IssueCoupon(ctx, "campaign-1", "user-1"); !errors.Is(err, errCouponStoreDown) {
+		t.Fatalf("IssueCoupon with failing store: got error %v, want %v", err, errCouponStoreDown)
+	}
+
+	campaign, err := campaigns.Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 0 {
+		t.Fatalf("issued coupons after failed save = %d, want 0", campaign.IssuedCoupons)
+	}
+
+	// Neither the campaign's only coupon nor the user's quota was used up
+	coupons.failing = false
+	coupon, err := svc.IssueCoupon(ctx, "campaign-1", "user-1")
+	if err != nil {
+		t.Fatalf("IssueCoupon after store recovered: %v", err)
+	}
+
+	campaign, err = campaigns.Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 1 {
+		t.Fatalf("issued coupons = %d, want 1", campaign.IssuedCoupons)
+	}
+
+	if _, err := coupons.GetByCode(ctx, coupon.Code); err != nil {
+		t.Fatalf("issued coupon was not stored: %v", err)
+	}
+}
+
+func TestIssueCouponLimitCheckedBeforeSave(t *testing.T) {
+	ctx := context.Background()
+	svc, _, coupons := newIssueTestService(t, &domain.Campaign{
+		ID:           "campaign-1",
+		Name:         "sold out",
+		TotalCoupons: 1,
+		StartTime:    time.Now().Add(-time.Minute),
+		CreatedAt:    time.Now().Add(-time.Minute),
+		Version:      1,
+	})
+
+	if _, err := svc.IssueCoupon(ctx, "campaign-1", ""); err != nil {
+		t.Fatalf("IssueCoupon: %v", err)
+	}
+	if _, err := svc.IssueCoupon(ctx, "campaign-1", ""); !errors.Is(err, ErrNoMoreCoupons) {
+		t.Fatalf("IssueCoupon on sold out campaign: got error %v, want %v", err, ErrNoMoreCoupons)
+	}
+
+	stored, _, err := coupons.GetByCampaign(ctx, "campaign-1", repository.Page{})
+	if err != nil {
+		t.Fatalf("list coupons: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("stored coupons = %d, want 1", len(stored))
+	}
+}
+
+func TestIssueCouponOpensAtStartTime(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	campaigns := memory.NewCampaignRepository(clk)
+	coupons := memory.NewCouponRepository()
+	codePool := memory.NewCodePoolRepository(campaigns, coupons)
+	svc := NewCampaignService(campaigns, coupons, memory.NewIssuanceRepository(campaigns, coupons, codePool), codePool, Options{Clock: clk})
+
+	if _, err := svc.CreateCampaign(ctx, "too early", 10, 0, clk.Now().Add(-time.Second), time.Time{}, "", nil); !errors.Is(err, ErrPastStartTime) {
+		t.Fatalf("CreateCampaign starting in the past: got error %v, want %v", err, ErrPastStartTime)
+	}
+
+	startTime := clk.Now().Add(time.Hour)
+	campaign, err := svc.CreateCampaign(ctx, "flash sale", 10, 0, startTime, startTime.Add(time.Hour), "", nil)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	if !campaign.CreatedAt.Equal(clk.Now()) {
+		t.Fatalf("campaign created at %v, want %v", campaign.CreatedAt, clk.Now())
+	}
+
+	clk.Advance(time.Hour - time.Nanosecond)
+	if _, err := svc.IssueCoupon(ctx, campaign.ID, "user-1"); !errors.Is(err, ErrCampaignNotStarted) {
+		t.Fatalf("IssueCoupon before the start: got error %v, want %v", err, ErrCampaignNotStarted)
+	}
+
+	clk.Advance(time.Nanosecond)
+	coupon, err := svc.IssueCoupon(ctx, campaign.ID, "user-1")
+	if err != nil {
+		t.Fatalf("IssueCoupon at the start: %v", err)
+	}
+	if !coupon.IssuedAt.Equal(startTime) {
+		t.Fatalf("coupon issued at %v, want %v", coupon.IssuedAt, startTime)
+	}
+
+	clk.Set(startTime.Add(time.Hour))
+	if _, err := svc.IssueCoupon(ctx, campaign.ID, "user-1"); !errors.Is(err, ErrCampaignEnded) {
+		t.Fatalf("IssueCoupon at the end: got error %v, want %v", err, ErrCampaignEnded)
+	}
+}
+
+// newTestService creates a service over empty memory repositories that
+// reads the time from clk
+func newTestService(clk clock.Clock, options Options) (*CampaignService, *memory.CampaignRepository, *memory.CouponRepository) {
+	campaigns := memory.NewCampaignRepository(clk)
+	coupons := memory.NewCouponRepository()
+	codePool := memory.NewCodePoolRepository(campaigns, coupons)
+	options.Clock = clk
+	svc := NewCampaignService(campaigns, coupons, memory.NewIssuanceRepository(campaigns, coupons, codePool), codePool, options)
+	return svc, campaigns, coupons
+}
+
+// mustCreateCampaign creates a campaign through the service or fails the test
+func mustCreateCampaign(t *testing.T, svc *CampaignService, name string, total, perUser int, startTime, endTime time.Time) *domain.Campaign {
+	t.Helper()
+
+	campaign, err := svc.CreateCampaign(context.Background(), name, total, perUser, startTime, endTime, "", nil)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	return campaign
+}
+
+func TestIssueCouponPerUserLimit(t *testing.T) {
+	type issue struct {
+		userID  string
+		wantErr error
+	}
+	tests := []struct {
+		name    string
+		perUser int
+		issues  []issue
+	}{
+		{"limit of one", 1, []issue{{"user-1", nil}, {"user-1", ErrUserLimitReached}, {"user-2", nil}}},
+		{"limit of two", 2, []issue{{"user-1", nil}, {"user-1", nil}, {"user-1", ErrUserLimitReached}, {"user-2", nil}}},
+		{"user required", 1, []issue{{"", ErrUserIDRequired}, {"user-1", nil}}},
+		{"no limit", 0, []issue{{"", nil}, {"", nil}, {"user-1", nil}, {"user-1", nil}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+			svc, _, _ := newTestService(clk, Options{})
+			campaign := mustCreateCampaign(t, svc, "limited", 10, tt.perUser, clk.Now(), time.Time{})
+
+			issued := 0
+			for i, issue := range tt.issues {
+				coupon, err := svc.IssueCoupon(ctx, campaign.ID, issue.userID)
+				if !errors.Is(err, issue.wantErr) {
+					t.Fatalf("issue %d to %q: got error %v, want %v", i, issue.userID, err, issue.wantErr)
+				}
+				if err == nil {
+					issued++
+					if coupon.UserID != issue.userID {
+						t.Fatalf("issue %d: coupon belongs to %q, want %q", i, coupon.UserID, issue.userID)
+					}
+				}
+			}
+
+			// Refused issues do not use up the campaign's coupons
+			stored, _, err := svc.GetCampaign(ctx, campaign.ID, "", false)
+			if err != nil {
+				t.Fatalf("get campaign: %v", err)
+			}
+			if stored.IssuedCoupons != issued {
+				t.Fatalf("issued coupons = %d, want %d", stored.IssuedCoupons, issued)
+			}
+		})
+	}
+}
+
+func TestCreateCampaignEndTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		endTime time.Time
+		wantErr error
+	}{
+		{"no end", time.Time{}, nil},
+		{"after start", start.Add(time.Hour), nil},
+		{"at start", start, ErrInvalidEndTime},
+		{"before start", start.Add(-time.Minute), ErrInvalidEndTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := fakeclock.New(start.Add(-time.Hour))
+			svc, _, _ := newTestService(clk, Options{})
+
+			campaign, err := svc.CreateCampaign(context.Background(), "ending", 10, 0, start, tt.endTime, "", nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("create campaign: got error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !campaign.EndTime.Equal(tt.endTime) {
+				t.Fatalf("end time = %v, want %v", campaign.EndTime, tt.endTime)
+			}
+		})
+	}
+}
+
+func TestRedeemCouponOnceUnderConcurrency(t *testing.T) {
+	const redeemers = 16
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	campaign := mustCreateCampaign(t, svc, "contested", 10, 0, clk.Now(), time.Time{})
+	coupon, err := svc.IssueCoupon(ctx, campaign.ID, "")
+	if err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		start    = make(chan struct{})
+		errs     = make([]error, redeemers)
+		redeemed atomic.Int32
+	)
+	for i := 0; i < redeemers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = svc.RedeemCoupon(ctx, coupon.Code)
+			if errs[i] == nil {
+				redeemed.Add(1)
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if redeemed.Load() != 1 {
+		t.Fatalf("coupon redeemed %d times, want once", redeemed.Load())
+	}
+	for i, err := range errs {
+		if err != nil && !errors.Is(err, ErrCouponAlreadyRedeemed) {
+			t.Fatalf("redeemer %d: got error %v, want %v", i, err, ErrCouponAlreadyRedeemed)
+		}
+	}
+
+	stored, err := svc.couponRepo.GetByCode(ctx, coupon.Code)
+	if err != nil || stored.Status != domain.CouponStatusRedeemed || !stored.RedeemedAt.Equal(clk.Now()) {
+		t.Fatalf("stored coupon after redeeming = %+v, %v", stored, err)
+	}
+}
+
+func TestListCampaignsPageSize(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	const campaigns = MaxPageSize + 100
+	for i := 0; i < campaigns; i++ {
+		clk.Advance(time.Second)
+		mustCreateCampaign(t, svc, fmt.Sprintf("campaign-%03d", i), 10, 0, clk.Now(), time.Time{})
+	}
+
+	tests := []struct {
+		name     string
+		pageSize int
+		want     int
+		wantErr  error
+	}{
+		{"default", 0, DefaultPageSize, nil},
+		{"requested", 10, 10, nil},
+		{"largest", MaxPageSize, MaxPageSize, nil},
+		{"clamped", MaxPageSize + 1, MaxPageSize, nil},
+		{"negative", -1, 0, ErrInvalidRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, nextPageToken, err := svc.ListCampaigns(ctx, repository.CampaignFilter{}, "", tt.pageSize)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("list campaigns: got error %v, want %v", err, tt.wantErr)
+			}
+			if len(page) != tt.want || (err == nil && nextPageToken == "") {
+				t.Fatalf("page size %d: got %d campaigns and next token %q, want %d and a token", tt.pageSize, len(page), nextPageToken, tt.want)
+			}
+		})
+	}
+
+	// Following the tokens lists every campaign once, in creation order
+	var (
+		names     []string
+		pageToken string
+	)
+	for {
+		page, nextPageToken, err := svc.ListCampaigns(ctx, repository.CampaignFilter{}, pageToken, 128)
+		if err != nil {
+			t.Fatalf("list campaigns: %v", err)
+		}
+		for _, campaign := range page {
+			names = append(names, campaign.Name)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+	if len(names) != campaigns {
+		t.Fatalf("listed %d campaigns, want %d", len(names), campaigns)
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("campaign-%03d", i); name != want {
+			t.Fatalf("campaign %d is %q, want %q", i, name, want)
+		}
+	}
+
+	if _, _, err := svc.ListCampaigns(ctx, repository.CampaignFilter{}, "not a token", 10); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("list with a bad token: got error %v, want %v", err, ErrInvalidPageToken)
+	}
+}
+
+func TestListCampaignsFilters(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clk := fakeclock.New(created)
+	svc, _, _ := newTestService(clk, Options{})
+
+	// One campaign of every status, a minute apart
+	mustCreateCampaign(t, svc, "a-active", 10, 0, clk.Now(), time.Time{})
+	clk.Advance(time.Minute)
+	mustCreateCampaign(t, svc, "a-scheduled", 10, 0, clk.Now().Add(time.Hour), time.Time{})
+	clk.Advance(time.Minute)
+	soldOut := mustCreateCampaign(t, svc, "b-sold-out", 1, 0, clk.Now(), time.Time{})
+	if _, err := svc.IssueCoupon(ctx, soldOut.ID, ""); err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+	clk.Advance(time.Minute)
+	mustCreateCampaign(t, svc, "b-ended", 10, 0, clk.Now(), clk.Now().Add(time.Second))
+	clk.Advance(time.Minute)
+
+	tests := []struct {
+		name   string
+		filter repository.CampaignFilter
+		want   []string
+	}{
+		{"none", repository.CampaignFilter{}, []string{"a-active", "a-scheduled", "b-sold-out", "b-ended"}},
+		{"active", repository.CampaignFilter{Status: domain.CampaignStatusActive}, []string{"a-active"}},
+		{"scheduled", repository.CampaignFilter{Status: domain.CampaignStatusScheduled}, []string{"a-scheduled"}},
+		{"sold out", repository.CampaignFilter{Status: domain.CampaignStatusSoldOut}, []string{"b-sold-out"}},
+		{"ended", repository.CampaignFilter{Status: domain.CampaignStatusEnded}, []string{"b-ended"}},
+		{"name prefix", repository.CampaignFilter{NamePrefix: "b-"}, []string{"b-sold-out", "b-ended"}},
+		{"created range", repository.CampaignFilter{CreatedAfter: created.Add(time.Minute), CreatedBefore: created.Add(3 * time.Minute)}, []string{"a-scheduled", "b-sold-out"}},
+		{"status and prefix", repository.CampaignFilter{Status: domain.CampaignStatusActive, NamePrefix: "b-"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Pages of one show that filtered listings page correctly too
+			var (
+				names     []string
+				pageToken string
+			)
+			for {
+				page, nextPageToken, err := svc.ListCampaigns(ctx, tt.filter, pageToken, 1)
+				if err != nil {
+					t.Fatalf("list campaigns: %v", err)
+				}
+				for _, campaign := range page {
+					names = append(names, campaign.Name)
+				}
+				if nextPageToken == "" {
+					break
+				}
+				pageToken = nextPageToken
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Fatalf("listed %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestListCoupons(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	const coupons = MaxPageSize + 100
+	campaign := mustCreateCampaign(t, svc, "listed", coupons, 0, clk.Now(), time.Time{})
+	other := mustCreateCampaign(t, svc, "other", 10, 0, clk.Now(), time.Time{})
+	var issued []string
+	for i := 0; i < coupons; i++ {
+		clk.Advance(time.Second)
+		coupon, err := svc.IssueCoupon(ctx, campaign.ID, "")
+		if err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+		issued = append(issued, coupon.Code)
+		if i%100 == 0 {
+			if _, err := svc.IssueCoupon(ctx, other.ID, ""); err != nil {
+				t.Fatalf("issue coupon: %v", err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		campaignID string
+		pageSize   int
+		want       int
+		wantErr    error
+	}{
+		{"default", campaign.ID, 0, DefaultPageSize, nil},
+		{"requested", campaign.ID, 10, 10, nil},
+		{"largest", campaign.ID, MaxPageSize, MaxPageSize, nil},
+		{"clamped", campaign.ID, MaxPageSize + 1, MaxPageSize, nil},
+		{"negative", campaign.ID, -1, 0, ErrInvalidRequest},
+		{"no campaign", "", 10, 0, ErrInvalidRequest},
+		{"unknown campaign", "missing", 10, 0, ErrCampaignNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, nextPageToken, err := svc.ListCoupons(ctx, tt.campaignID, "", tt.pageSize)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("list coupons: got error %v, want %v", err, tt.wantErr)
+			}
+			if len(page) != tt.want || (err == nil && nextPageToken == "") {
+				t.Fatalf("page size %d: got %d coupons and next token %q, want %d and a token", tt.pageSize, len(page), nextPageToken, tt.want)
+			}
+		})
+	}
+
+	// Following the tokens lists every coupon of the campaign once, in issue order
+	var (
+		listed    []string
+		pageToken string
+	)
+	for {
+		page, nextPageToken, err := svc.ListCoupons(ctx, campaign.ID, pageToken, 128)
+		if err != nil {
+			t.Fatalf("list coupons: %v", err)
+		}
+		for _, coupon := range page {
+			listed = append(listed, coupon.Code)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+	if !slices.Equal(listed, issued) {
+		t.Fatalf("listed %d coupons, want the %d issued in issue order", len(listed), len(issued))
+	}
+
+	if _, _, err := svc.ListCoupons(ctx, campaign.ID, "not a token", 10); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("list with a bad token: got error %v, want %v", err, ErrInvalidPageToken)
+	}
+}
+
+func TestGetCampaignCoupons(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	campaign := mustCreateCampaign(t, svc, "shared", 10, 0, clk.Now(), time.Time{})
+	for _, userID := range []string{"user-1", "user-2", "user-1"} {
+		if _, err := svc.IssueCoupon(ctx, campaign.ID, userID); err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		withCoupons bool
+		want        int
+	}{
+		{"without coupons", "", false, 0},
+		{"all coupons", "", true, 3},
+		{"one user's coupons", "user-1", true, 2},
+		{"unknown user", "user-3", true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, coupons, err := svc.GetCampaign(ctx, campaign.ID, tt.userID, tt.withCoupons)
+			if err != nil || got.ID != campaign.ID {
+				t.Fatalf("get campaign: got %+v, %v", got, err)
+			}
+			if len(coupons) != tt.want || (!tt.withCoupons && coupons != nil) {
+				t.Fatalf("got %d coupons, want %d", len(coupons), tt.want)
+			}
+			for _, coupon := range coupons {
+				if tt.userID != "" && coupon.UserID != tt.userID {
+					t.Fatalf("coupon of %q returned for %q", coupon.UserID, tt.userID)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateCampaignRules(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	name := func(name string) *string { return &name }
+	startTime := func(startTime time.Time) *time.Time { return &startTime }
+	total := func(total int) *int { return &total }
+
+	tests := []struct {
+		name    string
+		started bool // the campaign runs and has issued 3 coupons
+		update  CampaignUpdate
+		wantErr error
+	}{
+		{"rename", false, CampaignUpdate{Name: name("renamed")}, nil},
+		{"rename a running campaign", true, CampaignUpdate{Name: name("renamed")}, nil},
+		{"rename to a taken name", false, CampaignUpdate{Name: name("taken")}, ErrDuplicateCampaign},
+		{"rename to nothing", false, CampaignUpdate{Name: name("")}, ErrInvalidRequest},
+		{"nothing to change", false, CampaignUpdate{}, ErrInvalidRequest},
+		{"move start", false, CampaignUpdate{StartTime: startTime(now.Add(2 * time.Hour))}, nil},
+		{"move start once running", true, CampaignUpdate{StartTime: startTime(now.Add(2 * time.Hour))}, ErrAlreadyStarted},
+		{"move start into the past", false, CampaignUpdate{StartTime: startTime(now.Add(-time.Minute))}, ErrPastStartTime},
+		{"move start to the end", false, CampaignUpdate{StartTime: startTime(now.Add(3 * time.Hour))}, ErrInvalidEndTime},
+		{"raise total", true, CampaignUpdate{TotalCoupons: total(20)}, nil},
+		{"lower total to issued", true, CampaignUpdate{TotalCoupons: total(3)}, nil},
+		{"lower total below issued", true, CampaignUpdate{TotalCoupons: total(2)}, ErrTotalBelowIssued},
+		{"zero total", false, CampaignUpdate{TotalCoupons: total(0)}, ErrInvalidRequest},
+		{"several fields", false, CampaignUpdate{Name: name("renamed"), StartTime: startTime(now.Add(2 * time.Hour)), TotalCoupons: total(5)}, nil},
+		{"one bad field of several", false, CampaignUpdate{Name: name("renamed"), TotalCoupons: total(-1)}, ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			clk := fakeclock.New(now)
+			svc, _, _ := newTestService(clk, Options{})
+
+			mustCreateCampaign(t, svc, "taken", 10, 0, now.Add(time.Hour), time.Time{})
+			start := now.Add(time.Hour)
+			if tt.started {
+				start = now
+			}
+			campaign := mustCreateCampaign(t, svc, "original", 10, 0, start, now.Add(3*time.Hour))
+			if tt.started {
+				for i := 0; i < 3; i++ {
+					if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); err != nil {
+						t.Fatalf("issue coupon: %v", err)
+					}
+				}
+			}
+
+			updated, err := svc.UpdateCampaign(ctx, campaign.ID, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("update campaign: got error %v, want %v", err, tt.wantErr)
+			}
+
+			// Refused updates change nothing; accepted ones change just the given fields
+			want := *campaign
+			if tt.started {
+				want.IssuedCoupons = 3
+			}
+			if err == nil {
+				want.Version++
+				if tt.update.Name != nil {
+					want.Name = *tt.update.Name
+				}
+				if tt.update.StartTime != nil {
+					want.StartTime = *tt.update.StartTime
+				}
+				if tt.update.TotalCoupons != nil {
+					want.TotalCoupons = *tt.update.TotalCoupons
+				}
+				if updated.Name != want.Name || !updated.StartTime.Equal(want.StartTime) || updated.TotalCoupons != want.TotalCoupons || updated.Version != want.Version {
+					t.Fatalf("update returned %+v, want %+v", updated, want)
+				}
+			}
+			stored, _, err := svc.GetCampaign(ctx, campaign.ID, "", false)
+			if err != nil {
+				t.Fatalf("get campaign: %v", err)
+			}
+			if stored.Name != want.Name || !stored.StartTime.Equal(want.StartTime) || stored.TotalCoupons != want.TotalCoupons ||
+				stored.IssuedCoupons != want.IssuedCoupons || stored.Version != want.Version {
+				t.Fatalf("stored campaign %+v, want %+v", stored, want)
+			}
+		})
+	}
+}
+
+func TestUpdateCampaignVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+	campaign := mustCreateCampaign(t, svc, "versioned", 10, 0, clk.Now().Add(time.Hour), time.Time{})
+
+	rename := func(name string, expectedVersion int64) (*domain.Campaign, error) {
+		return svc.UpdateCampaign(ctx, campaign.ID, CampaignUpdate{Name: &name, ExpectedVersion: expectedVersion})
+	}
+
+	// Two editors read version 1; the second one to save loses
+	if updated, err := rename("first", campaign.Version); err != nil || updated.Version != 2 {
+		t.Fatalf("first update: got %+v, %v", updated, err)
+	}
+	if _, err := rename("second", campaign.Version); !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("update based on a stale version: got error %v, want %v", err, ErrVersionConflict)
+	}
+	if updated, err := rename("second", 2); err != nil || updated.Version != 3 {
+		t.Fatalf("update based on the current version: got %+v, %v", updated, err)
+	}
+	// Version 0 skips the check
+	if updated, err := rename("third", 0); err != nil || updated.Version != 4 || updated.Name != "third" {
+		t.Fatalf("update without a version: got %+v, %v", updated, err)
+	}
+
+	if _, err := svc.UpdateCampaign(ctx, "missing", CampaignUpdate{Name: &campaign.Name}); !errors.Is(err, ErrCampaignNotFound) {
+		t.Fatalf("update a missing campaign: got error %v, want %v", err, ErrCampaignNotFound)
+	}
+}
+
+func TestPauseAndResumeCampaign(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+	campaign := mustCreateCampaign(t, svc, "pausable", 10, 0, clk.Now(), time.Time{})
+
+	steps := []struct {
+		name       string
+		setPaused  func(ctx context.Context, id string) (*domain.Campaign, error)
+		wantPaused bool
+		wantIssue  error
+	}{
+		{"pause", svc.PauseCampaign, true, ErrCampaignPaused},
+		{"pause again", svc.PauseCampaign, true, ErrCampaignPaused},
+		{"resume", svc.ResumeCampaign, false, nil},
+		{"resume again", svc.ResumeCampaign, false, nil},
+	}
+
+	issued := 0
+	for _, step := range steps {
+		updated, err := step.setPaused(ctx, campaign.ID)
+		if err != nil || updated.Paused != step.wantPaused {
+			t.Fatalf("%s: got %+v, %v", step.name, updated, err)
+		}
+		if status := updated.Status(clk.Now()); (status == domain.CampaignStatusPaused) != step.wantPaused {
+			t.Fatalf("%s: status %q", step.name, status)
+		}
+
+		if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); !errors.Is(err, step.wantIssue) {
+			t.Fatalf("issue after %s: got error %v, want %v", step.name, err, step.wantIssue)
+		}
+		if step.wantIssue == nil {
+			issued++
+		}
+
+		// Paused issuance does not use up coupons
+		stored, _, err := svc.GetCampaign(ctx, campaign.ID, "", false)
+		if err != nil || stored.IssuedCoupons != issued {
+			t.Fatalf("after %s: got %+v, %v, want %d issued coupons", step.name, stored, err, issued)
+		}
+	}
+
+	for _, setPaused := range []func(ctx context.Context, id string) (*domain.Campaign, error){svc.PauseCampaign, svc.ResumeCampaign} {
+		if _, err := setPaused(ctx, "missing"); !errors.Is(err, ErrCampaignNotFound) {
+			t.Fatalf("pause or resume a missing campaign: got error %v, want %v", err, ErrCampaignNotFound)
+		}
+		if _, err := setPaused(ctx, ""); !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("pause or resume without an ID: got error %v, want %v", err, ErrInvalidRequest)
+		}
+	}
+}
+
+func TestValidateCouponOfDeletedCampaign(t *testing.T) {
+	ctx := context.Background()
+	svc, campaigns, _ := newIssueTestService(t, &domain.Campaign{
+		ID:           "campaign-1",
+		Name:         "deleted",
+		TotalCoupons: 1,
+		StartTime:    time.Now().Add(-time.Minute),
+		CreatedAt:    time.Now().Add(-time.Minute),
+		Version:      1,
+	})
+
+	coupon, err := svc.IssueCoupon(ctx, "campaign-1", "")
+	if err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+
+	// DeleteCampaign removes the campaign first and its coupons after it
+	if deleted, err := campaigns.DeleteByID(ctx, "campaign-1"); !deleted || err != nil {
+		t.Fatalf("delete campaign: got %t, %v", deleted, err)
+	}
+
+	validation, err := svc.ValidateCoupon(ctx, coupon.Code)
+	if err != nil {
+		t.Fatalf("validate coupon: %v", err)
+	}
+	if validation.Reason != InvalidReasonCampaignDeleted || validation.Coupon == nil || validation.Coupon.Code != coupon.Code {
+		t.Fatalf("validation of a deleted campaign's code = %q with coupon %v, want %q", validation.Reason, validation.Coupon, InvalidReasonCampaignDeleted)
+	}
+	if _, err := svc.RedeemCoupon(ctx, coupon.Code); !errors.Is(err, ErrCampaignNotFound) {
+		t.Fatalf("redeem coupon of a deleted campaign: got %v, want %v", err, ErrCampaignNotFound)
+	}
+
+	// Once the coupons are gone too, the code is unknown
+	if err := svc.couponRepo.DeleteByCampaignID(ctx, "campaign-1"); err != nil {
+		t.Fatalf("delete coupons: %v", err)
+	}
+	if validation, err := svc.ValidateCoupon(ctx, coupon.Code); err != nil || validation.Reason != InvalidReasonUnknownCode {
+		t.Fatalf("validation after the coupons were deleted: got %+v, %v", validation, err)
+	}
+}
+
+func TestCouponOfEndedCampaignExpires(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	startTime := clk.Now().Add(time.Minute)
+	campaign := mustCreateCampaign(t, svc, "weekend", 10, 0, startTime, startTime.Add(time.Hour))
+	clk.Set(startTime)
+	usable, err := svc.IssueCoupon(ctx, campaign.ID, "")
+	if err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+	redeemed, err := svc.IssueCoupon(ctx, campaign.ID, "")
+	if err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+	if _, err := svc.RedeemCoupon(ctx, redeemed.Code); err != nil {
+		t.Fatalf("redeem coupon: %v", err)
+	}
+
+	if validation, err := svc.ValidateCoupon(ctx, usable.Code); err != nil || !validation.Valid() {
+		t.Fatalf("validation while the campaign runs: got %+v, %v", validation, err)
+	}
+
+	clk.Set(startTime.Add(time.Hour))
+	if validation, err := svc.ValidateCoupon(ctx, usable.Code); err != nil || validation.Reason != InvalidReasonExpired {
+		t.Fatalf("validation after the end: got %+v, %v, want %q", validation, err, InvalidReasonExpired)
+	}
+	if _, err := svc.RedeemCoupon(ctx, usable.Code); !errors.Is(err, ErrCouponExpired) {
+		t.Fatalf("redeem after the end: got %v, want %v", err, ErrCouponExpired)
+	}
+
+	// A coupon redeemed in time still says so
+	if validation, err := svc.ValidateCoupon(ctx, redeemed.Code); err != nil || validation.Reason != InvalidReasonRedeemed {
+		t.Fatalf("validation of a redeemed coupon after the end: got %+v, %v, want %q", validation, err, InvalidReasonRedeemed)
+	}
+	if _, err := svc.RedeemCoupon(ctx, redeemed.Code); !errors.Is(err, ErrCouponAlreadyRedeemed) {
+		t.Fatalf("redeem a redeemed coupon after the end: got %v, want %v", err, ErrCouponAlreadyRedeemed)
+	}
+}
+
+func TestRevokeCoupon(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{})
+
+	campaign := mustCreateCampaign(t, svc, "revocable", 10, 0, clk.Now(), time.Time{})
+	coupon, err := svc.IssueCoupon(ctx, campaign.ID, "")
+	if err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+
+	revoked, err := svc.RevokeCoupon(ctx, coupon.Code)
+	if err != nil || revoked.Status != domain.CouponStatusRevoked {
+		t.Fatalf("revoke coupon: got %+v, %v", revoked, err)
+	}
+	if validation, err := svc.ValidateCoupon(ctx, coupon.Code); err != nil || validation.Reason != InvalidReasonRevoked {
+		t.Fatalf("validation of a revoked coupon: got %+v, %v, want %q", validation, err, InvalidReasonRevoked)
+	}
+	if _, err := svc.RedeemCoupon(ctx, coupon.Code); !errors.Is(err, ErrCouponRevoked) {
+		t.Fatalf("redeem revoked coupon: got %v, want %v", err, ErrCouponRevoked)
+	}
+	if _, err := svc.RevokeCoupon(ctx, coupon.Code); !errors.Is(err, ErrCouponRevoked) {
+		t.Fatalf("revoke twice: got %v, want %v", err, ErrCouponRevoked)
+	}
+	if _, err := svc.RevokeCoupon(ctx, "missing"); !errors.Is(err, ErrCouponNotFound) {
+		t.Fatalf("revoke unknown code: got %v, want %v", err, ErrCouponNotFound)
+	}
+}
+
+func TestIssueCouponTakesImportedCodes(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, campaigns, _ := newTestService(clk, Options{PregenSize: 10})
+
+	campaign, err := svc.CreateCampaign(ctx, "partner", 10, 0, clk.Now(), time.Time{}, domain.CodeModeImported, nil)
+	if err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+	imported := []string{"PARTNER-2", "PARTNER-1"}
+	if duplicates, err := svc.ImportCodes(ctx, campaign.ID, imported); err != nil || len(duplicates) != 0 {
+		t.Fatalf("import codes: got duplicates %q, %v", duplicates, err)
+	}
+
+	// Pre-generation leaves imported campaigns alone
+	if err := svc.pregenerate(ctx); err != nil {
+		t.Fatalf("pregenerate: %v", err)
+	}
+	if stats := svc.PregenStats(); stats.Pools != 0 {
+		t.Fatalf("pre-generated pools for an imported campaign: %+v", stats)
+	}
+	for _, want := range imported {
+		coupon, err := svc.IssueCoupon(ctx, campaign.ID, "")
+		if err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+		if coupon.Code != want {
+			t.Fatalf("issued code %q, want imported code %q", coupon.Code, want)
+		}
+	}
+	if available, err := svc.AvailableCodes(ctx, campaign.ID); err != nil || available != 0 {
+		t.Fatalf("available codes after issuing all = %d, %v, want 0", available, err)
+	}
+
+	// Nothing is generated once the pool runs out, even with coupons left
+	if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); !errors.Is(err, ErrNoMoreCoupons) {
+		t.Fatalf("issue from an empty pool: got %v, want %v", err, ErrNoMoreCoupons)
+	}
+	stored, err := campaigns.Get(ctx, campaign.ID)
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if stored.IssuedCoupons != len(imported) || stored.CodeSerial != 0 {
+		t.Fatalf("campaign issued %d coupons with code serial %d, want %d and 0", stored.IssuedCoupons, stored.CodeSerial, len(imported))
+	}
+
+	if _, err := svc.ImportCodes(ctx, "missing", imported); !errors.Is(err, ErrCampaignNotFound) {
+		t.Fatalf("import into a missing campaign: got %v, want %v", err, ErrCampaignNotFound)
+	}
+}
+
+func TestRunPregenerationWithoutInterval(t *testing.T) {
+	campaigns := memory.NewCampaignRepository(clock.Real{})
+	coupons := memory.NewCouponRepository()
+	codePool := memory.NewCodePoolRepository(campaigns, coupons)
+	svc := NewCampaignService(campaigns, coupons, memory.NewIssuanceRepository(campaigns, coupons, codePool), codePool, Options{PregenSize: 10})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.RunPregeneration(context.Background(), 0, func(err error) {
+			t.Errorf("pre-generation failed: %v", err)
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunPregeneration with a zero interval did not return")
+	}
+}
@@ -0,0 +1,252 @@
+// internal/service/codes.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
+)
+
+// codeSource produces coupon codes for one campaign. Exactly one of its
+// fields is set, depending on the campaign's code mode.
+type codeSource struct {
+	generator   *coupongen.Generator
+	permutation *coupongen.Permutation
+	keyring     *coupongen.Keyring
+
+	// pregen holds ready codes of a random-mode campaign while
+	// RunPregeneration keeps one for it
+	pregen atomic.Pointer[pregenPool]
+}
+
+// newCodeSource creates the code source matching a campaign's code mode
+func newCodeSource(campaign *domain.Campaign, keys *coupongen.Keyring) (*codeSource, error) {
+	if campaign.UsesSignedCodes() {
+		if keys == nil {
+			return nil, ErrSigningDisabled
+		}
+		return &codeSource{keyring: keys}, nil
+	}
+
+	format, err := codeFormat(campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.UsesPermutedCodes() {
+		permutation, err := coupongen.NewPermutation(format, campaign.CodeKey)
+		if err != nil {
+			return nil, err
+		}
+		return &codeSource{permutation: permutation}, nil
+	}
+
+	generator, err := coupongen.NewGenerator(format)
+	if err != nil {
+		return nil, err
+	}
+	return &codeSource{generator: generator}, nil
+}
+
+// randomCodeSpaceFactor is how many times larger than the total coupons the
+// code space of a random-mode campaign must be, so a draw rarely hits a code
+// that is already taken
+const randomCodeSpaceFactor = 100
+
+// codeFormat returns the format of a campaign's codes
+func codeFormat(campaign *domain.Campaign) (coupongen.Format, error) {
+	if campaign.UsesSignedCodes() {
+		return coupongen.SignedFormat, nil
+	}
+	if campaign.CodeTemplate == nil {
+		return coupongen.DefaultFormat, nil
+	}
+
+	format, err := fromCodeTemplate(campaign.CodeTemplate).Format()
+	if err != nil {
+		return coupongen.Format{}, fmt.Errorf("%w: %v", ErrInvalidCodeFormat, err)
+	}
+	return format, nil
+}
+
+// checkCodeSpace checks that a campaign's code format has enough codes for
+// totalCoupons coupons
+func checkCodeSpace(campaign *domain.Campaign, totalCoupons int) error {
+	// The pool holds as many codes as were imported, whatever the total
+	if campaign.UsesImportedCodes() {
+		return nil
+	}
+	if campaign.UsesSignedCodes() {
+		if totalCoupons > coupongen.MaxSignedSerial+1 {
+			return ErrCodeSpaceTooSmall
+		}
+		return nil
+	}
+
+	format, err := codeFormat(campaign)
+	if err != nil {
+		return err
+	}
+	space, err := format.Space()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCodeFormat, err)
+	}
+
+	needed := uint64(totalCoupons)
+	if !campaign.UsesPermutedCodes() {
+		needed *= randomCodeSpaceFactor
+	}
+	if space < needed {
+		return ErrCodeSpaceTooSmall
+	}
+	return nil
+}
+
+// fromCodeTemplate converts a domain code template to a coupongen template
+func fromCodeTemplate(template *domain.CodeTemplate) coupongen.Template {
+	return coupongen.Template{
+		Prefix:    template.Prefix,
+		Alphabet:  coupongen.Alphabet(template.Alphabet),
+		Groups:    template.Groups,
+		Separator: template.Separator,
+	}
+}
+
+// toCodeTemplate converts a coupongen template to a domain code template
+func toCodeTemplate(template coupongen.Template) *domain.CodeTemplate {
+	return &domain.CodeTemplate{
+		Prefix:    template.Prefix,
+		Alphabet:  string(template.Alphabet),
+		Groups:    template.Groups,
+		Separator: template.Separator,
+	}
+}
+
+// codeSourceFor returns the campaign's code source, creating it on first use
+func (s *CampaignService) codeSourceFor(campaign *domain.Campaign) (*codeSource, error) {
+	s.codeSourcesMutex.Lock()
+	defer s.codeSourcesMutex.Unlock()
+
+	if source, exists := s.codeSources[campaign.ID]; exists {
+		return source, nil
+	}
+
+	source, err := newCodeSource(campaign, s.options.CodeKeys)
+	if err != nil {
+		return nil, err
+	}
+	s.codeSources[campaign.ID] = source
+	return source, nil
+}
+
+// forgetCodeSource drops a deleted campaign's code source and stops its pool
+func (s *CampaignService) forgetCodeSource(campaignID string) {
+	s.codeSourcesMutex.Lock()
+	defer s.codeSourcesMutex.Unlock()
+
+	if source, exists := s.codeSources[campaignID]; exists {
+		if pool := source.pregen.Swap(nil); pool != nil {
+			pool.stop()
+		}
+	}
+	delete(s.codeSources, campaignID)
+}
+
+// nextCode returns the code to try for a campaign's next coupon. Permuted and
+// signed codes use up a serial even if issuance fails afterwards, so a
+// serial, and with it a code, is never handed out twice.
+func (s *CampaignService) nextCode(ctx context.Context, campaignID string, source *codeSource) (string, error) {
+	if source.generator != nil {
+		if pool := source.pregen.Load(); pool != nil {
+			if code, ok := pool.take(); ok {
+				s.pregenCounters.hits.Add(1)
+				return code, nil
+			}
+			s.pregenCounters.misses.Add(1)
+		}
+		return source.generator.Generate()
+	}
+
+	serial, err := s.campaignRepo.NextCodeSerial(ctx, campaignID)
+	if err != nil {
+		return "", translateIssueError(err)
+	}
+
+	var code string
+	if source.keyring != nil {
+		code, err = source.keyring.Sign(campaignID, uint64(serial))
+	} else {
+		code, err = source.permutation.Code(uint64(serial))
+	}
+	if errors.Is(err, coupongen.ErrCodeSpaceExhausted) {
+		return "", ErrCodeSpaceExhausted
+	}
+	return code, err
+}
+
+// malformedCode checks a code's check character offline, so a mistyped code is
+// refused without a repository lookup. Only codes shaped like current codes
+// are judged; older codes without a check character go to the repository.
+func malformedCode(code string) bool {
+	return errors.Is(coupongen.Verify(code), coupongen.ErrCheckMismatch)
+}
+
+const (
+	// MaxImportBatch is the largest number of codes ImportCodes takes at once
+	MaxImportBatch = 10000
+	// maxImportCodeLength bounds an imported code, in bytes
+	maxImportCodeLength = 64
+)
+
+// ImportCodes adds partner-supplied codes to the code pool of a campaign
+// created with imported codes. Codes that are already pooled, taken by a
+// coupon or repeated in the batch are skipped and returned as duplicates;
+// the others are added at once. Importing a batch again is therefore safe.
+func (s *CampaignService) ImportCodes(ctx context.Context, campaignID string, codes []string) ([]string, error) {
+	if campaignID == "" || len(codes) == 0 || len(codes) > MaxImportBatch {
+		return nil, ErrInvalidRequest
+	}
+
+	campaign, err := s.campaignRepo.Get(ctx, campaignID)
+	if err != nil {
+		return nil, ErrCampaignNotFound
+	}
+	if !campaign.UsesImportedCodes() {
+		return nil, ErrNotImportable
+	}
+
+	for _, code := range codes {
+		if err := checkImportCode(code); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.codePoolRepo.AddCodes(ctx, campaignID, codes)
+}
+
+// AvailableCodes returns the number of imported codes a campaign has left to issue
+func (s *CampaignService) AvailableCodes(ctx context.Context, campaignID string) (int, error) {
+	return s.codePoolRepo.CountCodes(ctx, campaignID)
+}
+
+// checkImportCode checks that an imported code can be typed in and redeemed
+func checkImportCode(code string) error {
+	if code == "" || len(code) > maxImportCodeLength || !utf8.ValidString(code) {
+		return fmt.Errorf("%w: %q", ErrInvalidImportCode, code)
+	}
+	if strings.IndexFunc(code, func(r rune) bool { return !unicode.IsPrint(r) || unicode.IsSpace(r) }) >= 0 {
+		return fmt.Errorf("%w: %q contains spaces or control characters", ErrInvalidImportCode, code)
+	}
+	// Redeeming would refuse it as a mistyped generated code
+	if malformedCode(code) {
+		return fmt.Errorf("%w: %q looks like a generated code with a wrong check character", ErrInvalidImportCode, code)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// internal/service/pregen.go
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
+)
+
+// pregenPool holds codes generated ahead of issuance for one random-mode
+// campaign, so IssueCoupon takes a ready code instead of generating one.
+// Permuted and signed codes need a serial from the repository and are not
+// pre-generated, since codes lost on a restart would use up serials.
+type pregenPool struct {
+	codes chan string
+	// wake tells the filler that a code was taken
+	wake chan struct{}
+	// behindSince is when the pool last dropped below full, in Unix
+	// nanoseconds; 0 while it is full
+	behindSince atomic.Int64
+	// clock times the refill lag
+	clock clock.Clock
+	// stop ends the filler
+	stop context.CancelFunc
+}
+
+func newPregenPool(size int, clk clock.Clock, stop context.CancelFunc) *pregenPool {
+	return &pregenPool{
+		codes: make(chan string, size),
+		wake:  make(chan struct{}, 1),
+		clock: clk,
+		stop:  stop,
+	}
+}
+
+// take returns a ready code, or false if the pool is empty
+func (p *pregenPool) take() (string, bool) {
+	select {
+	case code := <-p.codes:
+		p.behindSince.CompareAndSwap(0, p.clock.Now().UnixNano())
+		select {
+		case p.wake <- struct{}{}:
+		default:
+		}
+		return code, true
+	default:
+		return "", false
+	}
+}
+
+// fill keeps the pool full until ctx is done. It is the only sender on
+// codes, so topping up never blocks.
+func (p *pregenPool) fill(ctx context.Context, generator *coupongen.Generator, counters *pregenCounters) {
+	for {
+		for len(p.codes) < cap(p.codes) && ctx.Err() == nil {
+			code, err := generator.Generate()
+			if err != nil {
+				// IssueCoupon generates inline on a miss and reports the error
+				break
+			}
+			p.codes <- code
+		}
+		if len(p.codes) == cap(p.codes) {
+			if since := p.behindSince.Swap(0); since != 0 {
+				counters.recordRefillLag(p.clock.Now().Sub(time.Unix(0, since)))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		}
+	}
+}
+
+// pregenCounters track how well the pools keep up with issuance
+type pregenCounters struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+	// lastRefillLag and maxRefillLag are in nanoseconds
+	lastRefillLag atomic.Int64
+	maxRefillLag  atomic.Int64
+}
+
+func (c *pregenCounters) recordRefillLag(lag time.Duration) {
+	c.lastRefillLag.Store(int64(lag))
+	for {
+		current := c.maxRefillLag.Load()
+		if int64(lag) <= current || c.maxRefillLag.CompareAndSwap(current, int64(lag)) {
+			return
+		}
+	}
+}
+
+// PregenStats reports on the pre-generated code pools
+type PregenStats struct {
+	// Pools is the number of campaigns with a pool
+	Pools int `json:"pools"`
+	// Depth maps campaign IDs to the number of ready codes in their pool
+	Depth map[string]int `json:"depth"`
+	// Hits and Misses count issuances that found a ready code or had to
+	// generate one because the pool was empty
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// LastRefillLag and MaxRefillLag measure how long a pool took to be full
+	// again after a code was taken, in nanoseconds
+	LastRefillLag time.Duration `json:"last_refill_lag_ns"`
+	MaxRefillLag  time.Duration `json:"max_refill_lag_ns"`
+}
+
+// PregenStats returns the current state of the pre-generated code pools
+func (s *CampaignService) PregenStats() PregenStats {
+	stats := PregenStats{
+		Depth:         make(map[string]int),
+		Hits:          s.pregenCounters.hits.Load(),
+		Misses:        s.pregenCounters.misses.Load(),
+		LastRefillLag: time.Duration(s.pregenCounters.lastRefillLag.Load()),
+		MaxRefillLag:  time.Duration(s.pregenCounters.maxRefillLag.Load()),
+	}
+
+	s.codeSourcesMutex.Lock()
+	defer s.codeSourcesMutex.Unlock()
+
+	for campaignID, source := range s.codeSources {
+		if pool := source.pregen.Load(); pool != nil {
+			stats.Depth[campaignID] = len(pool.codes)
+		}
+	}
+	stats.Pools = len(stats.Depth)
+
+	return stats
+}
+
+// RunPregeneration keeps pools of ready codes for random-mode campaigns that
+// start within Options.PregenLead and can still issue coupons, checking the
+// campaigns every interval until ctx is done. It does nothing if
+// Options.PregenSize or interval is not positive. Errors listing campaigns
+// are passed to onError.
+func (s *CampaignService) RunPregeneration(ctx context.Context, interval time.Duration, onError func(error)) {
+	if s.options.PregenSize <= 0 || interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.pregenerate(ctx); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pregenerate starts pools for the campaigns that need one and stops the
+// pools of campaigns that ended, sold out or were deleted
+func (s *CampaignService) pregenerate(ctx context.Context) error {
+	now := s.clock.Now()
+	wanted := make(map[string]bool)
+
+	page := repository.Page{Size: MaxPageSize}
+	for {
+		campaigns, nextPageToken, err := s.campaignRepo.List(ctx, repository.CampaignFilter{}, page)
+		if err != nil {
+			return err
+		}
+
+		for _, campaign := range campaigns {
+			if !s.wantsPregen(campaign, now) {
+				continue
+			}
+			source, err := s.codeSourceFor(campaign)
+			if err != nil || source.generator == nil {
+				continue
+			}
+			wanted[campaign.ID] = true
+
+			if source.pregen.Load() != nil {
+				continue
+			}
+			poolCtx, stop := context.WithCancel(ctx)
+			pool := newPregenPool(min(s.options.PregenSize, campaign.RemainingCoupons()), s.clock, stop)
+			if !source.pregen.CompareAndSwap(nil, pool) {
+				stop()
+				continue
+			}
+			go pool.fill(poolCtx, source.generator, &s.pregenCounters)
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		page.Token = nextPageToken
+	}
+
+	s.codeSourcesMutex.Lock()
+	defer s.codeSourcesMutex.Unlock()
+
+	for campaignID, source := range s.codeSources {
+		if wanted[campaignID] {
+			continue
+		}
+		if pool := source.pregen.Swap(nil); pool != nil {
+			pool.stop()
+		}
+	}
+
+	return nil
+}
+
+// wantsPregen checks if a campaign should have a pool of ready codes
+func (s *CampaignService) wantsPregen(campaign *domain.Campaign, now time.Time) bool {
+	if campaign.UsesPermutedCodes() || campaign.UsesSignedCodes() || campaign.UsesImportedCodes() {
+		return false
+	}
+	if campaign.HasEnded(now) || campaign.RemainingCoupons() <= 0 {
+		return false
+	}
+	return campaign.StartTime.Sub(now) <= s.options.PregenLead
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock/fakeclock"
+)
+
+// waitFor polls cond until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// mustPregenerate runs one pre-generation pass whose fillers stop with ctx
+func mustPregenerate(t *testing.T, ctx context.Context, svc *CampaignService) {
+	t.Helper()
+
+	if err := svc.pregenerate(ctx); err != nil {
+		t.Fatalf("pregenerate: %v", err)
+	}
+}
+
+// waitForDepth waits until a campaign's pool holds depth codes
+func waitForDepth(t *testing.T, svc *CampaignService, campaignID string, depth int) {
+	t.Helper()
+
+	waitFor(t, "pool to fill", func() bool {
+		got, exists := svc.PregenStats().Depth[campaignID]
+		return exists && got == depth
+	})
+}
+
+// restartFiller starts a new filler on a campaign's pool, stopping with ctx
+func restartFiller(ctx context.Context, svc *CampaignService, campaignID string) {
+	svc.codeSourcesMutex.Lock()
+	source := svc.codeSources[campaignID]
+	svc.codeSourcesMutex.Unlock()
+
+	go source.pregen.Load().fill(ctx, source.generator, &svc.pregenCounters)
+}
+
+func TestPregenFillsPoolBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{PregenSize: 5, PregenLead: time.Minute})
+
+	soon := mustCreateCampaign(t, svc, "soon", 100, 0, clk.Now().Add(30*time.Second), time.Time{})
+	small := mustCreateCampaign(t, svc, "small", 3, 0, clk.Now().Add(30*time.Second), time.Time{})
+	later := mustCreateCampaign(t, svc, "later", 100, 0, clk.Now().Add(2*time.Hour), time.Time{})
+
+	mustPregenerate(t, ctx, svc)
+	waitForDepth(t, svc, soon.ID, 5)
+	// A pool never holds more codes than the campaign can issue
+	waitForDepth(t, svc, small.ID, 3)
+	if stats := svc.PregenStats(); stats.Pools != 2 {
+		t.Fatalf("pools before the later campaign's lead = %d, want 2 (%+v)", stats.Pools, stats.Depth)
+	}
+
+	clk.Set(later.StartTime.Add(-time.Minute))
+	mustPregenerate(t, ctx, svc)
+	waitForDepth(t, svc, later.ID, 5)
+	if stats := svc.PregenStats(); stats.Pools != 3 {
+		t.Fatalf("pools within the later campaign's lead = %d, want 3", stats.Pools)
+	}
+}
+
+func TestIssueCouponTakesPregeneratedCodes(t *testing.T) {
+	ctx := context.Background()
+	fillCtx, stopFill := context.WithCancel(ctx)
+	defer stopFill()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{PregenSize: 3, PregenLead: time.Minute})
+
+	campaign := mustCreateCampaign(t, svc, "pregen", 10, 0, clk.Now(), time.Time{})
+	mustPregenerate(t, fillCtx, svc)
+	waitForDepth(t, svc, campaign.ID, 3)
+
+	// Without the filler, every issue empties the pool a bit further
+	stopFill()
+	for depth := 2; depth >= 0; depth-- {
+		if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+		if stats := svc.PregenStats(); stats.Depth[campaign.ID] != depth || stats.Hits != int64(3-depth) {
+			t.Fatalf("after issuing %d coupons: depth %d and %d hits, want %d and %d",
+				3-depth, stats.Depth[campaign.ID], stats.Hits, depth, 3-depth)
+		}
+	}
+
+	// An empty pool falls back to generating the code
+	coupon, err := svc.IssueCoupon(ctx, campaign.ID, "")
+	if err != nil {
+		t.Fatalf("issue coupon from an empty pool: %v", err)
+	}
+	if validation, err := svc.ValidateCoupon(ctx, coupon.Code); err != nil || !validation.Valid() {
+		t.Fatalf("validation of a generated code: got %+v, %v", validation, err)
+	}
+	if stats := svc.PregenStats(); stats.Hits != 3 || stats.Misses != 1 {
+		t.Fatalf("hits and misses = %d and %d, want 3 and 1", stats.Hits, stats.Misses)
+	}
+}
+
+func TestPregenPoolDroppedWithCampaign(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{PregenSize: 2, PregenLead: time.Minute})
+
+	ending := mustCreateCampaign(t, svc, "ending", 10, 0, clk.Now(), clk.Now().Add(time.Hour))
+	deleted := mustCreateCampaign(t, svc, "deleted", 10, 0, clk.Now(), time.Time{})
+	soldOut := mustCreateCampaign(t, svc, "sold out", 1, 0, clk.Now(), time.Time{})
+	kept := mustCreateCampaign(t, svc, "kept", 10, 0, clk.Now(), time.Time{})
+	mustPregenerate(t, ctx, svc)
+	if stats := svc.PregenStats(); stats.Pools != 4 {
+		t.Fatalf("pools = %d, want 4", stats.Pools)
+	}
+
+	// Deleting drops the pool right away
+	if deleted, _, err := svc.DeleteCampaign(ctx, deleted.ID, ""); !deleted || err != nil {
+		t.Fatalf("delete campaign: got %t, %v", deleted, err)
+	}
+	if _, exists := svc.PregenStats().Depth[deleted.ID]; exists {
+		t.Fatalf("deleted campaign kept its pool")
+	}
+
+	// Ended and sold out campaigns lose theirs on the next pass
+	if _, err := svc.IssueCoupon(ctx, soldOut.ID, ""); err != nil {
+		t.Fatalf("issue coupon: %v", err)
+	}
+	clk.Set(ending.EndTime)
+	mustPregenerate(t, ctx, svc)
+
+	stats := svc.PregenStats()
+	if _, exists := stats.Depth[kept.ID]; !exists || stats.Pools != 1 {
+		t.Fatalf("pools after the pass = %v, want only %s", stats.Depth, kept.ID)
+	}
+}
+
+func TestPregenStatsReportRefillLag(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	svc, _, _ := newTestService(clk, Options{PregenSize: 2, PregenLead: time.Minute})
+
+	campaign := mustCreateCampaign(t, svc, "lagging", 10, 0, clk.Now(), time.Time{})
+	fillCtx, stopFill := context.WithCancel(ctx)
+	mustPregenerate(t, fillCtx, svc)
+	waitForDepth(t, svc, campaign.ID, 2)
+	stopFill()
+
+	// Filling the pool the first time is not a refill
+	if stats := svc.PregenStats(); stats.LastRefillLag != 0 || stats.MaxRefillLag != 0 {
+		t.Fatalf("refill lag after the first fill = %v, max %v, want 0", stats.LastRefillLag, stats.MaxRefillLag)
+	}
+
+	// takeAndRefill takes a code, lets lag pass before a filler tops the
+	// pool up again and waits for the refill to be recorded
+	takeAndRefill := func(lag time.Duration) PregenStats {
+		t.Helper()
+
+		if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+		if depth := svc.PregenStats().Depth[campaign.ID]; depth != 1 {
+			t.Fatalf("depth after taking a code = %d, want 1", depth)
+		}
+		clk.Advance(lag)
+
+		fillCtx, stopFill := context.WithCancel(ctx)
+		defer stopFill()
+		restartFiller(fillCtx, svc, campaign.ID)
+		waitFor(t, "refill", func() bool {
+			return svc.PregenStats().LastRefillLag == lag
+		})
+		return svc.PregenStats()
+	}
+
+	stats := takeAndRefill(3 * time.Second)
+	if stats.MaxRefillLag != 3*time.Second || stats.Depth[campaign.ID] != 2 {
+		t.Fatalf("after a 3s refill: max lag %v, depth %d, want 3s and 2", stats.MaxRefillLag, stats.Depth[campaign.ID])
+	}
+	stats = takeAndRefill(time.Second)
+	if stats.MaxRefillLag != 3*time.Second {
+		t.Fatalf("max lag after a shorter refill = %v, want 3s", stats.MaxRefillLag)
+	}
+	if stats.Hits != 2 || stats.Misses != 0 {
+		t.Fatalf("hits and misses = %d and %d, want 2 and 0", stats.Hits, stats.Misses)
+	}
+
+	// Issuing more than a stopped pool holds counts misses
+	for i := 0; i < 3; i++ {
+		if _, err := svc.IssueCoupon(ctx, campaign.ID, ""); err != nil {
+			t.Fatalf("issue coupon: %v", err)
+		}
+	}
+	if stats := svc.PregenStats(); stats.Hits != 4 || stats.Misses != 1 || stats.Depth[campaign.ID] != 0 {
+		t.Fatalf("hits %d, misses %d, depth %d; want 4, 1 and 0", stats.Hits, stats.Misses, stats.Depth[campaign.ID])
+	}
+}
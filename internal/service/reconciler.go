@@ -0,0 +1,258 @@
+// internal/service/reconciler.go
+package service
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
+)
+
+// ErrRepairUnsupported is returned when repair is requested from a reconciler
+// whose backend does not commit issued counters and coupons together
+var ErrRepairUnsupported = errors.New("counter repair is not supported by this storage backend")
+
+// CounterDrift describes a campaign whose issued counters disagree with its stored coupons
+type CounterDrift struct {
+	CampaignID   string
+	CampaignName string
+	// IssuedCoupons is the campaign's issued counter
+	IssuedCoupons int
+	// StoredCoupons is the number of coupons actually stored for the campaign
+	StoredCoupons int
+	// UserDrift maps user IDs to their counter minus their stored coupons.
+	// Users whose counts agree are left out.
+	UserDrift map[string]int
+	// Repaired is set when the leaked slots were released during this pass
+	Repaired bool
+}
+
+// Leaked returns the number of counted slots without a stored coupon.
+// A negative value means there are coupons the counter does not account for.
+func (d *CounterDrift) Leaked() int {
+	return d.IssuedCoupons - d.StoredCoupons
+}
+
+// sameDrift checks if two passes found the same amount of drift
+func (d *CounterDrift) sameDrift(other *CounterDrift) bool {
+	return d.Leaked() == other.Leaked() && maps.Equal(d.UserDrift, other.UserDrift)
+}
+
+// ReconcileReport is the outcome of one reconciliation pass
+type ReconcileReport struct {
+	CheckedAt        time.Time
+	CampaignsChecked int
+	// CampaignsSkipped counts campaigns that changed while being checked;
+	// they are checked again on the next pass
+	CampaignsSkipped int
+	Drifts           []*CounterDrift
+}
+
+// ReconcilerOptions configures a Reconciler
+type ReconcilerOptions struct {
+	// AtomicIssuance must only be set for backends whose issuance changes the
+	// issued counters and stores the coupon in one commit, such as the file
+	// and sqlite backends. Only then can drift be repaired: the memory backend
+	// counts a slot before saving its coupon, so an issuance in flight looks
+	// just like a leaked slot, and releasing it would over-issue.
+	AtomicIssuance bool
+
+	// Clock stamps the reports; nil means the system clock
+	Clock clock.Clock
+}
+
+// Reconciler finds campaigns whose issued counters have drifted from the
+// coupons actually stored and can release the slots that leaked
+type Reconciler struct {
+	campaignRepo repository.CampaignRepository
+	couponRepo   repository.CouponRepository
+	options      ReconcilerOptions
+	clock        clock.Clock
+
+	// mutex serializes passes and guards previous
+	mutex sync.Mutex
+	// previous holds the unrepaired drift found by the last pass, by campaign ID
+	previous map[string]*CounterDrift
+}
+
+// NewReconciler creates a new reconciler
+func NewReconciler(campaignRepo repository.CampaignRepository, couponRepo repository.CouponRepository, options ReconcilerOptions) *Reconciler {
+	return &Reconciler{
+		campaignRepo: campaignRepo,
+		couponRepo:   couponRepo,
+		options:      options,
+		clock:        clock.OrReal(options.Clock),
+		previous:     make(map[string]*CounterDrift),
+	}
+}
+
+// Reconcile compares every campaign's counters with its stored coupons.
+// With repair set, leaked slots are given back to the campaign and its users,
+// but only for drift that the previous pass found with the same values.
+// Coupons the counters do not account for are only reported. Repair fails
+// with ErrRepairUnsupported unless ReconcilerOptions.AtomicIssuance is set.
+func (r *Reconciler) Reconcile(ctx context.Context, repair bool) (*ReconcileReport, error) {
+	if repair && !r.options.AtomicIssuance {
+		return nil, ErrRepairUnsupported
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	report := &ReconcileReport{CheckedAt: r.clock.Now()}
+	current := make(map[string]*CounterDrift)
+
+	page := repository.Page{Size: MaxPageSize}
+	for {
+		campaigns, nextPageToken, err := r.campaignRepo.List(ctx, repository.CampaignFilter{}, page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, campaign := range campaigns {
+			drift, stable, err := r.checkCampaign(ctx, campaign)
+			if err != nil {
+				return nil, err
+			}
+			if !stable {
+				report.CampaignsSkipped++
+				continue
+			}
+			report.CampaignsChecked++
+			if drift == nil {
+				continue
+			}
+
+			if previous, seen := r.previous[drift.CampaignID]; repair && seen && drift.sameDrift(previous) {
+				if err := r.release(ctx, drift); err != nil {
+					return nil, err
+				}
+			}
+			if !drift.Repaired {
+				current[drift.CampaignID] = drift
+			}
+			report.Drifts = append(report.Drifts, drift)
+		}
+
+		if nextPageToken == "" {
+			break
+		}
+		page.Token = nextPageToken
+	}
+
+	r.previous = current
+	return report, nil
+}
+
+// checkCampaign compares one campaign's counters with its stored coupons. It
+// returns nil when they agree, and stable is false when the campaign changed
+// or disappeared during the check, making the comparison meaningless.
+func (r *Reconciler) checkCampaign(ctx context.Context, campaign *domain.Campaign) (*CounterDrift, bool, error) {
+	// Counters are read before the coupons, and an unchanged counter
+	// afterwards means no issuance committed in between. That only makes the
+	// reads agree where issuance commits counter and coupon at once; elsewhere
+	// a slot counted but not yet saved shows up as drift, which is why
+	// Reconcile only repairs with ReconcilerOptions.AtomicIssuance.
+	before, err := r.campaignRepo.Get(ctx, campaign.ID)
+	if errors.Is(err, repository.ErrCampaignNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	issued := before.IssuedCoupons
+
+	issuedByUser, err := r.campaignRepo.IssuedByUser(ctx, campaign.ID)
+	if errors.Is(err, repository.ErrCampaignNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	stored, storedByUser, err := r.couponRepo.CountByCampaign(ctx, campaign.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	after, err := r.campaignRepo.Get(ctx, campaign.ID)
+	if errors.Is(err, repository.ErrCampaignNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if after.IssuedCoupons != issued {
+		return nil, false, nil
+	}
+
+	userDrift := make(map[string]int)
+	for userID, count := range issuedByUser {
+		if diff := count - storedByUser[userID]; diff != 0 {
+			userDrift[userID] = diff
+		}
+	}
+	for userID, count := range storedByUser {
+		if _, counted := issuedByUser[userID]; !counted {
+			userDrift[userID] = -count
+		}
+	}
+
+	if issued == stored && len(userDrift) == 0 {
+		return nil, true, nil
+	}
+
+	return &CounterDrift{
+		CampaignID:    campaign.ID,
+		CampaignName:  campaign.Name,
+		IssuedCoupons: issued,
+		StoredCoupons: stored,
+		UserDrift:     userDrift,
+	}, true, nil
+}
+
+// release gives back the campaign's leaked slots and marks the drift repaired
+func (r *Reconciler) release(ctx context.Context, drift *CounterDrift) error {
+	total := max(drift.Leaked(), 0)
+	byUser := make(map[string]int)
+	for userID, diff := range drift.UserDrift {
+		if diff > 0 {
+			byUser[userID] = diff
+		}
+	}
+	if total == 0 && len(byUser) == 0 {
+		return nil
+	}
+
+	err := r.campaignRepo.ReleaseIssued(ctx, drift.CampaignID, total, byUser)
+	if errors.Is(err, repository.ErrCampaignNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	drift.Repaired = true
+	return nil
+}
+
+// Run reconciles every interval until ctx is done, passing each report or
+// error to onReport
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration, repair bool, onReport func(*ReconcileReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			onReport(r.Reconcile(ctx, repair))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rpranjan11/coupon-issuance-system/internal/clock/fakeclock"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository/memory"
+)
+
+func TestReconcileDoesNotRepairNonAtomicIssuance(t *testing.T) {
+	ctx := context.Background()
+	clk := fakeclock.New(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	campaigns := memory.NewCampaignRepository(clk)
+	coupons := memory.NewCouponRepository()
+	if err := campaigns.Create(ctx, &domain.Campaign{
+		ID:           "campaign-1",
+		Name:         "in flight",
+		TotalCoupons: 10,
+		StartTime:    clk.Now().Add(-time.Minute),
+		CreatedAt:    clk.Now().Add(-time.Minute),
+		Version:      1,
+	}); err != nil {
+		t.Fatalf("create campaign: %v", err)
+	}
+
+	// A counted slot whose coupon is still being saved
+	if _, err := campaigns.AtomicIncrementIssued(ctx, "campaign-1", ""); err != nil {
+		t.Fatalf("increment issued: %v", err)
+	}
+
+	reconciler := NewReconciler(campaigns, coupons, ReconcilerOptions{Clock: clk})
+	for pass := 0; pass < 2; pass++ {
+		if _, err := reconciler.Reconcile(ctx, true); !errors.Is(err, ErrRepairUnsupported) {
+			t.Fatalf("repair pass %d: got error %v, want %v", pass, err, ErrRepairUnsupported)
+		}
+	}
+
+	report, err := reconciler.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if !report.CheckedAt.Equal(clk.Now()) {
+		t.Fatalf("report checked at %v, want %v", report.CheckedAt, clk.Now())
+	}
+	if len(report.Drifts) != 1 || report.Drifts[0].Leaked() != 1 || report.Drifts[0].Repaired {
+		t.Fatalf("drifts = %+v, want one unrepaired leak of 1", report.Drifts)
+	}
+
+	campaign, err := campaigns.Get(ctx, "campaign-1")
+	if err != nil {
+		t.Fatalf("get campaign: %v", err)
+	}
+	if campaign.IssuedCoupons != 1 {
+		t.Fatalf("issued coupons = %d, want the in-flight slot kept", campaign.IssuedCoupons)
+	}
+}
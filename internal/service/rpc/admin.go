@@ -0,0 +1,100 @@
+// internal/service/rpc/admin.go
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bufbuild/connect-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	coupon "github.com/rpranjan11/coupon-issuance-system/api/coupon"
+	"github.com/rpranjan11/coupon-issuance-system/internal/service"
+)
+
+// AdminServiceServer implements the AdminService Connect API
+type AdminServiceServer struct {
+	campaignService *service.CampaignService
+	reconciler      *service.Reconciler
+}
+
+// NewAdminServiceServer creates a new AdminServiceServer
+func NewAdminServiceServer(campaignService *service.CampaignService, reconciler *service.Reconciler) *AdminServiceServer {
+	return &AdminServiceServer{
+		campaignService: campaignService,
+		reconciler:      reconciler,
+	}
+}
+
+// ReconcileCounters runs a reconciliation pass and reports the drift it found
+func (s *AdminServiceServer) ReconcileCounters(
+	ctx context.Context,
+	req *connect.Request[coupon.ReconcileCountersRequest],
+) (*connect.Response[coupon.ReconcileCountersResponse], error) {
+	report, err := s.reconciler.Reconcile(ctx, req.Msg.Repair)
+	if errors.Is(err, service.ErrRepairUnsupported) {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	drifts := make([]*coupon.CounterDrift, 0, len(report.Drifts))
+	for _, drift := range report.Drifts {
+		drifts = append(drifts, toCounterDriftProto(drift))
+	}
+
+	return connect.NewResponse(&coupon.ReconcileCountersResponse{
+		CheckedAt:        timestamppb.New(report.CheckedAt),
+		CampaignsChecked: int32(report.CampaignsChecked),
+		CampaignsSkipped: int32(report.CampaignsSkipped),
+		Drifts:           drifts,
+	}), nil
+}
+
+// RevokeCoupon withdraws an issued coupon so it can no longer be redeemed
+func (s *AdminServiceServer) RevokeCoupon(
+	ctx context.Context,
+	req *connect.Request[coupon.RevokeCouponRequest],
+) (*connect.Response[coupon.RevokeCouponResponse], error) {
+	// Validate request
+	if req.Msg.Code == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("coupon code is required"))
+	}
+
+	// Revoke coupon
+	c, err := s.campaignService.RevokeCoupon(ctx, req.Msg.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCouponNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, service.ErrCouponAlreadyRedeemed),
+			errors.Is(err, service.ErrCouponExpired),
+			errors.Is(err, service.ErrCouponRevoked):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		default:
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	return connect.NewResponse(&coupon.RevokeCouponResponse{
+		Coupon: toCouponProto(c),
+	}), nil
+}
+
+// toCounterDriftProto converts a service counter drift to its proto model
+func toCounterDriftProto(drift *service.CounterDrift) *coupon.CounterDrift {
+	userDrift := make(map[string]int32, len(drift.UserDrift))
+	for userID, diff := range drift.UserDrift {
+		userDrift[userID] = int32(diff)
+	}
+
+	return &coupon.CounterDrift{
+		CampaignId:    drift.CampaignID,
+		CampaignName:  drift.CampaignName,
+		IssuedCoupons: int32(drift.IssuedCoupons),
+		StoredCoupons: int32(drift.StoredCoupons),
+		UserDrift:     userDrift,
+		Repaired:      drift.Repaired,
+	}
+}
@@ -4,12 +4,17 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/bufbuild/connect-go"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	coupon "github.com/rpranjan11/coupon-issuance-system/api/coupon"
+	"github.com/rpranjan11/coupon-issuance-system/internal/domain"
+	"github.com/rpranjan11/coupon-issuance-system/internal/repository"
 	"github.com/rpranjan11/coupon-issuance-system/internal/service"
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
 )
 
 // CouponServiceServer implements the CouponService Connect API
@@ -30,15 +35,23 @@ func (s *CouponServiceServer) CreateCampaign(
 	req *connect.Request[coupon.CreateCampaignRequest],
 ) (*connect.Response[coupon.CreateCampaignResponse], error) {
 	// Validate request
-	if req.Msg.Name == "" || req.Msg.TotalCoupons <= 0 || req.Msg.StartTime == nil {
+	if req.Msg.Name == "" || req.Msg.TotalCoupons <= 0 || req.Msg.MaxCouponsPerUser < 0 || req.Msg.StartTime == nil ||
+		coupon.CodeMode_name[int32(req.Msg.CodeMode)] == "" ||
+		(req.Msg.CodeTemplate != nil && coupon.CodeAlphabet_name[int32(req.Msg.CodeTemplate.Alphabet)] == "") {
 		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("invalid request parameters"))
 	}
 
-	// Extract start time
+	// Extract start and optional end time
 	startTime := req.Msg.StartTime.AsTime()
+	var endTime time.Time
+	if req.Msg.EndTime != nil {
+		endTime = req.Msg.EndTime.AsTime()
+	}
 
 	// Create campaign
-	campaign, err := s.campaignService.CreateCampaign(ctx, req.Msg.Name, int(req.Msg.TotalCoupons), startTime)
+	campaign, err := s.campaignService.CreateCampaign(ctx, req.Msg.Name, int(req.Msg.TotalCoupons),
+		int(req.Msg.MaxCouponsPerUser), startTime, endTime, fromCodeModeProto(req.Msg.CodeMode),
+		fromCodeTemplateProto(req.Msg.CodeTemplate))
 	if err != nil {
 		if errors.Is(err, service.ErrDuplicateCampaign) {
 			return nil, connect.NewError(connect.CodeAlreadyExists,
@@ -46,26 +59,22 @@ func (s *CouponServiceServer) CreateCampaign(
 		} else if errors.Is(err, service.ErrPastStartTime) {
 			return nil, connect.NewError(connect.CodeInvalidArgument,
 				errors.New("campaign start time cannot be in the past"))
+		} else if errors.Is(err, service.ErrInvalidEndTime) || errors.Is(err, service.ErrInvalidRequest) ||
+			errors.Is(err, service.ErrInvalidCodeFormat) || errors.Is(err, service.ErrCodeSpaceTooSmall) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		} else if errors.Is(err, service.ErrSigningDisabled) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
 		}
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	// Convert domain model to proto model
-	campaignProto := &coupon.Campaign{
-		Id:            campaign.ID,
-		Name:          campaign.Name,
-		TotalCoupons:  int32(campaign.TotalCoupons),
-		IssuedCoupons: int32(campaign.IssuedCoupons),
-		StartTime:     timestamppb.New(campaign.StartTime),
-		CreatedAt:     timestamppb.New(campaign.CreatedAt),
-	}
-
 	return connect.NewResponse(&coupon.CreateCampaignResponse{
-		Campaign: campaignProto,
+		Campaign: toCampaignProto(campaign, s.campaignService.Now()),
 	}), nil
 }
 
-// GetCampaign gets campaign information including all issued coupon codes
+// GetCampaign gets campaign information including all issued coupon codes,
+// unless the request asks to omit them
 func (s *CouponServiceServer) GetCampaign(
 	ctx context.Context,
 	req *connect.Request[coupon.GetCampaignRequest],
@@ -76,7 +85,7 @@ func (s *CouponServiceServer) GetCampaign(
 	}
 
 	// Get campaign and coupons
-	campaign, coupons, err := s.campaignService.GetCampaign(ctx, req.Msg.CampaignId)
+	campaign, coupons, err := s.campaignService.GetCampaign(ctx, req.Msg.CampaignId, req.Msg.UserId, !req.Msg.OmitCoupons)
 	if err != nil {
 		if errors.Is(err, service.ErrCampaignNotFound) {
 			return nil, connect.NewError(connect.CodeNotFound, err)
@@ -85,26 +94,13 @@ func (s *CouponServiceServer) GetCampaign(
 	}
 
 	// Convert domain models to proto models
-	campaignProto := &coupon.Campaign{
-		Id:            campaign.ID,
-		Name:          campaign.Name,
-		TotalCoupons:  int32(campaign.TotalCoupons),
-		IssuedCoupons: int32(campaign.IssuedCoupons),
-		StartTime:     timestamppb.New(campaign.StartTime),
-		CreatedAt:     timestamppb.New(campaign.CreatedAt),
-	}
-
 	couponProtos := make([]*coupon.Coupon, len(coupons))
 	for i, c := range coupons {
-		couponProtos[i] = &coupon.Coupon{
-			Code:       c.Code,
-			CampaignId: c.CampaignID,
-			IssuedAt:   timestamppb.New(c.IssuedAt),
-		}
+		couponProtos[i] = toCouponProto(c)
 	}
 
 	return connect.NewResponse(&coupon.GetCampaignResponse{
-		Campaign: campaignProto,
+		Campaign: toCampaignProto(campaign, s.campaignService.Now()),
 		Coupons:  couponProtos,
 	}), nil
 }
@@ -120,7 +116,7 @@ func (s *CouponServiceServer) IssueCoupon(
 	}
 
 	// Issue coupon
-	c, err := s.campaignService.IssueCoupon(ctx, req.Msg.CampaignId)
+	c, err := s.campaignService.IssueCoupon(ctx, req.Msg.CampaignId, req.Msg.UserId)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrCampaignNotFound):
@@ -130,26 +126,40 @@ func (s *CouponServiceServer) IssueCoupon(
 				Success: false,
 				Error:   "campaign has not started yet",
 			}), nil
+		case errors.Is(err, service.ErrCampaignEnded):
+			return connect.NewResponse(&coupon.IssueCouponResponse{
+				Success: false,
+				Error:   "campaign has ended",
+			}), nil
+		case errors.Is(err, service.ErrCampaignPaused):
+			return connect.NewResponse(&coupon.IssueCouponResponse{
+				Success: false,
+				Error:   "campaign is paused",
+			}), nil
 		case errors.Is(err, service.ErrNoMoreCoupons):
 			return connect.NewResponse(&coupon.IssueCouponResponse{
 				Success: false,
 				Error:   "no more coupons available",
 			}), nil
+		case errors.Is(err, service.ErrUserLimitReached):
+			return connect.NewResponse(&coupon.IssueCouponResponse{
+				Success: false,
+				Error:   "user has reached the coupon limit for this campaign",
+			}), nil
+		case errors.Is(err, service.ErrUserIDRequired):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, service.ErrCodeSpaceExhausted):
+			return nil, connect.NewError(connect.CodeResourceExhausted, err)
+		case errors.Is(err, service.ErrSigningDisabled):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
 		default:
 			return nil, connect.NewError(connect.CodeInternal, err)
 		}
 	}
 
-	// Convert domain model to proto model
-	couponProto := &coupon.Coupon{
-		Code:       c.Code,
-		CampaignId: c.CampaignID,
-		IssuedAt:   timestamppb.New(c.IssuedAt),
-	}
-
 	return connect.NewResponse(&coupon.IssueCouponResponse{
 		Success: true,
-		Coupon:  couponProto,
+		Coupon:  toCouponProto(c),
 	}), nil
 }
 
@@ -182,3 +192,512 @@ func (s *CouponServiceServer) DeleteCampaign(
 		Message: message,
 	}), nil
 }
+
+// RedeemCoupon marks an issued coupon as used
+func (s *CouponServiceServer) RedeemCoupon(
+	ctx context.Context,
+	req *connect.Request[coupon.RedeemCouponRequest],
+) (*connect.Response[coupon.RedeemCouponResponse], error) {
+	// Validate request
+	if req.Msg.Code == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("coupon code is required"))
+	}
+
+	// Redeem coupon
+	c, err := s.campaignService.RedeemCoupon(ctx, req.Msg.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCouponNotFound),
+			errors.Is(err, service.ErrCampaignNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, service.ErrMalformedCouponCode):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, service.ErrCouponAlreadyRedeemed),
+			errors.Is(err, service.ErrCouponExpired),
+			errors.Is(err, service.ErrCouponRevoked):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		default:
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	return connect.NewResponse(&coupon.RedeemCouponResponse{
+		Coupon: toCouponProto(c),
+	}), nil
+}
+
+// ValidateCoupon checks whether a coupon code is usable without redeeming it
+func (s *CouponServiceServer) ValidateCoupon(
+	ctx context.Context,
+	req *connect.Request[coupon.ValidateCouponRequest],
+) (*connect.Response[coupon.ValidateCouponResponse], error) {
+	// Validate request
+	if req.Msg.Code == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("coupon code is required"))
+	}
+
+	// Validate coupon
+	validation, err := s.campaignService.ValidateCoupon(ctx, req.Msg.Code)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// Convert result to proto model
+	resp := &coupon.ValidateCouponResponse{
+		Valid:  validation.Valid(),
+		Reason: toInvalidReasonProto(validation.Reason),
+	}
+	if validation.Coupon != nil {
+		resp.Coupon = toCouponProto(validation.Coupon)
+	}
+	if validation.Campaign != nil {
+		resp.Campaign = toCampaignProto(validation.Campaign, s.campaignService.Now())
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// ListCampaigns lists campaigns page by page, optionally filtered
+func (s *CouponServiceServer) ListCampaigns(
+	ctx context.Context,
+	req *connect.Request[coupon.ListCampaignsRequest],
+) (*connect.Response[coupon.ListCampaignsResponse], error) {
+	// Validate request
+	if req.Msg.PageSize < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("page size cannot be negative"))
+	}
+
+	// Build filter from request
+	filter := repository.CampaignFilter{
+		Status:     fromCampaignStatusProto(req.Msg.Status),
+		NamePrefix: req.Msg.NamePrefix,
+	}
+	if req.Msg.CreatedAfter != nil {
+		filter.CreatedAfter = req.Msg.CreatedAfter.AsTime()
+	}
+	if req.Msg.CreatedBefore != nil {
+		filter.CreatedBefore = req.Msg.CreatedBefore.AsTime()
+	}
+
+	// List campaigns
+	campaigns, nextPageToken, err := s.campaignService.ListCampaigns(ctx, filter, req.Msg.PageToken, int(req.Msg.PageSize))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPageToken) || errors.Is(err, service.ErrInvalidRequest) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	// Convert domain models to proto models
+	now := s.campaignService.Now()
+	campaignProtos := make([]*coupon.Campaign, len(campaigns))
+	for i, c := range campaigns {
+		campaignProtos[i] = toCampaignProto(c, now)
+	}
+
+	return connect.NewResponse(&coupon.ListCampaignsResponse{
+		Campaigns:     campaignProtos,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// ListCoupons lists the coupons issued for a campaign page by page, in issue order
+func (s *CouponServiceServer) ListCoupons(
+	ctx context.Context,
+	req *connect.Request[coupon.ListCouponsRequest],
+) (*connect.Response[coupon.ListCouponsResponse], error) {
+	// Validate request
+	if req.Msg.CampaignId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+	}
+	if req.Msg.PageSize < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("page size cannot be negative"))
+	}
+
+	// List coupons
+	coupons, nextPageToken, err := s.campaignService.ListCoupons(ctx, req.Msg.CampaignId, req.Msg.PageToken, int(req.Msg.PageSize))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCampaignNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, service.ErrInvalidPageToken), errors.Is(err, service.ErrInvalidRequest):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		default:
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	// Convert domain models to proto models
+	couponProtos := make([]*coupon.Coupon, len(coupons))
+	for i, c := range coupons {
+		couponProtos[i] = toCouponProto(c)
+	}
+
+	return connect.NewResponse(&coupon.ListCouponsResponse{
+		Coupons:       couponProtos,
+		NextPageToken: nextPageToken,
+	}), nil
+}
+
+// UpdateCampaign changes the name, start time or total coupons of a campaign
+func (s *CouponServiceServer) UpdateCampaign(
+	ctx context.Context,
+	req *connect.Request[coupon.UpdateCampaignRequest],
+) (*connect.Response[coupon.UpdateCampaignResponse], error) {
+	// Validate request
+	c := req.Msg.Campaign
+	if c == nil || c.Id == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+	}
+	if len(req.Msg.UpdateMask.GetPaths()) == 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("update mask is required"))
+	}
+
+	// Collect the fields listed in the update mask
+	update := service.CampaignUpdate{ExpectedVersion: c.Version}
+	for _, path := range req.Msg.UpdateMask.GetPaths() {
+		switch path {
+		case "name":
+			update.Name = &c.Name
+		case "start_time":
+			if c.StartTime == nil {
+				return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("start time is required"))
+			}
+			startTime := c.StartTime.AsTime()
+			update.StartTime = &startTime
+		case "total_coupons":
+			totalCoupons := int(c.TotalCoupons)
+			update.TotalCoupons = &totalCoupons
+		default:
+			return nil, connect.NewError(connect.CodeInvalidArgument,
+				fmt.Errorf("field %q cannot be updated", path))
+		}
+	}
+
+	// Update campaign
+	campaign, err := s.campaignService.UpdateCampaign(ctx, c.Id, update)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCampaignNotFound):
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		case errors.Is(err, service.ErrVersionConflict):
+			return nil, connect.NewError(connect.CodeAborted, err)
+		case errors.Is(err, service.ErrDuplicateCampaign):
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		case errors.Is(err, service.ErrAlreadyStarted), errors.Is(err, service.ErrTotalBelowIssued),
+			errors.Is(err, service.ErrCodeSpaceTooSmall):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrPastStartTime),
+			errors.Is(err, service.ErrInvalidEndTime):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		default:
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+
+	return connect.NewResponse(&coupon.UpdateCampaignResponse{
+		Campaign: toCampaignProto(campaign, s.campaignService.Now()),
+	}), nil
+}
+
+// PauseCampaign stops issuance for a campaign without deleting it
+func (s *CouponServiceServer) PauseCampaign(
+	ctx context.Context,
+	req *connect.Request[coupon.PauseCampaignRequest],
+) (*connect.Response[coupon.PauseCampaignResponse], error) {
+	// Validate request
+	if req.Msg.CampaignId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+	}
+
+	// Pause campaign
+	campaign, err := s.campaignService.PauseCampaign(ctx, req.Msg.CampaignId)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&coupon.PauseCampaignResponse{
+		Campaign: toCampaignProto(campaign, s.campaignService.Now()),
+	}), nil
+}
+
+// ResumeCampaign restarts issuance for a paused campaign
+func (s *CouponServiceServer) ResumeCampaign(
+	ctx context.Context,
+	req *connect.Request[coupon.ResumeCampaignRequest],
+) (*connect.Response[coupon.ResumeCampaignResponse], error) {
+	// Validate request
+	if req.Msg.CampaignId == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+	}
+
+	// Resume campaign
+	campaign, err := s.campaignService.ResumeCampaign(ctx, req.Msg.CampaignId)
+	if err != nil {
+		if errors.Is(err, service.ErrCampaignNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	return connect.NewResponse(&coupon.ResumeCampaignResponse{
+		Campaign: toCampaignProto(campaign, s.campaignService.Now()),
+	}), nil
+}
+
+// maxReportedDuplicates bounds the duplicate codes echoed back by ImportCodes
+const maxReportedDuplicates = 100
+
+// ImportCodes adds a stream of partner-supplied codes to a campaign's code pool
+func (s *CouponServiceServer) ImportCodes(
+	ctx context.Context,
+	stream *connect.ClientStream[coupon.ImportCodesRequest],
+) (*connect.Response[coupon.ImportCodesResponse], error) {
+	var campaignID string
+	resp := &coupon.ImportCodesResponse{}
+
+	for stream.Receive() {
+		msg := stream.Msg()
+
+		// Validate request
+		if campaignID == "" {
+			if msg.CampaignId == "" {
+				return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+			}
+			campaignID = msg.CampaignId
+		} else if msg.CampaignId != "" && msg.CampaignId != campaignID {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("all messages must name the same campaign"))
+		}
+		if len(msg.Codes) == 0 {
+			continue
+		}
+
+		// Import batch
+		duplicates, err := s.campaignService.ImportCodes(ctx, campaignID, msg.Codes)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrCampaignNotFound):
+				return nil, connect.NewError(connect.CodeNotFound, err)
+			case errors.Is(err, service.ErrNotImportable):
+				return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+			case errors.Is(err, service.ErrInvalidImportCode), errors.Is(err, service.ErrInvalidRequest):
+				return nil, connect.NewError(connect.CodeInvalidArgument, err)
+			default:
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+		}
+
+		resp.Imported += int32(len(msg.Codes) - len(duplicates))
+		resp.Duplicates += int32(len(duplicates))
+		room := maxReportedDuplicates - len(resp.DuplicateCodes)
+		resp.DuplicateCodes = append(resp.DuplicateCodes, duplicates[:min(room, len(duplicates))]...)
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if campaignID == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("campaign ID is required"))
+	}
+
+	available, err := s.campaignService.AvailableCodes(ctx, campaignID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	resp.Available = int32(available)
+
+	return connect.NewResponse(resp), nil
+}
+
+// toCampaignProto converts a domain campaign to its proto model, with its status at now
+func toCampaignProto(c *domain.Campaign, now time.Time) *coupon.Campaign {
+	campaignProto := &coupon.Campaign{
+		Id:                c.ID,
+		Name:              c.Name,
+		TotalCoupons:      int32(c.TotalCoupons),
+		IssuedCoupons:     int32(c.IssuedCoupons),
+		StartTime:         timestamppb.New(c.StartTime),
+		CreatedAt:         timestamppb.New(c.CreatedAt),
+		MaxCouponsPerUser: int32(c.MaxCouponsPerUser),
+		Status:            toCampaignStatusProto(c.Status(now)),
+		Version:           c.Version,
+		CodeMode:          toCodeModeProto(c.CodeMode),
+		CodeTemplate:      toCodeTemplateProto(c.CodeTemplate),
+	}
+	if c.HasEndTime() {
+		campaignProto.EndTime = timestamppb.New(c.EndTime)
+	}
+
+	return campaignProto
+}
+
+// toCouponProto converts a domain coupon to its proto model
+func toCouponProto(c *domain.Coupon) *coupon.Coupon {
+	couponProto := &coupon.Coupon{
+		Code:       c.Code,
+		CampaignId: c.CampaignID,
+		IssuedAt:   timestamppb.New(c.IssuedAt),
+		UserId:     c.UserID,
+		Status:     toCouponStatusProto(c.Status),
+	}
+	if !c.RedeemedAt.IsZero() {
+		couponProto.RedeemedAt = timestamppb.New(c.RedeemedAt)
+	}
+
+	return couponProto
+}
+
+// toCampaignStatusProto converts a domain campaign status to its proto enum
+func toCampaignStatusProto(status domain.CampaignStatus) coupon.CampaignStatus {
+	switch status {
+	case domain.CampaignStatusScheduled:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_SCHEDULED
+	case domain.CampaignStatusActive:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_ACTIVE
+	case domain.CampaignStatusSoldOut:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_SOLD_OUT
+	case domain.CampaignStatusEnded:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_ENDED
+	case domain.CampaignStatusPaused:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_PAUSED
+	default:
+		return coupon.CampaignStatus_CAMPAIGN_STATUS_UNSPECIFIED
+	}
+}
+
+// fromCampaignStatusProto converts a proto campaign status to the domain status.
+// Unspecified maps to the empty status, which matches every campaign.
+func fromCampaignStatusProto(status coupon.CampaignStatus) domain.CampaignStatus {
+	switch status {
+	case coupon.CampaignStatus_CAMPAIGN_STATUS_SCHEDULED:
+		return domain.CampaignStatusScheduled
+	case coupon.CampaignStatus_CAMPAIGN_STATUS_ACTIVE:
+		return domain.CampaignStatusActive
+	case coupon.CampaignStatus_CAMPAIGN_STATUS_SOLD_OUT:
+		return domain.CampaignStatusSoldOut
+	case coupon.CampaignStatus_CAMPAIGN_STATUS_ENDED:
+		return domain.CampaignStatusEnded
+	case coupon.CampaignStatus_CAMPAIGN_STATUS_PAUSED:
+		return domain.CampaignStatusPaused
+	default:
+		return ""
+	}
+}
+
+// toCodeModeProto converts a domain code mode to its proto enum
+func toCodeModeProto(mode domain.CodeMode) coupon.CodeMode {
+	switch mode {
+	case domain.CodeModePermuted:
+		return coupon.CodeMode_CODE_MODE_PERMUTED
+	case domain.CodeModeSigned:
+		return coupon.CodeMode_CODE_MODE_SIGNED
+	case domain.CodeModeImported:
+		return coupon.CodeMode_CODE_MODE_IMPORTED
+	default:
+		return coupon.CodeMode_CODE_MODE_RANDOM
+	}
+}
+
+// fromCodeModeProto converts a proto code mode to the domain mode.
+// Unspecified maps to random codes.
+func fromCodeModeProto(mode coupon.CodeMode) domain.CodeMode {
+	switch mode {
+	case coupon.CodeMode_CODE_MODE_PERMUTED:
+		return domain.CodeModePermuted
+	case coupon.CodeMode_CODE_MODE_SIGNED:
+		return domain.CodeModeSigned
+	case coupon.CodeMode_CODE_MODE_IMPORTED:
+		return domain.CodeModeImported
+	default:
+		return domain.CodeModeRandom
+	}
+}
+
+// toCodeTemplateProto converts a domain code template to its proto model
+func toCodeTemplateProto(template *domain.CodeTemplate) *coupon.CodeTemplate {
+	if template == nil {
+		return nil
+	}
+
+	templateProto := &coupon.CodeTemplate{
+		Prefix:    template.Prefix,
+		Separator: template.Separator,
+	}
+	switch coupongen.Alphabet(template.Alphabet) {
+	case coupongen.AlphabetHangul:
+		templateProto.Alphabet = coupon.CodeAlphabet_CODE_ALPHABET_HANGUL
+	case coupongen.AlphabetLatin:
+		templateProto.Alphabet = coupon.CodeAlphabet_CODE_ALPHABET_LATIN
+	case coupongen.AlphabetDigits:
+		templateProto.Alphabet = coupon.CodeAlphabet_CODE_ALPHABET_DIGITS
+	}
+	for _, size := range template.Groups {
+		templateProto.Groups = append(templateProto.Groups, int32(size))
+	}
+
+	return templateProto
+}
+
+// fromCodeTemplateProto converts a proto code template to the domain model.
+// An unspecified alphabet is left empty for the service to default.
+func fromCodeTemplateProto(templateProto *coupon.CodeTemplate) *domain.CodeTemplate {
+	if templateProto == nil {
+		return nil
+	}
+
+	template := &domain.CodeTemplate{
+		Prefix:    templateProto.Prefix,
+		Separator: templateProto.Separator,
+	}
+	switch templateProto.Alphabet {
+	case coupon.CodeAlphabet_CODE_ALPHABET_HANGUL:
+		template.Alphabet = string(coupongen.AlphabetHangul)
+	case coupon.CodeAlphabet_CODE_ALPHABET_LATIN:
+		template.Alphabet = string(coupongen.AlphabetLatin)
+	case coupon.CodeAlphabet_CODE_ALPHABET_DIGITS:
+		template.Alphabet = string(coupongen.AlphabetDigits)
+	}
+	for _, size := range templateProto.Groups {
+		template.Groups = append(template.Groups, int(size))
+	}
+
+	return template
+}
+
+// toCouponStatusProto converts a domain coupon status to its proto enum
+func toCouponStatusProto(status domain.CouponStatus) coupon.CouponStatus {
+	switch status {
+	case domain.CouponStatusIssued:
+		return coupon.CouponStatus_COUPON_STATUS_ISSUED
+	case domain.CouponStatusRedeemed:
+		return coupon.CouponStatus_COUPON_STATUS_REDEEMED
+	case domain.CouponStatusExpired:
+		return coupon.CouponStatus_COUPON_STATUS_EXPIRED
+	case domain.CouponStatusRevoked:
+		return coupon.CouponStatus_COUPON_STATUS_REVOKED
+	default:
+		return coupon.CouponStatus_COUPON_STATUS_UNSPECIFIED
+	}
+}
+
+// toInvalidReasonProto converts a service invalid reason to its proto enum
+func toInvalidReasonProto(reason service.InvalidReason) coupon.CouponInvalidReason {
+	switch reason {
+	case service.InvalidReasonUnknownCode:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_UNKNOWN_CODE
+	case service.InvalidReasonRedeemed:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_REDEEMED
+	case service.InvalidReasonExpired:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_EXPIRED
+	case service.InvalidReasonRevoked:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_REVOKED
+	case service.InvalidReasonCampaignDeleted:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_CAMPAIGN_DELETED
+	case service.InvalidReasonMalformedCode:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_MALFORMED_CODE
+	default:
+		return coupon.CouponInvalidReason_COUPON_INVALID_REASON_UNSPECIFIED
+	}
+}
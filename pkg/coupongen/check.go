@@ -0,0 +1,136 @@
+// pkg/coupongen/check.go
+package coupongen
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+var (
+	// ErrMalformedCode is returned by Verify for a code that does not have
+	// the shape of the format's codes
+	ErrMalformedCode = errors.New("malformed coupon code")
+	// ErrCheckMismatch is returned by Verify for a code whose check character
+	// does not match the rest of the code, which usually means a typo
+	ErrCheckMismatch = errors.New("coupon code check character does not match")
+)
+
+// checker computes Luhn mod N check characters over an alphabet. It catches
+// every single mistyped symbol and every swap of two adjacent symbols except
+// a swap of the alphabet's first and last symbols.
+type checker struct {
+	symbols []rune
+	index   map[rune]int
+}
+
+func newChecker(symbols []rune) *checker {
+	index := make(map[rune]int, len(symbols))
+	for i, r := range symbols {
+		index[r] = i
+	}
+	return &checker{symbols: symbols, index: index}
+}
+
+// character returns the check character for a code's prefix and symbols
+func (c *checker) character(prefix, payload []rune) rune {
+	n := len(c.symbols)
+	return c.symbols[(n-c.sum(prefix, payload, 2)%n)%n]
+}
+
+// valid checks if the last symbol of code is the check character of the
+// prefix and the other symbols
+func (c *checker) valid(prefix, code []rune) bool {
+	return c.sum(prefix, code, 1)%len(c.symbols) == 0
+}
+
+// sum adds up the prefix and code from the right, doubling every other symbol
+// starting with the given factor and folding the doubled values back into
+// base n. The prefix counts as if it were part of the code, so a prefix made
+// of alphabet symbols gives the same check character as an unprefixed code.
+func (c *checker) sum(prefix, code []rune, factor int) int {
+	n := len(c.symbols)
+	sum := 0
+	for _, part := range [][]rune{code, prefix} {
+		for i := len(part) - 1; i >= 0; i-- {
+			addend := factor * c.value(part[i])
+			sum += addend/n + addend%n
+			factor = 3 - factor
+		}
+	}
+	return sum
+}
+
+// value maps a symbol to its position in the alphabet. Prefix characters
+// outside the alphabet are folded in by code point.
+func (c *checker) value(r rune) int {
+	if i, ok := c.index[r]; ok {
+		return i
+	}
+	return int(r) % len(c.symbols)
+}
+
+// Verifier checks codes of a format offline, so a typo can be refused before
+// looking the code up. It is safe for concurrent use.
+type Verifier struct {
+	*layout
+}
+
+// NewVerifier creates a verifier for a format with check characters
+func NewVerifier(format Format) (*Verifier, error) {
+	if !format.Check {
+		return nil, fmt.Errorf("%w: format has no check character", ErrInvalidFormat)
+	}
+
+	l, err := format.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{layout: l}, nil
+}
+
+// Verify checks that code has the format's shape and a matching check
+// character. It returns ErrMalformedCode or ErrCheckMismatch otherwise.
+func (v *Verifier) Verify(code string) error {
+	symbols, ok := v.parse(code)
+	if !ok || !slices.Contains(v.leading, symbols[0]) {
+		return ErrMalformedCode
+	}
+	for _, r := range symbols[1:] {
+		if _, ok := v.checker.index[r]; !ok {
+			return ErrMalformedCode
+		}
+	}
+
+	if !v.checker.valid(v.prefix, symbols) {
+		return ErrCheckMismatch
+	}
+	return nil
+}
+
+// Verify checks a code of DefaultFormat or SignedFormat offline, see
+// Verifier.Verify. It does not check the signature of signed codes; use
+// Keyring.Verify for that.
+func Verify(code string) error {
+	switch utf8.RuneCountInString(code) {
+	case DefaultFormat.Length + 1:
+		return defaultVerifier.Verify(code)
+	case SignedFormat.Length + 1:
+		return signedVerifier.Verify(code)
+	default:
+		return ErrMalformedCode
+	}
+}
+
+// defaultVerifier verifies codes of DefaultFormat
+var defaultVerifier = mustVerifier(DefaultFormat)
+
+// mustVerifier creates a verifier for one of the package's own formats
+func mustVerifier(format Format) *Verifier {
+	verifier, err := NewVerifier(format)
+	if err != nil {
+		panic(err)
+	}
+	return verifier
+}
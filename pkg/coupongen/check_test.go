@@ -0,0 +1,214 @@
+package coupongen
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkedFormats are formats whose codes carry a check character
+var checkedFormats = []struct {
+	name   string
+	format Format
+}{
+	{"default", DefaultFormat},
+	{"signed", SignedFormat},
+	{"symbol prefix", Format{Prefix: "가나", Symbols: KoreanChars + Digits, Length: 6, Check: true}},
+	{"other prefix", Format{Prefix: "EVT-", Symbols: Digits, Length: 6, Check: true, Groups: []int{3, 4}, Separator: " "}},
+	{"two symbols", Format{Symbols: "AB", Length: 8, Check: true}},
+}
+
+// newTestVerifier creates a generator and verifier for a format
+func newTestVerifier(t *testing.T, format Format) (*Generator, *Verifier) {
+	t.Helper()
+
+	gen, err := NewGenerator(format)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	verifier, err := NewVerifier(format)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	return gen, verifier
+}
+
+func TestCheckCatchesSubstitutions(t *testing.T) {
+	for _, tt := range checkedFormats {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, verifier := newTestVerifier(t, tt.format)
+			for i := 0; i < 50; i++ {
+				code, err := gen.Generate()
+				if err != nil {
+					t.Fatalf("generate: %v", err)
+				}
+				if err := verifier.Verify(code); err != nil {
+					t.Fatalf("verify %q: %v", code, err)
+				}
+
+				symbols, _ := verifier.parse(code)
+				for pos := range symbols {
+					alphabet := verifier.symbols
+					if pos == 0 {
+						alphabet = verifier.leading
+					}
+					for _, r := range alphabet {
+						if r == symbols[pos] {
+							continue
+						}
+						changed := slices.Clone(symbols)
+						changed[pos] = r
+						typo := renderUnchecked(verifier.layout, changed)
+						if err := verifier.Verify(typo); !errors.Is(err, ErrCheckMismatch) {
+							t.Fatalf("verify %q, a typo of %q: got %v, want ErrCheckMismatch", typo, code, err)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCatchesTranspositions(t *testing.T) {
+	for _, tt := range checkedFormats {
+		t.Run(tt.name, func(t *testing.T) {
+			_, verifier := newTestVerifier(t, tt.format)
+			n := len(verifier.symbols)
+
+			// Every pair of symbols at every pair of adjacent positions, the
+			// check character included
+			for pos := 0; pos < verifier.length; pos++ {
+				for _, a := range verifier.symbols {
+					for _, b := range verifier.symbols {
+						if pos == 0 && !(slices.Contains(verifier.leading, a) && slices.Contains(verifier.leading, b)) {
+							continue
+						}
+
+						payload := make([]rune, verifier.length)
+						for i := range payload {
+							payload[i] = verifier.leading[0]
+						}
+						payload[pos] = a
+						if pos+1 < len(payload) {
+							payload[pos+1] = b
+						}
+						symbols := append(payload, verifier.checker.character(verifier.prefix, payload))
+
+						x, y := verifier.checker.index[symbols[pos]], verifier.checker.index[symbols[pos+1]]
+						if x == y || min(x, y) == 0 && max(x, y) == n-1 {
+							// Luhn mod N cannot see a swap of the first and last symbols
+							continue
+						}
+
+						symbols[pos], symbols[pos+1] = symbols[pos+1], symbols[pos]
+						swapped := renderUnchecked(verifier.layout, symbols)
+						if err := verifier.Verify(swapped); !errors.Is(err, ErrCheckMismatch) {
+							t.Fatalf("verify %q with positions %d and %d swapped: got %v, want ErrCheckMismatch", swapped, pos, pos+1, err)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCheckWithPrefix(t *testing.T) {
+	// A prefix of alphabet symbols counts as part of the code
+	c := newChecker([]rune(Digits))
+	payload := []rune("123456")
+	if got, want := c.character([]rune("98"), payload), c.character(nil, []rune("98123456")); got != want {
+		t.Fatalf("check character with prefix = %q, without = %q", got, want)
+	}
+
+	format := Format{Prefix: "EVT-", Symbols: Digits, Length: 6, Check: true}
+	gen, verifier := newTestVerifier(t, format)
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !strings.HasPrefix(code, "EVT-") {
+		t.Fatalf("code %q lacks the prefix", code)
+	}
+	if err := verifier.Verify(code); err != nil {
+		t.Fatalf("verify %q: %v", code, err)
+	}
+
+	// The check character covers the prefix
+	format.Prefix = "EVU-"
+	other, err := NewVerifier(format)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if err := other.Verify("EVU-" + strings.TrimPrefix(code, "EVT-")); !errors.Is(err, ErrCheckMismatch) {
+		t.Fatalf("verify under another prefix: got %v, want ErrCheckMismatch", err)
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	format := Format{Prefix: "EVT-", Leading: "123", Symbols: Digits, Length: 6, Check: true, Groups: []int{3, 4}, Separator: " "}
+	gen, verifier := newTestVerifier(t, format)
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	for name, bad := range map[string]string{
+		"empty":              "",
+		"no prefix":          strings.TrimPrefix(code, "EVT-"),
+		"no separator":       strings.Replace(code, " ", "", 1),
+		"other separator":    strings.Replace(code, " ", "-", 1),
+		"too short":          code[:len(code)-1],
+		"too long":           code + "0",
+		"outside alphabet":   code[:len(code)-1] + "X",
+		"bad leading symbol": "EVT-0" + code[5:],
+	} {
+		if err := verifier.Verify(bad); !errors.Is(err, ErrMalformedCode) {
+			t.Fatalf("verify %s code %q: got %v, want ErrMalformedCode", name, bad, err)
+		}
+	}
+
+	if err := Verify("가12345"); !errors.Is(err, ErrMalformedCode) {
+		t.Fatalf("verify short code: got %v, want ErrMalformedCode", err)
+	}
+	if err := Verify("가123456789X"); !errors.Is(err, ErrMalformedCode) {
+		t.Fatalf("verify code outside the alphabet: got %v, want ErrMalformedCode", err)
+	}
+	if _, err := NewVerifier(Format{Symbols: Digits, Length: 6}); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("new verifier without check character: got %v, want ErrInvalidFormat", err)
+	}
+}
+
+func TestVerifyCheckMismatch(t *testing.T) {
+	gen, err := NewGenerator(DefaultFormat)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := Verify(code); err != nil {
+		t.Fatalf("verify %q: %v", code, err)
+	}
+
+	symbols := []rune(code)
+	check := symbols[len(symbols)-1]
+	for _, r := range DefaultFormat.Symbols {
+		if r == check {
+			continue
+		}
+		symbols[len(symbols)-1] = r
+		if err := Verify(string(symbols)); !errors.Is(err, ErrCheckMismatch) {
+			t.Fatalf("verify %q with check character %q: got %v, want ErrCheckMismatch", string(symbols), r, err)
+		}
+	}
+}
+
+// renderUnchecked renders symbols as a code of a layout, taking the last
+// symbol as the check character as it is
+func renderUnchecked(l *layout, symbols []rune) string {
+	unchecked := *l
+	unchecked.checker = nil
+	return unchecked.finish(symbols)
+}
@@ -2,51 +2,282 @@
 package coupongen
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strings"
-	"sync"
-	"time"
+	"unicode/utf8"
 )
 
-var (
-	numbers        = "0123456789"
-	koreanChars    = []rune("가나다라마바사아자차카타파하")
-	generatorMutex sync.Mutex
-	usedCodes      = make(map[string]struct{})
+const (
+	// Digits are the decimal digits
+	Digits = "0123456789"
+	// KoreanChars are the Hangul syllables used in coupon codes
+	KoreanChars = "가나다라마바사아자차카타파하"
 )
 
-// GenerateCode generates a unique coupon code with Korean characters and numbers
-func GenerateCode(length int) string {
-	if length <= 0 {
-		length = 10 // Default length
+// ErrInvalidFormat is returned by NewGenerator for an unusable format
+var ErrInvalidFormat = errors.New("invalid coupon code format")
+
+// Format describes the codes a Generator produces
+type Format struct {
+	// Leading lists the symbols allowed in the first position; empty means Symbols
+	Leading string
+	// Symbols lists the symbols allowed in every other position
+	Symbols string
+	// Length is the number of symbols in a code, not counting the check character
+	Length int
+	// Check appends a check character computed over Symbols, making codes one
+	// symbol longer than Length. Leading must then be a subset of Symbols.
+	Check bool
+
+	// Prefix is put in front of every code and covered by the check character
+	Prefix string
+	// Groups splits the symbols, check character included, into groups of the
+	// given sizes joined by Separator; empty means a single group
+	Groups []int
+	// Separator is put between groups and may not contain any symbol
+	Separator string
+}
+
+// DefaultFormat is 10 symbols of Korean syllables and digits, starting with a
+// Korean syllable, followed by a check character
+var DefaultFormat = Format{
+	Leading: KoreanChars,
+	Symbols: KoreanChars + Digits,
+	Length:  10,
+	Check:   true,
+}
+
+// alphabets validates the format and returns its leading and other symbols
+func (f Format) alphabets() ([]rune, []rune, error) {
+	if f.Leading == "" {
+		f.Leading = f.Symbols
+	}
+
+	leading := []rune(f.Leading)
+	symbols := []rune(f.Symbols)
+	if f.Length <= 0 || len(symbols) < 2 || len(leading) < 1 {
+		return nil, nil, ErrInvalidFormat
+	}
+	if len(symbols) > 256 || len(leading) > 256 {
+		return nil, nil, fmt.Errorf("%w: alphabets are limited to 256 symbols", ErrInvalidFormat)
+	}
+	if hasDuplicates(leading) || hasDuplicates(symbols) {
+		return nil, nil, fmt.Errorf("%w: alphabet repeats a symbol", ErrInvalidFormat)
+	}
+	if f.Check {
+		for _, r := range leading {
+			if !slices.Contains(symbols, r) {
+				return nil, nil, fmt.Errorf("%w: checked leading symbols must be in the symbol alphabet", ErrInvalidFormat)
+			}
+		}
 	}
 
-	generatorMutex.Lock()
-	defer generatorMutex.Unlock()
+	return leading, symbols, nil
+}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+// layout is a validated Format, ready to build and parse codes
+type layout struct {
+	leading   []rune
+	symbols   []rune
+	length    int
+	prefix    []rune
+	groups    []int
+	separator string
+	// checker is nil for formats without check characters
+	checker *checker
+}
 
-	for {
-		var sb strings.Builder
+// compile validates the format and prepares it for building codes
+func (f Format) compile() (*layout, error) {
+	leading, symbols, err := f.alphabets()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &layout{
+		leading:   leading,
+		symbols:   symbols,
+		length:    f.Length,
+		prefix:    []rune(f.Prefix),
+		separator: f.Separator,
+	}
+	if f.Check {
+		l.checker = newChecker(symbols)
+	}
+
+	total := l.length
+	if l.checker != nil {
+		total++
+	}
+	if len(f.Groups) == 0 {
+		l.groups = []int{total}
+	} else {
+		sum := 0
+		for _, size := range f.Groups {
+			if size <= 0 {
+				return nil, fmt.Errorf("%w: empty symbol group", ErrInvalidFormat)
+			}
+			sum += size
+		}
+		if sum != total {
+			return nil, fmt.Errorf("%w: groups hold %d symbols, codes have %d", ErrInvalidFormat, sum, total)
+		}
+		l.groups = slices.Clone(f.Groups)
+	}
+	if strings.ContainsAny(f.Separator, string(leading)+string(symbols)) {
+		return nil, fmt.Errorf("%w: separator contains a code symbol", ErrInvalidFormat)
+	}
+
+	return l, nil
+}
+
+// Space returns the number of different codes of the format, capped at 2^62
+func (f Format) Space() (uint64, error) {
+	l, err := f.compile()
+	if err != nil {
+		return 0, err
+	}
+	return codeSpace(len(l.leading), len(l.symbols), l.length), nil
+}
+
+// finish appends any check character to a code's symbols and renders the
+// code with its prefix and separators
+func (l *layout) finish(code []rune) string {
+	if l.checker != nil {
+		code = append(code, l.checker.character(l.prefix, code))
+	}
+	if len(l.prefix) == 0 && len(l.groups) == 1 {
+		return string(code)
+	}
 
-		// Ensure at least one Korean character
-		sb.WriteRune(koreanChars[r.Intn(len(koreanChars))])
+	var sb strings.Builder
+	sb.Grow(len(l.prefix)*utf8.UTFMax + len(code)*utf8.UTFMax + len(l.groups)*len(l.separator))
+	sb.WriteString(string(l.prefix))
+	for i, size := range l.groups {
+		if i > 0 {
+			sb.WriteString(l.separator)
+		}
+		sb.WriteString(string(code[:size]))
+		code = code[size:]
+	}
+	return sb.String()
+}
+
+// parse strips the prefix and separators from a rendered code and returns its
+// symbols, check character included. The symbols are not checked against the
+// alphabets.
+func (l *layout) parse(code string) ([]rune, bool) {
+	rest, ok := strings.CutPrefix(code, string(l.prefix))
+	if !ok {
+		return nil, false
+	}
 
-		// Fill the rest with a mix of numbers and Korean characters
-		for i := 1; i < length; i++ {
-			if r.Intn(2) == 0 {
-				sb.WriteByte(numbers[r.Intn(len(numbers))])
-			} else {
-				sb.WriteRune(koreanChars[r.Intn(len(koreanChars))])
+	var symbols []rune
+	for i, size := range l.groups {
+		if i > 0 {
+			if rest, ok = strings.CutPrefix(rest, l.separator); !ok {
+				return nil, false
 			}
 		}
+		for j := 0; j < size; j++ {
+			r, n := utf8.DecodeRuneInString(rest)
+			if n == 0 || r == utf8.RuneError {
+				return nil, false
+			}
+			symbols = append(symbols, r)
+			rest = rest[n:]
+		}
+	}
+
+	return symbols, rest == ""
+}
+
+// Generator creates random coupon codes from a cryptographically secure
+// source. Each campaign gets its own Generator with its own format.
+//
+// A Generator keeps no record of the codes it returned, so its memory use
+// does not grow with the number of coupons. Codes can repeat; callers rely
+// on the coupon store's unique code index to reject a repeat and draw again.
+// It is safe for concurrent use.
+type Generator struct {
+	*layout
+}
 
-		code := sb.String()
+// NewGenerator creates a generator for the given format
+func NewGenerator(format Format) (*Generator, error) {
+	l, err := format.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Generator{layout: l}, nil
+}
+
+// Generate draws a random code with every symbol chosen uniformly from its alphabet
+func (g *Generator) Generate() (string, error) {
+	src := newByteSource(rand.Reader, 2*g.length)
+
+	code := make([]rune, g.length, g.length+1)
+
+	first, err := src.index(len(g.leading))
+	if err != nil {
+		return "", err
+	}
+	code[0] = g.leading[first]
+
+	for i := 1; i < g.length; i++ {
+		next, err := src.index(len(g.symbols))
+		if err != nil {
+			return "", err
+		}
+		code[i] = g.symbols[next]
+	}
+
+	return g.finish(code), nil
+}
+
+// byteSource hands out random bytes read from r in batches
+type byteSource struct {
+	r   io.Reader
+	buf []byte
+	pos int
+}
+
+func newByteSource(r io.Reader, batch int) *byteSource {
+	return &byteSource{r: r, buf: make([]byte, batch), pos: batch}
+}
+
+// index returns a uniformly distributed number in [0, n) for n <= 256.
+// Bytes that would bias the result are rejected and redrawn.
+func (s *byteSource) index(n int) (int, error) {
+	limit := 256 - 256%n
+	for {
+		if s.pos == len(s.buf) {
+			if _, err := io.ReadFull(s.r, s.buf); err != nil {
+				return 0, err
+			}
+			s.pos = 0
+		}
+
+		b := int(s.buf[s.pos])
+		s.pos++
+		if b < limit {
+			return b % n, nil
+		}
+	}
+}
 
-		// Check if code already exists
-		if _, exists := usedCodes[code]; !exists {
-			usedCodes[code] = struct{}{}
-			return code
+// hasDuplicates checks if an alphabet lists a symbol more than once
+func hasDuplicates(alphabet []rune) bool {
+	seen := make(map[rune]struct{}, len(alphabet))
+	for _, r := range alphabet {
+		if _, exists := seen[r]; exists {
+			return true
 		}
+		seen[r] = struct{}{}
 	}
+	return false
 }
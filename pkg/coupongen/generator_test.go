@@ -0,0 +1,149 @@
+package coupongen
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// alphabet returns n distinct symbols
+func alphabet(n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteRune(rune(0x4E00 + i))
+	}
+	return sb.String()
+}
+
+func TestGenerateUsesAlphabets(t *testing.T) {
+	gen, err := NewGenerator(DefaultFormat)
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		code, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("generate: %v", err)
+		}
+		symbols := []rune(code)
+		if len(symbols) != DefaultFormat.Length+1 {
+			t.Fatalf("code %q has %d symbols, want %d", code, len(symbols), DefaultFormat.Length+1)
+		}
+		if !strings.ContainsRune(KoreanChars, symbols[0]) {
+			t.Fatalf("code %q starts with %q, not a leading symbol", code, symbols[0])
+		}
+		for _, r := range symbols[1:] {
+			if !strings.ContainsRune(DefaultFormat.Symbols, r) {
+				t.Fatalf("code %q holds %q, not in the alphabet", code, r)
+			}
+		}
+		if err := Verify(code); err != nil {
+			t.Fatalf("verify generated code %q: %v", code, err)
+		}
+	}
+}
+
+func TestFormatValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		valid  bool
+	}{
+		{"default", DefaultFormat, true},
+		{"leading defaults to symbols", Format{Symbols: "AB", Length: 1}, true},
+		{"256 symbols", Format{Symbols: alphabet(256), Length: 4}, true},
+		{"256 leading symbols", Format{Leading: alphabet(256), Symbols: "AB", Length: 4}, true},
+		{"zero length", Format{Symbols: Digits, Length: 0}, false},
+		{"negative length", Format{Symbols: Digits, Length: -1}, false},
+		{"single symbol", Format{Symbols: "A", Length: 4}, false},
+		{"257 symbols", Format{Symbols: alphabet(257), Length: 4}, false},
+		{"257 leading symbols", Format{Leading: alphabet(257), Symbols: "AB", Length: 4}, false},
+		{"repeated symbol", Format{Symbols: "ABA", Length: 4}, false},
+		{"repeated leading symbol", Format{Leading: "XX", Symbols: "AB", Length: 4}, false},
+		{"unchecked leading outside symbols", Format{Leading: "X", Symbols: "AB", Length: 4}, true},
+		{"checked leading outside symbols", Format{Leading: "X", Symbols: "AB", Length: 4, Check: true}, false},
+		{"groups", Format{Symbols: Digits, Length: 5, Check: true, Groups: []int{3, 3}, Separator: "-"}, true},
+		{"empty group", Format{Symbols: Digits, Length: 4, Groups: []int{4, 0}}, false},
+		{"groups too short", Format{Symbols: Digits, Length: 5, Check: true, Groups: []int{3, 2}}, false},
+		{"groups too long", Format{Symbols: Digits, Length: 4, Groups: []int{3, 2}}, false},
+		{"separator holds a symbol", Format{Symbols: Digits, Length: 4, Groups: []int{2, 2}, Separator: "-1"}, false},
+		{"separator holds a leading symbol", Format{Leading: "X", Symbols: Digits, Length: 4, Groups: []int{2, 2}, Separator: "X"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGenerator(tt.format)
+			if tt.valid && err != nil {
+				t.Fatalf("new generator: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidFormat) {
+				t.Fatalf("new generator: got error %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
+
+func TestIndexStaysInRange(t *testing.T) {
+	// Every byte value, many times over
+	all := make([]byte, 256*8)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	for _, n := range []int{1, 2, 3, 7, 14, 24, 100, 255, 256} {
+		src := newByteSource(bytes.NewReader(all), 16)
+		counts := make([]int, n)
+		for i := 0; i < (256-256%n)*8; i++ {
+			index, err := src.index(n)
+			if err != nil {
+				t.Fatalf("n=%d: index: %v", n, err)
+			}
+			if index < 0 || index >= n {
+				t.Fatalf("n=%d: index %d out of range", n, index)
+			}
+			counts[index]++
+		}
+
+		// Rejecting the biased bytes leaves every value equally likely
+		for index, count := range counts {
+			if count != counts[0] {
+				t.Fatalf("n=%d: index %d drawn %d times, index 0 %d times", n, index, count, counts[0])
+			}
+		}
+	}
+}
+
+func TestIndexRejectsBiasedBytes(t *testing.T) {
+	// For n=3 the bytes 255 would favour 0, so they are skipped
+	src := newByteSource(bytes.NewReader([]byte{255, 255, 4}), 1)
+	index, err := src.index(3)
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	if index != 1 {
+		t.Fatalf("index = %d, want 1 from the first unbiased byte", index)
+	}
+
+	// A source that runs dry is an error, not a biased value
+	src = newByteSource(bytes.NewReader([]byte{255}), 1)
+	if _, err := src.index(3); err == nil {
+		t.Fatal("index succeeded without an unbiased byte")
+	}
+}
+
+func TestGenerateRendersGroups(t *testing.T) {
+	gen, err := NewGenerator(Format{Prefix: "X-", Symbols: Digits, Length: 7, Check: true, Groups: []int{4, 4}, Separator: " "})
+	if err != nil {
+		t.Fatalf("new generator: %v", err)
+	}
+	code, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if utf8.RuneCountInString(code) != len("X-1234 5678") || !strings.HasPrefix(code, "X-") || code[6] != ' ' {
+		t.Fatalf("code %q is not rendered as X-dddd dddd", code)
+	}
+}
@@ -0,0 +1,153 @@
+// pkg/coupongen/permutation.go
+package coupongen
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+const (
+	// KeySize is the length of the keys created by NewKey
+	KeySize = 32
+
+	// feistelRounds is the number of rounds of the Feistel network
+	feistelRounds = 10
+
+	// maxDomain caps the number of serials a Permutation maps, keeping all
+	// arithmetic within uint64 for formats with enormous code spaces
+	maxDomain = 1 << 62
+)
+
+var (
+	// ErrInvalidKey is returned by NewPermutation for an empty key
+	ErrInvalidKey = errors.New("invalid permutation key")
+	// ErrCodeSpaceExhausted is returned for a serial beyond the code space
+	ErrCodeSpaceExhausted = errors.New("coupon code space exhausted")
+)
+
+// NewKey creates a random key for NewPermutation
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Permutation maps sequential serials to codes of a format through a keyed
+// permutation of the format's code space. Every serial below Size gets a
+// different code, so codes are unique without any lookup, while consecutive
+// serials give codes that look unrelated to anyone without the key.
+//
+// The permutation is a balanced Feistel network keyed with HMAC-SHA256 over
+// the smallest even number of bits covering the code space. Results outside
+// the code space are encrypted again (cycle walking) until they fall inside.
+// It is safe for concurrent use.
+type Permutation struct {
+	*layout
+	key []byte
+
+	// size is the number of codes the permutation maps to
+	size uint64
+	// halfBits is the width of each Feistel half
+	halfBits uint
+}
+
+// NewPermutation creates a permutation of the format's codes under key
+func NewPermutation(format Format, key []byte) (*Permutation, error) {
+	l, err := format.compile()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 {
+		return nil, ErrInvalidKey
+	}
+
+	size := codeSpace(len(l.leading), len(l.symbols), l.length)
+
+	// Cover size with two halves of equal width, at least one bit each
+	halfBits := uint(1)
+	if width := uint(bits.Len64(size - 1)); width > 2 {
+		halfBits = (width + 1) / 2
+	}
+
+	return &Permutation{
+		layout:   l,
+		key:      append([]byte(nil), key...),
+		size:     size,
+		halfBits: halfBits,
+	}, nil
+}
+
+// Size returns the number of distinct codes the permutation hands out
+func (p *Permutation) Size() uint64 {
+	return p.size
+}
+
+// Code returns the code for a serial. Different serials below Size always
+// give different codes; larger serials fail with ErrCodeSpaceExhausted.
+func (p *Permutation) Code(serial uint64) (string, error) {
+	if serial >= p.size {
+		return "", ErrCodeSpaceExhausted
+	}
+
+	// Every value below size stays below size after enough rounds, since
+	// the network permutes the whole 2*halfBits domain
+	x := p.encrypt(serial)
+	for x >= p.size {
+		x = p.encrypt(x)
+	}
+
+	return p.encode(x), nil
+}
+
+// encrypt applies the Feistel network to a value of 2*halfBits bits
+func (p *Permutation) encrypt(x uint64) uint64 {
+	mask := uint64(1)<<p.halfBits - 1
+	left, right := x>>p.halfBits, x&mask
+
+	mac := hmac.New(sha256.New, p.key)
+	var block [9]byte
+	for round := 0; round < feistelRounds; round++ {
+		block[0] = byte(round)
+		binary.BigEndian.PutUint64(block[1:], right)
+
+		mac.Reset()
+		mac.Write(block[:])
+		f := binary.BigEndian.Uint64(mac.Sum(nil)) & mask
+
+		left, right = right, left^f
+	}
+
+	return left<<p.halfBits | right
+}
+
+// encode writes x in mixed radix: the leading alphabet for the first symbol
+// and the symbol alphabet for the others, then renders it as a code
+func (p *Permutation) encode(x uint64) string {
+	digits := make([]rune, p.length, p.length+1)
+	for i := p.length - 1; i > 0; i-- {
+		digits[i] = p.symbols[x%uint64(len(p.symbols))]
+		x /= uint64(len(p.symbols))
+	}
+	digits[0] = p.leading[x%uint64(len(p.leading))]
+
+	return p.finish(digits)
+}
+
+// codeSpace returns the number of codes of a format, capped at maxDomain
+func codeSpace(leading, symbols, length int) uint64 {
+	size := uint64(leading)
+	for i := 1; i < length; i++ {
+		if size > math.MaxUint64/uint64(symbols) {
+			return maxDomain
+		}
+		size *= uint64(symbols)
+	}
+	return min(size, maxDomain)
+}
@@ -0,0 +1,130 @@
+package coupongen
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPermutationIsBijection(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		size   uint64
+	}{
+		{"size 1", Format{Leading: "A", Symbols: "AB", Length: 1}, 1},
+		{"size 2", Format{Symbols: "AB", Length: 1}, 2},
+		{"size 3", Format{Symbols: "ABC", Length: 1}, 3},
+		{"size 4", Format{Symbols: "AB", Length: 2}, 4},
+		{"3 bits", Format{Symbols: "AB", Length: 3}, 8},
+		{"5 bits", Format{Symbols: "AB", Length: 5}, 32},
+		{"7 bits", Format{Symbols: "AB", Length: 7}, 128},
+		{"9 bits, cycle walking", Format{Leading: "ABC", Symbols: Digits, Length: 3}, 300},
+		{"checked and grouped", Format{Symbols: Digits, Length: 3, Check: true, Groups: []int{2, 2}, Separator: "-"}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			perm, err := NewPermutation(tt.format, []byte("test key"))
+			if err != nil {
+				t.Fatalf("new permutation: %v", err)
+			}
+			if perm.Size() != tt.size {
+				t.Fatalf("size = %d, want %d", perm.Size(), tt.size)
+			}
+			if space, err := tt.format.Space(); err != nil || space != tt.size {
+				t.Fatalf("format space = %d, %v, want %d", space, err, tt.size)
+			}
+
+			var verifier *Verifier
+			if tt.format.Check {
+				if verifier, err = NewVerifier(tt.format); err != nil {
+					t.Fatalf("new verifier: %v", err)
+				}
+			}
+
+			seen := make(map[string]uint64, tt.size)
+			for serial := uint64(0); serial < tt.size; serial++ {
+				code, err := perm.Code(serial)
+				if err != nil {
+					t.Fatalf("code of serial %d: %v", serial, err)
+				}
+				if previous, exists := seen[code]; exists {
+					t.Fatalf("serials %d and %d both map to %q", previous, serial, code)
+				}
+				seen[code] = serial
+
+				if verifier != nil {
+					if err := verifier.Verify(code); err != nil {
+						t.Fatalf("verify code %q: %v", code, err)
+					}
+				}
+			}
+
+			if _, err := perm.Code(tt.size); !errors.Is(err, ErrCodeSpaceExhausted) {
+				t.Fatalf("code of serial %d: got %v, want ErrCodeSpaceExhausted", tt.size, err)
+			}
+		})
+	}
+}
+
+func TestPermutationKeys(t *testing.T) {
+	format := Format{Symbols: Digits, Length: 8}
+	codes := func(key []byte) []string {
+		t.Helper()
+
+		perm, err := NewPermutation(format, key)
+		if err != nil {
+			t.Fatalf("new permutation: %v", err)
+		}
+		var codes []string
+		for serial := uint64(0); serial < 20; serial++ {
+			code, err := perm.Code(serial)
+			if err != nil {
+				t.Fatalf("code of serial %d: %v", serial, err)
+			}
+			codes = append(codes, code)
+		}
+		return codes
+	}
+
+	first := codes([]byte("key one"))
+	if again := codes([]byte("key one")); !slices.Equal(first, again) {
+		t.Fatalf("same key gave %v, then %v", first, again)
+	}
+	if other := codes([]byte("key two")); slices.Equal(first, other) {
+		t.Fatalf("different keys both gave %v", first)
+	}
+
+	random, err := NewKey()
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+	if len(random) != KeySize || bytes.Equal(random, make([]byte, KeySize)) {
+		t.Fatalf("new key %x is not %d random bytes", random, KeySize)
+	}
+
+	if _, err := NewPermutation(format, nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("new permutation without a key: got %v, want ErrInvalidKey", err)
+	}
+	if _, err := NewPermutation(Format{Symbols: "A", Length: 4}, []byte("key one")); !errors.Is(err, ErrInvalidFormat) {
+		t.Fatalf("new permutation of a bad format: got %v, want ErrInvalidFormat", err)
+	}
+}
+
+func TestPermutationCapsHugeSpaces(t *testing.T) {
+	perm, err := NewPermutation(Format{Symbols: alphabet(256), Length: 20}, []byte("test key"))
+	if err != nil {
+		t.Fatalf("new permutation: %v", err)
+	}
+	if perm.Size() != maxDomain {
+		t.Fatalf("size = %d, want the cap %d", perm.Size(), uint64(maxDomain))
+	}
+	if _, err := perm.Code(maxDomain - 1); err != nil {
+		t.Fatalf("code of the last serial: %v", err)
+	}
+	if _, err := perm.Code(maxDomain); !errors.Is(err, ErrCodeSpaceExhausted) {
+		t.Fatalf("code beyond the cap: got %v, want ErrCodeSpaceExhausted", err)
+	}
+}
@@ -0,0 +1,285 @@
+// pkg/coupongen/signed.go
+package coupongen
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxKeyID is the largest key ID a signed code can carry
+	MaxKeyID = 1<<keyIDBits - 1
+	// MaxSignedSerial is the largest serial a signed code can carry
+	MaxSignedSerial = 1<<serialBits - 1
+
+	keyIDBits  = 5
+	serialBits = 30
+
+	// headerSymbols encode the key ID and serial, macSymbols the truncated HMAC
+	headerSymbols = 8
+	macSymbols    = 7
+
+	// signatureContext separates signed-code MACs from any other use of the keys
+	signatureContext = "coupongen signed code v1"
+)
+
+var (
+	// ErrUnknownKey is returned when a signed code names a key ID the keyring lacks
+	ErrUnknownKey = errors.New("coupon code signed with an unknown key")
+	// ErrBadSignature is returned when a signed code's HMAC does not match
+	ErrBadSignature = errors.New("coupon code signature does not match")
+)
+
+// SignedFormat is the shape of signed codes: a Korean syllable followed by
+// Korean syllables and digits, 15 symbols in all plus a check character. The
+// first 8 symbols carry the key ID and serial, the last 7 a truncated HMAC.
+var SignedFormat = Format{
+	Leading: KoreanChars,
+	Symbols: KoreanChars + Digits,
+	Length:  headerSymbols + macSymbols,
+	Check:   true,
+}
+
+// SigningKey is a secret for signing codes, identified by the ID embedded in
+// every code it signs
+type SigningKey struct {
+	ID     int
+	Secret []byte
+}
+
+// Keyring holds the keys signed codes are checked against. The key with the
+// highest ID signs new codes, so keys are rotated by adding one with a higher
+// ID and removing the old key once its codes no longer need to verify.
+// It is safe for concurrent use.
+type Keyring struct {
+	keys    map[int][]byte
+	current int
+}
+
+// NewKeyring creates a keyring from the given keys
+func NewKeyring(keys ...SigningKey) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: no signing keys", ErrInvalidKey)
+	}
+
+	keyring := &Keyring{keys: make(map[int][]byte, len(keys)), current: -1}
+	for _, key := range keys {
+		if key.ID < 0 || key.ID > MaxKeyID {
+			return nil, fmt.Errorf("%w: key ID %d is outside 0-%d", ErrInvalidKey, key.ID, MaxKeyID)
+		}
+		if len(key.Secret) < 16 {
+			return nil, fmt.Errorf("%w: key %d is shorter than 16 bytes", ErrInvalidKey, key.ID)
+		}
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("%w: key ID %d is listed twice", ErrInvalidKey, key.ID)
+		}
+		keyring.keys[key.ID] = append([]byte(nil), key.Secret...)
+		keyring.current = max(keyring.current, key.ID)
+	}
+
+	return keyring, nil
+}
+
+// ReadKeys parses a key file: one key per line as "<id> <base64 secret>".
+// Blank lines and lines starting with # are ignored.
+func ReadKeys(r io.Reader) ([]SigningKey, error) {
+	var keys []SigningKey
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("key file line %d: want \"<id> <base64 secret>\"", line)
+		}
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("key file line %d: invalid key ID: %w", line, err)
+		}
+		secret, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("key file line %d: invalid secret: %w", line, err)
+		}
+
+		keys = append(keys, SigningKey{ID: id, Secret: secret})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// LoadKeyring creates a keyring from a key file, see ReadKeys
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys, err := ReadKeys(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyring(keys...)
+}
+
+// CurrentKeyID returns the ID of the key that signs new codes
+func (k *Keyring) CurrentKeyID() int {
+	return k.current
+}
+
+// KeyIDs returns the IDs of all keys in the keyring in ascending order
+func (k *Keyring) KeyIDs() []int {
+	ids := make([]int, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Sign returns the signed code for a campaign's serial using the current key.
+// Serials above MaxSignedSerial fail with ErrCodeSpaceExhausted.
+func (k *Keyring) Sign(campaignID string, serial uint64) (string, error) {
+	if serial > MaxSignedSerial {
+		return "", ErrCodeSpaceExhausted
+	}
+
+	header := uint64(k.current)<<serialBits | serial
+	mac := signature(k.keys[k.current], k.current, serial, campaignID)
+	return signedEncoding.encode(header, mac), nil
+}
+
+// SignedCode is what a verified signed code carries
+type SignedCode struct {
+	KeyID  int
+	Serial uint64
+}
+
+// Verify checks that code was signed for campaignID by a key in the keyring.
+// It returns ErrMalformedCode or ErrCheckMismatch for codes that are not
+// signed codes or were mistyped, ErrUnknownKey for a key the keyring does not
+// hold, and ErrBadSignature for a forged code or one of another campaign.
+func (k *Keyring) Verify(campaignID, code string) (*SignedCode, error) {
+	if err := signedVerifier.Verify(code); err != nil {
+		return nil, err
+	}
+
+	header, mac := signedEncoding.decode(code)
+	keyID := int(header >> serialBits)
+	serial := header & MaxSignedSerial
+
+	secret, exists := k.keys[keyID]
+	if !exists {
+		return nil, ErrUnknownKey
+	}
+
+	want := signature(secret, keyID, serial, campaignID)
+	if !hmac.Equal(binary.BigEndian.AppendUint64(nil, mac), binary.BigEndian.AppendUint64(nil, want)) {
+		return nil, ErrBadSignature
+	}
+
+	return &SignedCode{KeyID: keyID, Serial: serial}, nil
+}
+
+// signature computes the truncated HMAC of a signed code, reduced to what
+// macSymbols can carry
+func signature(secret []byte, keyID int, serial uint64, campaignID string) uint64 {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signatureContext))
+	mac.Write([]byte{byte(keyID)})
+	mac.Write(binary.BigEndian.AppendUint64(nil, serial))
+	mac.Write([]byte(campaignID))
+
+	sum := binary.BigEndian.Uint64(mac.Sum(nil))
+	return sum % signedEncoding.macSpace
+}
+
+// signedLayout writes the header and MAC of a signed code in mixed radix
+type signedLayout struct {
+	leading  []rune
+	symbols  []rune
+	index    map[rune]int
+	checker  *checker
+	macSpace uint64
+}
+
+// signedEncoding and signedVerifier are built once from SignedFormat
+var (
+	signedEncoding = newSignedLayout(SignedFormat)
+	signedVerifier = mustVerifier(SignedFormat)
+)
+
+func newSignedLayout(format Format) *signedLayout {
+	leading, symbols, err := format.alphabets()
+	if err != nil {
+		panic(err)
+	}
+
+	// The header must hold every key ID and serial
+	if codeSpace(len(leading), len(symbols), headerSymbols) <= uint64(MaxKeyID)<<serialBits|MaxSignedSerial {
+		panic("coupongen: signed code header is too short")
+	}
+
+	layout := &signedLayout{
+		leading:  leading,
+		symbols:  symbols,
+		checker:  newChecker(symbols),
+		macSpace: codeSpace(len(symbols), len(symbols), macSymbols),
+	}
+	layout.index = layout.checker.index
+	return layout
+}
+
+func (l *signedLayout) encode(header, mac uint64) string {
+	n := uint64(len(l.symbols))
+	code := make([]rune, headerSymbols+macSymbols, headerSymbols+macSymbols+1)
+
+	for i := headerSymbols - 1; i > 0; i-- {
+		code[i] = l.symbols[header%n]
+		header /= n
+	}
+	code[0] = l.leading[header]
+
+	for i := headerSymbols + macSymbols - 1; i >= headerSymbols; i-- {
+		code[i] = l.symbols[mac%n]
+		mac /= n
+	}
+
+	return string(append(code, l.checker.character(nil, code)))
+}
+
+// decode reverses encode for a code that passed signedVerifier
+func (l *signedLayout) decode(code string) (uint64, uint64) {
+	runes := []rune(code)
+	n := uint64(len(l.symbols))
+
+	header := uint64(slices.Index(l.leading, runes[0]))
+	for _, r := range runes[1:headerSymbols] {
+		header = header*n + uint64(l.index[r])
+	}
+
+	var mac uint64
+	for _, r := range runes[headerSymbols : headerSymbols+macSymbols] {
+		mac = mac*n + uint64(l.index[r])
+	}
+
+	return header, mac
+}
@@ -0,0 +1,203 @@
+package coupongen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testKey returns a signing key whose secret repeats seed
+func testKey(id int, seed string) SigningKey {
+	return SigningKey{ID: id, Secret: []byte(strings.Repeat(seed, 16))}
+}
+
+// mustKeyring creates a keyring or fails the test
+func mustKeyring(t *testing.T, keys ...SigningKey) *Keyring {
+	t.Helper()
+
+	keyring, err := NewKeyring(keys...)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	return keyring
+}
+
+// mustSign signs a serial or fails the test
+func mustSign(t *testing.T, keyring *Keyring, campaignID string, serial uint64) string {
+	t.Helper()
+
+	code, err := keyring.Sign(campaignID, serial)
+	if err != nil {
+		t.Fatalf("sign serial %d: %v", serial, err)
+	}
+	return code
+}
+
+func TestSignAndVerify(t *testing.T) {
+	keyring := mustKeyring(t, testKey(7, "a"))
+
+	seen := make(map[string]bool)
+	for _, serial := range []uint64{0, 1, 2, 1000, MaxSignedSerial} {
+		code := mustSign(t, keyring, "campaign-1", serial)
+		if err := Verify(code); err != nil {
+			t.Fatalf("check %q offline: %v", code, err)
+		}
+		if seen[code] {
+			t.Fatalf("serial %d repeats code %q", serial, code)
+		}
+		seen[code] = true
+
+		signed, err := keyring.Verify("campaign-1", code)
+		if err != nil {
+			t.Fatalf("verify %q: %v", code, err)
+		}
+		if signed.KeyID != 7 || signed.Serial != serial {
+			t.Fatalf("verified %q as key %d serial %d, want key 7 serial %d", code, signed.KeyID, signed.Serial, serial)
+		}
+	}
+
+	if _, err := keyring.Sign("campaign-1", MaxSignedSerial+1); !errors.Is(err, ErrCodeSpaceExhausted) {
+		t.Fatalf("sign serial beyond MaxSignedSerial: got %v, want ErrCodeSpaceExhausted", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := mustKeyring(t, testKey(1, "a"))
+	rotated := mustKeyring(t, testKey(2, "b"), testKey(1, "a"))
+	retired := mustKeyring(t, testKey(2, "b"))
+
+	if rotated.CurrentKeyID() != 2 {
+		t.Fatalf("current key = %d, want the highest ID 2", rotated.CurrentKeyID())
+	}
+	if ids := rotated.KeyIDs(); len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("key IDs = %v, want [1 2]", ids)
+	}
+
+	oldCode := mustSign(t, old, "campaign-1", 5)
+	newCode := mustSign(t, rotated, "campaign-1", 5)
+	if oldCode == newCode {
+		t.Fatalf("both keys signed serial 5 as %q", oldCode)
+	}
+
+	if signed, err := rotated.Verify("campaign-1", oldCode); err != nil || signed.KeyID != 1 {
+		t.Fatalf("verify code of the old key after rotation: got %+v, %v", signed, err)
+	}
+	if signed, err := rotated.Verify("campaign-1", newCode); err != nil || signed.KeyID != 2 {
+		t.Fatalf("verify code of the new key: got %+v, %v", signed, err)
+	}
+	if _, err := retired.Verify("campaign-1", oldCode); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("verify code of a removed key: got %v, want ErrUnknownKey", err)
+	}
+	if _, err := old.Verify("campaign-1", newCode); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("verify code of a newer key: got %v, want ErrUnknownKey", err)
+	}
+
+	// The same ID with another secret is a forgery, not an unknown key
+	if _, err := mustKeyring(t, testKey(1, "c")).Verify("campaign-1", oldCode); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("verify with another secret: got %v, want ErrBadSignature", err)
+	}
+}
+
+func TestVerifyRejectsForgeries(t *testing.T) {
+	keyring := mustKeyring(t, testKey(MaxKeyID, "a"))
+	code := mustSign(t, keyring, "campaign-1", 42)
+
+	if _, err := keyring.Verify("campaign-2", code); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("verify code of another campaign: got %v, want ErrBadSignature", err)
+	}
+
+	// Tampered codes keep a valid check character, so only the signature
+	// can catch them
+	header, mac := signedEncoding.decode(code)
+	for name, tampered := range map[string]string{
+		"serial":    signedEncoding.encode(header+1, mac),
+		"signature": signedEncoding.encode(header, (mac+1)%signedEncoding.macSpace),
+	} {
+		if err := Verify(tampered); err != nil {
+			t.Fatalf("check tampered %s offline: %v", name, err)
+		}
+		if _, err := keyring.Verify("campaign-1", tampered); !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("verify code with tampered %s: got %v, want ErrBadSignature", name, err)
+		}
+	}
+
+	if _, err := keyring.Verify("campaign-1", "가나다"); !errors.Is(err, ErrMalformedCode) {
+		t.Fatalf("verify short code: got %v, want ErrMalformedCode", err)
+	}
+	typo := []rune(code)
+	if typo[3] = '0'; string(typo) == code {
+		typo[3] = '1'
+	}
+	if _, err := keyring.Verify("campaign-1", string(typo)); !errors.Is(err, ErrCheckMismatch) {
+		t.Fatalf("verify mistyped code: got %v, want ErrCheckMismatch", err)
+	}
+}
+
+func TestNewKeyringRejectsBadKeys(t *testing.T) {
+	for name, keys := range map[string][]SigningKey{
+		"no keys":      nil,
+		"negative ID":  {testKey(-1, "a")},
+		"ID too large": {testKey(MaxKeyID+1, "a")},
+		"short secret": {{ID: 1, Secret: []byte("short")}},
+		"repeated ID":  {testKey(1, "a"), testKey(1, "b")},
+	} {
+		if _, err := NewKeyring(keys...); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("new keyring with %s: got %v, want ErrInvalidKey", name, err)
+		}
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	keys, err := ReadKeys(strings.NewReader(`
+# rotated in March
+1 YWFhYWFhYWFhYWFhYWFhYQ==
+
+  2   YmJiYmJiYmJiYmJiYmJiYg==
+`))
+	if err != nil {
+		t.Fatalf("read keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != 1 || keys[1].ID != 2 ||
+		!bytes.Equal(keys[0].Secret, testKey(1, "a").Secret) || !bytes.Equal(keys[1].Secret, testKey(2, "b").Secret) {
+		t.Fatalf("read keys %+v", keys)
+	}
+
+	for name, input := range map[string]string{
+		"missing secret": "1\n",
+		"extra field":    "1 YWFhYWFhYWFhYWFhYWFhYQ== extra\n",
+		"bad ID":         "one YWFhYWFhYWFhYWFhYWFhYQ==\n",
+		"bad secret":     "1 not-base64!\n",
+	} {
+		if _, err := ReadKeys(strings.NewReader(input)); err == nil {
+			t.Fatalf("read keys with %s: no error", name)
+		}
+	}
+}
+
+func TestLoadKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("3 YWFhYWFhYWFhYWFhYWFhYQ==\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+
+	keyring, err := LoadKeyring(path)
+	if err != nil {
+		t.Fatalf("load keyring: %v", err)
+	}
+	if keyring.CurrentKeyID() != 3 {
+		t.Fatalf("current key = %d, want 3", keyring.CurrentKeyID())
+	}
+
+	if _, err := LoadKeyring(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("load missing key file: got %v, want os.ErrNotExist", err)
+	}
+	if err := os.WriteFile(path, []byte("# no keys\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	if _, err := LoadKeyring(path); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("load empty key file: got %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,111 @@
+// pkg/coupongen/template.go
+package coupongen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LatinChars are upper-case Latin letters and digits without the easily
+// confused I, O, 0 and 1
+const LatinChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const (
+	// maxPrefixLength and maxSeparatorLength bound the fixed parts of a template, in characters
+	maxPrefixLength    = 16
+	maxSeparatorLength = 3
+	// maxTemplateSymbols bounds the symbols of a templated code, check character included
+	maxTemplateSymbols = 32
+)
+
+// Alphabet names the symbols a Template draws from
+type Alphabet string
+
+const (
+	// AlphabetHangul is Korean syllables and digits, starting with a syllable
+	AlphabetHangul Alphabet = "hangul"
+	// AlphabetLatin is LatinChars
+	AlphabetLatin Alphabet = "latin"
+	// AlphabetDigits is decimal digits only
+	AlphabetDigits Alphabet = "digits"
+)
+
+// Template describes what a campaign's codes look like. Templated codes
+// always end with a check character.
+type Template struct {
+	// Prefix is put in front of every code
+	Prefix string
+	// Alphabet selects the code symbols; empty means AlphabetHangul
+	Alphabet Alphabet
+	// Groups are the sizes of the symbol groups, check character included;
+	// empty means a single group as long as DefaultFormat's codes
+	Groups []int
+	// Separator is put between groups
+	Separator string
+}
+
+// WithDefaults returns the template with empty settings filled in
+func (t Template) WithDefaults() Template {
+	if t.Alphabet == "" {
+		t.Alphabet = AlphabetHangul
+	}
+	if len(t.Groups) == 0 {
+		t.Groups = []int{DefaultFormat.Length + 1}
+	}
+	return t
+}
+
+// Format validates the template and returns the format it describes
+func (t Template) Format() (Format, error) {
+	t = t.WithDefaults()
+
+	var format Format
+	switch t.Alphabet {
+	case AlphabetHangul:
+		format.Leading = KoreanChars
+		format.Symbols = KoreanChars + Digits
+	case AlphabetLatin:
+		format.Symbols = LatinChars
+	case AlphabetDigits:
+		format.Symbols = Digits
+	default:
+		return Format{}, fmt.Errorf("%w: unknown alphabet %q", ErrInvalidFormat, t.Alphabet)
+	}
+
+	if utf8.RuneCountInString(t.Prefix) > maxPrefixLength || !printable(t.Prefix) {
+		return Format{}, fmt.Errorf("%w: prefix must be at most %d printable characters without spaces", ErrInvalidFormat, maxPrefixLength)
+	}
+	if utf8.RuneCountInString(t.Separator) > maxSeparatorLength || !printable(t.Separator) {
+		return Format{}, fmt.Errorf("%w: separator must be at most %d printable characters without spaces", ErrInvalidFormat, maxSeparatorLength)
+	}
+
+	total := 0
+	for _, size := range t.Groups {
+		total += size
+	}
+	if total < 2 || total > maxTemplateSymbols {
+		return Format{}, fmt.Errorf("%w: codes must have 2 to %d symbols", ErrInvalidFormat, maxTemplateSymbols)
+	}
+
+	format.Length = total - 1
+	format.Check = true
+	format.Prefix = t.Prefix
+	format.Groups = t.Groups
+	format.Separator = t.Separator
+
+	// Catch bad groups and separators here rather than at first use
+	if _, err := format.compile(); err != nil {
+		return Format{}, err
+	}
+	return format, nil
+}
+
+// printable checks that s has no spaces or control characters, which would
+// not survive being typed in or copied
+func printable(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsPrint(r) || unicode.IsSpace(r)
+	}) < 0
+}
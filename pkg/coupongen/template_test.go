@@ -0,0 +1,138 @@
+package coupongen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTemplateRendersGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+		// shape has an x for every symbol
+		shape string
+		space uint64
+	}{
+		{"defaults", Template{}, "xxxxxxxxxxx", 14 * pow(24, 9)},
+		{"latin groups", Template{Prefix: "SPRING-", Alphabet: AlphabetLatin, Groups: []int{4, 4, 4}, Separator: "-"}, "SPRING-xxxx-xxxx-xxxx", pow(32, 11)},
+		{"digits", Template{Alphabet: AlphabetDigits, Groups: []int{3, 3}, Separator: "::"}, "xxx::xxx", pow(10, 5)},
+		{"no separator", Template{Alphabet: AlphabetDigits, Groups: []int{2, 2}}, "xxxx", pow(10, 3)},
+		{"hangul groups", Template{Prefix: "봄", Groups: []int{1, 5}, Separator: "."}, "봄x.xxxxx", 14 * pow(24, 4)},
+		{"shortest", Template{Alphabet: AlphabetDigits, Groups: []int{2}}, "xx", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := tt.template.Format()
+			if err != nil {
+				t.Fatalf("format: %v", err)
+			}
+			if space, err := format.Space(); err != nil || space != tt.space {
+				t.Fatalf("space = %d, %v, want %d", space, err, tt.space)
+			}
+
+			gen, verifier := newTestVerifier(t, format)
+			for i := 0; i < 20; i++ {
+				code, err := gen.Generate()
+				if err != nil {
+					t.Fatalf("generate: %v", err)
+				}
+				if !matchesShape(code, tt.shape) {
+					t.Fatalf("code %q does not look like %q", code, tt.shape)
+				}
+				if err := verifier.Verify(code); err != nil {
+					t.Fatalf("verify %q: %v", code, err)
+				}
+
+				symbols, ok := verifier.parse(code)
+				if !ok || renderUnchecked(verifier.layout, symbols) != code {
+					t.Fatalf("parsed %q as %q, which renders differently", code, string(symbols))
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		template Template
+		valid    bool
+	}{
+		{"longest prefix", Template{Prefix: strings.Repeat("가", maxPrefixLength)}, true},
+		{"prefix too long", Template{Prefix: strings.Repeat("가", maxPrefixLength+1)}, false},
+		{"prefix with space", Template{Prefix: "NEW YEAR"}, false},
+		{"prefix with control character", Template{Prefix: "NEW\aYEAR"}, false},
+		{"longest separator", Template{Groups: []int{5, 6}, Separator: "-~-"}, true},
+		{"separator too long", Template{Groups: []int{5, 6}, Separator: "-~-~"}, false},
+		{"separator with space", Template{Groups: []int{5, 6}, Separator: " "}, false},
+		{"separator holds a symbol", Template{Alphabet: AlphabetDigits, Groups: []int{5, 6}, Separator: "0"}, false},
+		{"separator holds a Latin symbol", Template{Alphabet: AlphabetLatin, Groups: []int{5, 6}, Separator: "A"}, false},
+		{"separator outside the alphabet", Template{Alphabet: AlphabetLatin, Groups: []int{5, 6}, Separator: "0"}, true},
+		{"most symbols", Template{Groups: []int{maxTemplateSymbols}}, true},
+		{"too many symbols", Template{Groups: []int{maxTemplateSymbols, 1}}, false},
+		{"fewest symbols", Template{Groups: []int{2}}, true},
+		{"too few symbols", Template{Groups: []int{1}}, false},
+		{"empty group", Template{Groups: []int{5, 0, 6}}, false},
+		{"negative group", Template{Groups: []int{12, -1}}, false},
+		{"unknown alphabet", Template{Alphabet: "emoji"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := tt.template.Format()
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidFormat) {
+					t.Fatalf("format: got error %v, want %v", err, ErrInvalidFormat)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("format: %v", err)
+			}
+			if _, err := NewGenerator(format); err != nil {
+				t.Fatalf("new generator for an accepted template: %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplateWithDefaults(t *testing.T) {
+	template := Template{}.WithDefaults()
+	if template.Alphabet != AlphabetHangul || len(template.Groups) != 1 || template.Groups[0] != DefaultFormat.Length+1 {
+		t.Fatalf("template defaults to %+v", template)
+	}
+
+	format, err := Template{}.Format()
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	if format.Leading != DefaultFormat.Leading || format.Symbols != DefaultFormat.Symbols ||
+		format.Length != DefaultFormat.Length || !format.Check {
+		t.Fatalf("default template gives %+v, want DefaultFormat", format)
+	}
+}
+
+// matchesShape checks if code is shape with every x replaced by one symbol
+func matchesShape(code, shape string) bool {
+	codeRunes, shapeRunes := []rune(code), []rune(shape)
+	if len(codeRunes) != len(shapeRunes) {
+		return false
+	}
+	for i, r := range shapeRunes {
+		if r != 'x' && codeRunes[i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// pow returns base to the power of exp
+func pow(base, exp uint64) uint64 {
+	result := uint64(1)
+	for i := uint64(0); i < exp; i++ {
+		result *= base
+	}
+	return result
+}
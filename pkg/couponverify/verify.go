@@ -0,0 +1,51 @@
+// pkg/couponverify/verify.go
+
+// Package couponverify checks signed coupon codes offline. A point of sale
+// loads the same key file as the coupon server and can then tell genuine
+// codes of a campaign from mistyped or forged ones without calling the server.
+//
+// Verification only proves that the server signed the code. Whether the
+// coupon was already redeemed can only be answered by the server.
+package couponverify
+
+import (
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
+)
+
+// Errors returned by Verify
+var (
+	ErrMalformedCode = coupongen.ErrMalformedCode
+	ErrCheckMismatch = coupongen.ErrCheckMismatch
+	ErrUnknownKey    = coupongen.ErrUnknownKey
+	ErrBadSignature  = coupongen.ErrBadSignature
+)
+
+// Verifier checks signed codes against a set of keys.
+// It is safe for concurrent use.
+type Verifier struct {
+	keyring *coupongen.Keyring
+}
+
+// New creates a verifier that accepts codes signed by any of the given keys
+func New(keys ...coupongen.SigningKey) (*Verifier, error) {
+	keyring, err := coupongen.NewKeyring(keys...)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{keyring: keyring}, nil
+}
+
+// Load creates a verifier from a key file in the format read by coupongen.ReadKeys
+func Load(path string) (*Verifier, error) {
+	keyring, err := coupongen.LoadKeyring(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{keyring: keyring}, nil
+}
+
+// Verify checks that code is a genuine signed code of the campaign and
+// returns the key ID and serial it carries
+func (v *Verifier) Verify(campaignID, code string) (*coupongen.SignedCode, error) {
+	return v.keyring.Verify(campaignID, code)
+}
@@ -0,0 +1,63 @@
+package couponverify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rpranjan11/coupon-issuance-system/pkg/coupongen"
+)
+
+func TestVerify(t *testing.T) {
+	key := coupongen.SigningKey{ID: 4, Secret: []byte("0123456789abcdef")}
+	keyring, err := coupongen.NewKeyring(key)
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	code, err := keyring.Sign("campaign-1", 9)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte("4 MDEyMzQ1Njc4OWFiY2RlZg==\n"), 0o600); err != nil {
+		t.Fatalf("write key file: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load verifier: %v", err)
+	}
+	created, err := New(key)
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	for name, verifier := range map[string]*Verifier{"loaded": loaded, "created": created} {
+		signed, err := verifier.Verify("campaign-1", code)
+		if err != nil || signed.KeyID != 4 || signed.Serial != 9 {
+			t.Fatalf("%s verifier: verify %q: got %+v, %v", name, code, signed, err)
+		}
+		if _, err := verifier.Verify("campaign-2", code); !errors.Is(err, ErrBadSignature) {
+			t.Fatalf("%s verifier: verify code of another campaign: got %v, want ErrBadSignature", name, err)
+		}
+		if _, err := verifier.Verify("campaign-1", "가나다"); !errors.Is(err, ErrMalformedCode) {
+			t.Fatalf("%s verifier: verify malformed code: got %v, want ErrMalformedCode", name, err)
+		}
+	}
+
+	other, err := New(coupongen.SigningKey{ID: 5, Secret: key.Secret})
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if _, err := other.Verify("campaign-1", code); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("verify code of a key the verifier lacks: got %v, want ErrUnknownKey", err)
+	}
+
+	if _, err := New(); err == nil {
+		t.Fatal("new verifier without keys: no error")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("load missing key file: no error")
+	}
+}